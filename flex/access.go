@@ -0,0 +1,88 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Role is the kind of user interface element a node presents to
+// assistive technology.
+type Role int
+
+// Possible values of Role.
+const (
+	RoleNone   Role = iota // no semantics; its children are still exported
+	RoleGroup              // a container of other elements
+	RoleText               // static text
+	RoleImage              // a picture
+	RoleButton             // a control that performs an action
+)
+
+var roleNames = [...]string{
+	RoleNone:   "none",
+	RoleGroup:  "group",
+	RoleText:   "text",
+	RoleImage:  "image",
+	RoleButton: "button",
+}
+
+func (r Role) String() string {
+	return enumName(roleNames[:], "Role", int(r))
+}
+
+// An Accessible is a widget.Class that describes its nodes to
+// assistive technology. Classes that are not Accessible are exported
+// with RoleNone, except Flex containers, which are RoleGroup.
+type Accessible interface {
+	// Role returns the role of n.
+	Role(n *widget.Node) Role
+
+	// Name returns the text read out for n, such as a label or the
+	// caption of a button.
+	Name(n *widget.Node) string
+}
+
+// AccessNode is a snapshot of one node of a laid-out widget tree, for
+// a platform accessibility bridge.
+type AccessNode struct {
+	Node *widget.Node
+	Role Role
+	Name string
+
+	// Bounds is the node's Rect in the coordinates of the root
+	// passed to Accessibility, offset by its origin.
+	Bounds image.Rectangle
+
+	// Children are the node's children in visual order.
+	Children []*AccessNode
+}
+
+// Accessibility returns a snapshot of the tree rooted at n, which must
+// have been laid out. Bounds are in screen coordinates, given that n's
+// parent is painted at origin; pass image.Point{} for a root painted at
+// the top-left of a window.
+//
+// The children of a Flex are listed in its VisualOrder.
+func Accessibility(n *widget.Node, origin image.Point) *AccessNode {
+	a := &AccessNode{
+		Node:   n,
+		Bounds: n.Rect.Add(origin),
+	}
+	if _, ok := n.Class.(*flexClass); ok {
+		a.Role = RoleGroup
+	}
+	children := VisualChildren(n)
+	if acc, ok := n.Class.(Accessible); ok {
+		a.Role = acc.Role(n)
+		a.Name = acc.Name(n)
+	}
+	for _, c := range children {
+		a.Children = append(a.Children, Accessibility(c, a.Bounds.Min))
+	}
+	return a
+}
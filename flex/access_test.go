@@ -0,0 +1,75 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+type buttonClass struct {
+	widget.LeafClassEmbed
+	name string
+}
+
+func (k *buttonClass) Role(n *widget.Node) Role   { return RoleButton }
+func (k *buttonClass) Name(n *widget.Node) string { return k.name }
+
+func TestAccessibility(t *testing.T) {
+	inner := NewFlex()
+	inner.Direction = RowReverse
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := &widget.Node{Class: &buttonClass{name: "OK"}}
+	inner.AppendChild(a)
+	inner.AppendChild(b)
+
+	root := NewFlex()
+	root.Direction = Column
+	root.SafeArea = Insets{Top: 5, Left: 5}
+	root.AppendChild(&inner.Node)
+
+	root.Class.Measure(&root.Node, nil)
+	root.Rect = image.Rect(0, 0, 100, 100)
+	root.Class.Layout(&root.Node, nil)
+
+	got := Accessibility(&root.Node, image.Pt(20, 30))
+	if got.Role != RoleGroup || got.Bounds != image.Rect(20, 30, 120, 130) {
+		t.Errorf("root: role %v, bounds %v", got.Role, got.Bounds)
+	}
+	if len(got.Children) != 1 {
+		t.Fatalf("root has %d children, want 1", len(got.Children))
+	}
+	g := got.Children[0]
+	if g.Node != &inner.Node || g.Role != RoleGroup {
+		t.Errorf("inner: node %p, role %v", g.Node, g.Role)
+	}
+	if len(g.Children) != 2 {
+		t.Fatalf("inner has %d children, want 2", len(g.Children))
+	}
+	// RowReverse puts b first on screen.
+	if c := g.Children[0]; c.Node != b || c.Role != RoleButton || c.Name != "OK" {
+		t.Errorf("first child: %+v", c)
+	}
+	if c := g.Children[1]; c.Node != a || c.Role != RoleNone {
+		t.Errorf("second child: %+v", c)
+	}
+	want := a.Rect.Add(inner.Rect.Min).Add(image.Pt(20, 30))
+	if c := g.Children[1]; c.Bounds != want {
+		t.Errorf("second child bounds %v, want %v", c.Bounds, want)
+	}
+}
+
+func TestRoleString(t *testing.T) {
+	if s := RoleButton.String(); s != "button" {
+		t.Errorf("RoleButton.String() = %q", s)
+	}
+	if s := Role(7).String(); s != "Role(7)" {
+		t.Errorf("Role(7).String() = %q", s)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package anim provides easings, tweens and a ticker for animating
+// widgets from the paint loop.
+//
+// An animation is a function of the time of the frame being painted.
+// A Ticker holds the animations that are running, and an event loop
+// steps it before every paint:
+//
+//	tk := &anim.Ticker{Repaint: func() { w.Send(paint.Event{}) }}
+//	...
+//	case paint.Event:
+//		tk.Step(time.Now())
+//		root.Class.Paint(root, theme, buf.RGBA(), image.Point{})
+//
+// While any animation is running, Step calls Repaint so that another
+// frame follows.
+package anim
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// An Ease maps the linear progress of an animation, in [0, 1], to the
+// fraction of the distance travelled.
+type Ease func(t float64) float64
+
+// Linear moves at a constant speed.
+func Linear(t float64) float64 { return t }
+
+// InOut accelerates from rest and decelerates to rest.
+func InOut(t float64) float64 { return t * t * (3 - 2*t) }
+
+// In accelerates from rest.
+func In(t float64) float64 { return t * t }
+
+// Out decelerates to rest.
+func Out(t float64) float64 { return t * (2 - t) }
+
+// CubicBezier returns the Ease of the CSS cubic-bezier timing function
+// with control points (x1, y1) and (x2, y2). x1 and x2 must be in
+// [0, 1].
+func CubicBezier(x1, y1, x2, y2 float64) Ease {
+	bezier := func(a, b, t float64) float64 {
+		// The curve from 0 to 1 through control points a and b.
+		s := 1 - t
+		return 3*s*s*t*a + 3*s*t*t*b + t*t*t
+	}
+	return func(x float64) float64 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		// Find t with bezier(x1, x2, t) = x by bisection; the curve
+		// is monotonic in x.
+		lo, hi := 0.0, 1.0
+		t := x
+		for i := 0; i < 32; i++ {
+			if bezier(x1, x2, t) < x {
+				lo = t
+			} else {
+				hi = t
+			}
+			t = (lo + hi) / 2
+		}
+		return bezier(y1, y2, t)
+	}
+}
+
+// A Tween is the timing of an animation that runs for Duration from
+// Start.
+type Tween struct {
+	// Start is the time the animation starts. If zero, it is set by
+	// the first call to Progress.
+	Start time.Time
+
+	// Duration is the length of the animation. If zero, the
+	// animation is done as soon as it starts.
+	Duration time.Duration
+
+	// Ease is applied to the progress of the animation. If nil,
+	// InOut is used.
+	Ease Ease
+}
+
+// Progress returns the eased progress of the animation at time now,
+// from 0 at Start to 1 once Duration has passed.
+func (tw *Tween) Progress(now time.Time) float64 {
+	if tw.Start.IsZero() {
+		tw.Start = now
+	}
+	p := 1.0
+	if tw.Duration > 0 {
+		p = float64(now.Sub(tw.Start)) / float64(tw.Duration)
+	}
+	if p >= 1 {
+		return 1
+	}
+	if p < 0 {
+		p = 0
+	}
+	ease := tw.Ease
+	if ease == nil {
+		ease = InOut
+	}
+	return ease(p)
+}
+
+// Done reports whether the animation has finished at time now.
+func (tw *Tween) Done(now time.Time) bool {
+	return !tw.Start.IsZero() && now.Sub(tw.Start) >= tw.Duration
+}
+
+// Lerp returns the value a fraction f of the way from a to b.
+func Lerp(a, b, f float64) float64 {
+	return a + (b-a)*f
+}
+
+// LerpInt returns the integer a fraction f of the way from a to b,
+// rounded to the nearest.
+func LerpInt(a, b int, f float64) int {
+	return a + int(math.Floor(float64(b-a)*f+0.5))
+}
+
+// LerpPoint returns the point a fraction f of the way from a to b.
+func LerpPoint(a, b image.Point, f float64) image.Point {
+	return image.Point{LerpInt(a.X, b.X, f), LerpInt(a.Y, b.Y, f)}
+}
+
+// LerpRect returns the rectangle whose corners are a fraction f of
+// the way from those of a to those of b.
+func LerpRect(a, b image.Rectangle, f float64) image.Rectangle {
+	return image.Rectangle{Min: LerpPoint(a.Min, b.Min, f), Max: LerpPoint(a.Max, b.Max, f)}
+}
+
+// A Ticker steps the animations that are running from the paint loop.
+// The zero Ticker is ready to use.
+type Ticker struct {
+	// Repaint, if non-nil, is called by Step while any animation is
+	// still running, so that another frame is scheduled.
+	Repaint func()
+
+	steps []func(now time.Time) bool
+}
+
+// Add starts an animation. Each Step calls step with the time of the
+// frame, until step reports that it is no longer running. Add may be
+// called from a step, such as to chain another animation when one
+// finishes; the new step runs from the next Step.
+func (tk *Ticker) Add(step func(now time.Time) (running bool)) {
+	tk.steps = append(tk.steps, step)
+}
+
+// Step advances every animation to time now, and reports whether any
+// is still running.
+func (tk *Ticker) Step(now time.Time) bool {
+	// Steps may Add to tk.steps, so iterate over a snapshot.
+	steps := tk.steps
+	tk.steps = nil
+	running := steps[:0]
+	for _, step := range steps {
+		if step(now) {
+			running = append(running, step)
+		}
+	}
+	for i := len(running); i < len(steps); i++ {
+		steps[i] = nil
+	}
+	tk.steps = append(running, tk.steps...)
+	if len(tk.steps) == 0 {
+		return false
+	}
+	if tk.Repaint != nil {
+		tk.Repaint()
+	}
+	return true
+}
+
+// Running reports whether any animation is running.
+func (tk *Ticker) Running() bool {
+	return len(tk.steps) > 0
+}
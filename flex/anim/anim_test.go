@@ -0,0 +1,148 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anim
+
+import (
+	"image"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEases(t *testing.T) {
+	eases := map[string]Ease{
+		"Linear":      Linear,
+		"InOut":       InOut,
+		"In":          In,
+		"Out":         Out,
+		"CubicBezier": CubicBezier(0.25, 0.1, 0.25, 1),
+	}
+	for name, ease := range eases {
+		if got := ease(0); got != 0 {
+			t.Errorf("%s(0) = %g", name, got)
+		}
+		if got := ease(1); got != 1 {
+			t.Errorf("%s(1) = %g", name, got)
+		}
+		prev := 0.0
+		for i := 1; i <= 10; i++ {
+			v := ease(float64(i) / 10)
+			if v < prev {
+				t.Errorf("%s decreases at %g", name, float64(i)/10)
+			}
+			prev = v
+		}
+	}
+	// A cubic-bezier with control points on the diagonal is linear.
+	lin := CubicBezier(1.0/3, 1.0/3, 2.0/3, 2.0/3)
+	for _, x := range []float64{0.1, 0.5, 0.9} {
+		if got := lin(x); math.Abs(got-x) > 1e-6 {
+			t.Errorf("linear bezier(%g) = %g", x, got)
+		}
+	}
+}
+
+func TestTween(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tw := Tween{Duration: time.Second, Ease: Linear}
+	if tw.Done(t0) {
+		t.Error("done before starting")
+	}
+	for _, test := range []struct {
+		d    time.Duration
+		want float64
+		done bool
+	}{
+		{0, 0, false},
+		{time.Second / 4, 0.25, false},
+		{time.Second, 1, true},
+		{2 * time.Second, 1, true},
+	} {
+		now := t0.Add(test.d)
+		if got := tw.Progress(now); got != test.want {
+			t.Errorf("Progress(+%v) = %g, want %g", test.d, got, test.want)
+		}
+		if got := tw.Done(now); got != test.done {
+			t.Errorf("Done(+%v) = %t", test.d, got)
+		}
+	}
+
+	if got := (&Tween{}).Progress(t0); got != 1 {
+		t.Errorf("zero Duration Progress = %g, want 1", got)
+	}
+}
+
+func TestLerp(t *testing.T) {
+	if got := Lerp(2, 4, 0.25); got != 2.5 {
+		t.Errorf("Lerp = %g", got)
+	}
+	got := LerpRect(image.Rect(0, 0, 10, 10), image.Rect(10, 0, 30, 10), 0.5)
+	if want := image.Rect(5, 0, 20, 10); got != want {
+		t.Errorf("LerpRect = %v, want %v", got, want)
+	}
+}
+
+func TestTicker(t *testing.T) {
+	repaints := 0
+	tk := &Ticker{Repaint: func() { repaints++ }}
+	t0 := time.Unix(1000, 0)
+	var a, b []float64
+	ta := &Tween{Duration: time.Second, Ease: Linear}
+	tb := &Tween{Duration: 2 * time.Second, Ease: Linear}
+	tk.Add(func(now time.Time) bool {
+		a = append(a, ta.Progress(now))
+		return !ta.Done(now)
+	})
+	tk.Add(func(now time.Time) bool {
+		b = append(b, tb.Progress(now))
+		return !tb.Done(now)
+	})
+
+	for i, want := range []bool{true, true, false, false} {
+		if got := tk.Step(t0.Add(time.Duration(i) * time.Second)); got != want {
+			t.Errorf("Step %d = %t, want %t", i, got, want)
+		}
+	}
+	if len(a) != 2 || a[1] != 1 {
+		t.Errorf("a stepped %v", a)
+	}
+	if len(b) != 3 || b[1] != 0.5 || b[2] != 1 {
+		t.Errorf("b stepped %v", b)
+	}
+	if repaints != 2 || tk.Running() {
+		t.Errorf("repaints=%d, Running=%t", repaints, tk.Running())
+	}
+}
+
+// TestTickerChain checks that an animation added by a running step is
+// kept, and runs from the next Step.
+func TestTickerChain(t *testing.T) {
+	var tk Ticker
+	t0 := time.Unix(1000, 0)
+	var ran []string
+	tk.Add(func(now time.Time) bool {
+		ran = append(ran, "a")
+		tk.Add(func(now time.Time) bool {
+			ran = append(ran, "b")
+			return false
+		})
+		return false
+	})
+	tk.Add(func(now time.Time) bool {
+		ran = append(ran, "c")
+		return now.Before(t0.Add(time.Second))
+	})
+
+	if !tk.Step(t0) {
+		t.Error("first Step = false, want the chained animation running")
+	}
+	if tk.Step(t0.Add(time.Second)) || tk.Running() {
+		t.Error("second Step left an animation running")
+	}
+	if got := strings.Join(ran, ""); got != "accb" {
+		t.Errorf("steps ran in order %q, want accb", got)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// A Baseliner is a widget.Class that knows where the baselines of its
+// text are. Flex aligns the first baselines of items in a Row whose
+// alignment is AlignItemBaseline, and the last baselines of those whose
+// alignment is AlignItemLastBaseline.
+//
+// Classes that are not Baseliners are given a synthesized baseline at
+// their bottom edge, so that a box sits on the baseline of the text
+// next to it.
+type Baseliner interface {
+	// FirstBaseline returns the baseline of the first line of text
+	// in n, in pixels from the top of n.
+	FirstBaseline(n *widget.Node, t *widget.Theme) int
+
+	// LastBaseline returns the baseline of the last line of text in
+	// n, if n were laid out at the given size, in pixels from the
+	// top of n. It must not modify n or its descendants, as it is
+	// asked before n is given its Rect, and by Plan.
+	LastBaseline(n *widget.Node, t *widget.Theme, size image.Point) int
+}
@@ -0,0 +1,128 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// baselineClass is a leaf of a fixed size with a fixed baseline.
+type baselineClass struct {
+	widget.LeafClassEmbed
+	size     image.Point
+	baseline int
+}
+
+func (k *baselineClass) Measure(n *widget.Node, t *widget.Theme) { n.MeasuredSize = k.size }
+
+func (k *baselineClass) FirstBaseline(n *widget.Node, t *widget.Theme) int { return k.baseline }
+func (k *baselineClass) LastBaseline(n *widget.Node, t *widget.Theme, size image.Point) int {
+	return k.baseline
+}
+
+func TestBaseline(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignItem = AlignItemBaseline
+	fl.AlignContent = AlignContentStart
+	a := &widget.Node{Class: &baselineClass{size: size(20, 30), baseline: 25}}
+	b := &widget.Node{Class: &baselineClass{size: size(20, 10), baseline: 5}}
+	c := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(10)).Node
+	d := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(10)).Node
+	for _, n := range []*widget.Node{a, b, c, d} {
+		fl.AppendChild(n)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 60, 100)
+	fl.Class.Layout(&fl.Node, nil)
+
+	// The first line is 25px of ascent plus 5px of descent.
+	for _, test := range []struct {
+		name string
+		n    *widget.Node
+		want image.Rectangle
+	}{
+		{"a", a, image.Rect(0, 0, 20, 30)},
+		{"b", b, image.Rect(20, 20, 40, 30)},
+		{"c", c, image.Rect(40, 15, 60, 25)},
+		{"d", d, image.Rect(0, 30, 20, 40)},
+	} {
+		if test.n.Rect != test.want {
+			t.Errorf("%s.Rect=%v, want %v", test.name, test.n.Rect, test.want)
+		}
+	}
+
+	// Columns have no baselines to align.
+	fl.Direction = Column
+	fl.Wrap = NoWrap
+	fl.Class.Layout(&fl.Node, nil)
+	if want := image.Rect(0, 30, 20, 40); b.Rect != want {
+		t.Errorf("column b.Rect=%v, want %v", b.Rect, want)
+	}
+}
+
+// textClass is a leaf whose last baseline is lastFromBottom pixels
+// above the bottom of its Rect.
+type textClass struct {
+	widget.LeafClassEmbed
+	size           image.Point
+	first          int
+	lastFromBottom int
+}
+
+func (k *textClass) Measure(n *widget.Node, t *widget.Theme) { n.MeasuredSize = k.size }
+
+func (k *textClass) FirstBaseline(n *widget.Node, t *widget.Theme) int { return k.first }
+func (k *textClass) LastBaseline(n *widget.Node, t *widget.Theme, size image.Point) int {
+	return size.Y - k.lastFromBottom
+}
+
+func TestLastBaseline(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignContent = AlignContentStart
+	label := &widget.Node{
+		Class:      &textClass{size: size(20, 40), first: 10, lastFromBottom: 5},
+		LayoutData: LayoutData{Align: AlignItemLastBaseline},
+	}
+	input := &widget.Node{
+		Class:      &textClass{size: size(20, 20), first: 15, lastFromBottom: 5},
+		LayoutData: LayoutData{Align: AlignItemLastBaseline},
+	}
+	first := &widget.Node{
+		Class:      &baselineClass{size: size(20, 10), baseline: 5},
+		LayoutData: LayoutData{Align: AlignItemBaseline},
+	}
+	for _, n := range []*widget.Node{label, input, first} {
+		fl.AppendChild(n)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 60, 100)
+	fl.Class.Layout(&fl.Node, nil)
+
+	for _, test := range []struct {
+		name string
+		n    *widget.Node
+		want image.Rectangle
+	}{
+		{"label", label, image.Rect(0, 0, 20, 40)},
+		{"input", input, image.Rect(20, 20, 40, 40)},
+		{"first", first, image.Rect(40, 0, 60, 10)}, // in its own group
+	} {
+		if test.n.Rect != test.want {
+			t.Errorf("%s.Rect=%v, want %v", test.name, test.n.Rect, test.want)
+		}
+	}
+
+	d, err := ParseItemStyle("align-self: last baseline")
+	if err != nil || d.Align != AlignItemLastBaseline {
+		t.Errorf("ParseItemStyle: %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package build constructs flex widget trees declaratively.
+//
+// A tree is written as nested calls, each returning a fully wired
+// *flex.Flex:
+//
+//	root := build.Row(build.Gap(8),
+//		build.Of(icon),
+//		build.Of(build.Column(
+//			build.Of(title),
+//			build.Of(body, build.Grow(1)),
+//		), build.Grow(1)),
+//	)
+//
+// Every child added with Of gets a flex.LayoutData, so item
+// properties are never left on a node that is not a flex item.
+package build
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/crawshaw/exp/flex"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// An Option sets a container property of a Flex or adds a child to it.
+// Options are applied in order.
+type Option func(fl *flex.Flex)
+
+// An ItemOption sets a property of a child's flex.LayoutData.
+type ItemOption func(d *flex.LayoutData)
+
+// Row returns a Flex with direction flex.Row, configured by opts.
+func Row(opts ...Option) *flex.Flex {
+	return newFlex(flex.Row, opts)
+}
+
+// Column returns a Flex with direction flex.Column, configured by opts.
+func Column(opts ...Option) *flex.Flex {
+	return newFlex(flex.Column, opts)
+}
+
+func newFlex(dir flex.Direction, opts []Option) *flex.Flex {
+	fl := flex.NewFlex()
+	fl.Direction = dir
+	for _, opt := range opts {
+		opt(fl)
+	}
+	return fl
+}
+
+// Of adds child to the container as a flex item whose LayoutData is
+// built from opts, applied over the container's DefaultLayoutData.
+//
+// The child is a *widget.Node or a *flex.Flex, such as one returned by
+// Row or Column. Of panics if child has any other type or already has
+// a parent.
+func Of(child interface{}, opts ...ItemOption) Option {
+	var n *widget.Node
+	switch c := child.(type) {
+	case *widget.Node:
+		n = c
+	case *flex.Flex:
+		n = &c.Node
+	default:
+		panic(fmt.Sprintf("build: child of type %T is not a *widget.Node or *flex.Flex", child))
+	}
+	return func(fl *flex.Flex) {
+		if n.Parent != nil {
+			panic("build: child already has a parent")
+		}
+		var d flex.LayoutData
+		if fl.DefaultLayoutData != nil {
+			d = *fl.DefaultLayoutData
+		}
+		for _, opt := range opts {
+			opt(&d)
+		}
+		n.LayoutData = d
+		fl.AppendChild(n)
+	}
+}
+
+// Reverse reverses the direction of the main axis, turning a Row into
+// flex.RowReverse and a Column into flex.ColumnReverse.
+func Reverse() Option {
+	return func(fl *flex.Flex) {
+		switch fl.Direction {
+		case flex.Row:
+			fl.Direction = flex.RowReverse
+		case flex.RowReverse:
+			fl.Direction = flex.Row
+		case flex.Column:
+			fl.Direction = flex.ColumnReverse
+		case flex.ColumnReverse:
+			fl.Direction = flex.Column
+		}
+	}
+}
+
+// Wrap sets how items are broken into lines.
+func Wrap(w flex.FlexWrap) Option {
+	return func(fl *flex.Flex) { fl.Wrap = w }
+}
+
+// Gap sets both the row and column gap, in pixels.
+func Gap(px int) Option {
+	return func(fl *flex.Flex) { fl.RowGap, fl.ColumnGap = px, px }
+}
+
+// GapSpace sets both gaps to a step of the container's Spacing.
+func GapSpace(s flex.Space) Option {
+	return func(fl *flex.Flex) { fl.GapSpace = s }
+}
+
+// RowGap sets the gap between rows, in pixels.
+func RowGap(px int) Option {
+	return func(fl *flex.Flex) { fl.RowGap = px }
+}
+
+// ColumnGap sets the gap between columns, in pixels.
+func ColumnGap(px int) Option {
+	return func(fl *flex.Flex) { fl.ColumnGap = px }
+}
+
+// Justify sets the alignment of items along the main axis.
+func Justify(j flex.Justify) Option {
+	return func(fl *flex.Flex) { fl.Justify = j }
+}
+
+// LastLineJustify sets the alignment of the last line along the main
+// axis, overriding Justify.
+func LastLineJustify(j flex.Justify) Option {
+	return func(fl *flex.Flex) { fl.LastLineJustify = &j }
+}
+
+// AlignItems sets the default cross axis alignment of items.
+func AlignItems(a flex.AlignItem) Option {
+	return func(fl *flex.Flex) { fl.AlignItem = a }
+}
+
+// AlignContent sets the alignment of lines along the cross axis.
+func AlignContent(a flex.AlignContent) Option {
+	return func(fl *flex.Flex) { fl.AlignContent = a }
+}
+
+// Safe makes Justify and AlignItems fall back to start alignment for
+// content that would overflow the start edge. See flex.Flex.Safe.
+func Safe() Option {
+	return func(fl *flex.Flex) { fl.Safe = true }
+}
+
+// LineMinCrossSize sets the minimum cross size of each flex line.
+func LineMinCrossSize(v unit.Value) Option {
+	return func(fl *flex.Flex) { fl.LineMinCrossSize = v }
+}
+
+// MaxLines limits a wrapping container to n lines, with overflow
+// saying what becomes of the children beyond them.
+func MaxLines(n int, overflow flex.LineOverflow) Option {
+	return func(fl *flex.Flex) { fl.MaxLines, fl.LineOverflow = n, overflow }
+}
+
+// SafeArea sets the insets that children are laid out inside.
+func SafeArea(in flex.Insets) Option {
+	return func(fl *flex.Flex) { fl.SafeArea = in }
+}
+
+// EqualSplit makes the children added by the preceding options share
+// the container's main size equally. See flex.EqualSplit.
+func EqualSplit() Option {
+	return flex.EqualSplit
+}
+
+// Defaults sets the container's DefaultLayoutData, built from opts.
+// Children added by later options start from it, and may override
+// any of its properties.
+func Defaults(opts ...ItemOption) Option {
+	var d flex.LayoutData
+	for _, opt := range opts {
+		opt(&d)
+	}
+	return func(fl *flex.Flex) { fl.DefaultLayoutData = &d }
+}
+
+// Grow sets the flex grow factor.
+func Grow(f float64) ItemOption {
+	return func(d *flex.LayoutData) { d.Grow = f }
+}
+
+// Fraction sizes the item as a share of the container's free space,
+// like a CSS grid fr track. See flex.LayoutData.Fraction.
+func Fraction(f float64) ItemOption {
+	return func(d *flex.LayoutData) { d.Fraction = f }
+}
+
+// Shrink sets the flex shrink factor.
+func Shrink(f float64) ItemOption {
+	return func(d *flex.LayoutData) { d.Shrink = &f }
+}
+
+// Basis sets a definite flex basis, in pixels.
+func Basis(px int) ItemOption {
+	return func(d *flex.LayoutData) { d.Basis, d.BasisPx = flex.Definite, px }
+}
+
+// FitContent sets a fit-content flex basis: the item's measured size,
+// up to px pixels.
+func FitContent(px int) ItemOption {
+	return func(d *flex.LayoutData) { d.Basis, d.BasisPx = flex.FitContent, px }
+}
+
+// BasisClamp sets a flex basis of preferred clamped between min and
+// max. See flex.BasisClamp.
+func BasisClamp(min, preferred, max flex.Length) ItemOption {
+	return func(d *flex.LayoutData) {
+		d.BasisClamp = &flex.BasisClamp{Min: min, Preferred: preferred, Max: max}
+	}
+}
+
+// CrossSize sets a definite cross size, in pixels, which is not
+// stretched.
+func CrossSize(px int) ItemOption {
+	return func(d *flex.LayoutData) { d.CrossBasis, d.CrossSize = flex.Definite, px }
+}
+
+// Align overrides the container's AlignItems for this item.
+func Align(a flex.AlignItem) ItemOption {
+	return func(d *flex.LayoutData) { d.Align = a }
+}
+
+// MinSize sets the minimum size of the item.
+func MinSize(w, h int) ItemOption {
+	return func(d *flex.LayoutData) { d.MinSize = image.Pt(w, h) }
+}
+
+// MaxSize sets the maximum size of the item.
+func MaxSize(w, h int) ItemOption {
+	return func(d *flex.LayoutData) {
+		max := image.Pt(w, h)
+		d.MaxSize = &max
+	}
+}
+
+// BreakAfter forces the next item onto a new line.
+func BreakAfter() ItemOption {
+	return func(d *flex.LayoutData) { d.BreakAfter = true }
+}
+
+// FullBleed extends the item into its container's SafeArea.
+func FullBleed() ItemOption {
+	return func(d *flex.LayoutData) { d.FullBleed = true }
+}
+
+// ZIndex sets the paint order of the item among its siblings.
+func ZIndex(z int) ItemOption {
+	return func(d *flex.LayoutData) { d.ZIndex = z }
+}
+
+// Pin pins the item to the main-start or main-end edge of the
+// container, outside the flow.
+func Pin(p flex.Pin) ItemOption {
+	return func(d *flex.LayoutData) { d.Pin = p }
+}
+
+// PointerEvents sets whether the item can be the target of pointer
+// events. See flex.LayoutData.PointerEvents.
+func PointerEvents(p flex.PointerEvents) ItemOption {
+	return func(d *flex.LayoutData) { d.PointerEvents = p }
+}
+
+// Margin sets the space around the item. Negative margins overlap it
+// with its neighbours. See flex.LayoutData.Margin.
+func Margin(in flex.Insets) ItemOption {
+	return func(d *flex.LayoutData) { d.Margin = in }
+}
+
+// Offset moves the item by (x, y) pixels after layout.
+func Offset(x, y int) ItemOption {
+	return func(d *flex.LayoutData) { d.Offset = image.Pt(x, y) }
+}
+
+// At adds a breakpoint to the item: in containers with a main size of
+// at least minMainSize pixels, its LayoutData is built from opts
+// instead. Only opts apply there; the item's other options do not.
+func At(minMainSize int, opts ...ItemOption) ItemOption {
+	var bd flex.LayoutData
+	for _, opt := range opts {
+		opt(&bd)
+	}
+	return func(d *flex.LayoutData) {
+		// d.Breakpoints may be shared with the DefaultLayoutData and
+		// other children built from it, so never append in place.
+		var bps []flex.Breakpoint
+		if d.Breakpoints != nil {
+			bps = append(bps, *d.Breakpoints...)
+		}
+		bps = append(bps, flex.Breakpoint{MinMainSize: minMainSize, LayoutData: bd})
+		d.Breakpoints = &bps
+	}
+}
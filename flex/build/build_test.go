@@ -0,0 +1,182 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package build
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/crawshaw/exp/flex"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func box(w, h float64) *widget.Node {
+	return widget.NewUniform(color.Black, unit.Pixels(w), unit.Pixels(h)).Node
+}
+
+func TestBuild(t *testing.T) {
+	icon := box(40, 40)
+	title := box(100, 20)
+	body := box(100, 20)
+	side := box(30, 30)
+
+	col := Column(
+		Of(title),
+		Of(body, Grow(1)),
+	)
+	root := Row(Gap(10), AlignItems(flex.AlignItemStretch),
+		Of(icon, Align(flex.AlignItemStart)),
+		Of(col, Grow(1), Shrink(0)),
+		Of(side, Basis(50)),
+	)
+	if root.Direction != flex.Row || col.Direction != flex.Column {
+		t.Fatalf("directions %v, %v", root.Direction, col.Direction)
+	}
+	if col.Parent != &root.Node {
+		t.Fatal("nested Flex is not a child of root")
+	}
+
+	root.Class.Measure(&root.Node, nil)
+	root.Rect = image.Rectangle{Max: image.Pt(400, 100)}
+	root.Class.Layout(&root.Node, nil)
+
+	for _, test := range []struct {
+		name string
+		n    *widget.Node
+		want image.Rectangle
+	}{
+		{"icon", icon, image.Rect(0, 0, 40, 40)},
+		{"col", &col.Node, image.Rect(50, 0, 340, 100)},
+		{"title", title, image.Rect(0, 0, 100, 20)},
+		{"body", body, image.Rect(0, 20, 100, 100)},
+		{"side", side, image.Rect(350, 0, 400, 100)},
+	} {
+		if test.n.Rect != test.want {
+			t.Errorf("%s.Rect=%v, want %v", test.name, test.n.Rect, test.want)
+		}
+	}
+
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if _, ok := c.LayoutData.(flex.LayoutData); !ok {
+			t.Errorf("child %p has no LayoutData", c)
+		}
+	}
+	if d := side.LayoutData.(flex.LayoutData); d.Basis != flex.Definite || d.BasisPx != 50 {
+		t.Errorf("side LayoutData=%+v", d)
+	}
+}
+
+func TestAt(t *testing.T) {
+	a, b := box(100, 10), box(100, 10)
+	root := Row(Wrap(flex.Wrap),
+		Of(a, Grow(1), BreakAfter(), At(600, Basis(200))),
+		Of(b),
+	)
+	root.Class.Measure(&root.Node, nil)
+	for _, test := range []struct {
+		width int
+		a, b  image.Rectangle
+	}{
+		{400, image.Rect(0, 0, 400, 10), image.Rect(0, 10, 100, 20)},
+		{700, image.Rect(0, 0, 200, 10), image.Rect(200, 0, 300, 10)},
+	} {
+		root.Rect = image.Rect(0, 0, test.width, 20)
+		root.Class.Layout(&root.Node, nil)
+		if a.Rect != test.a || b.Rect != test.b {
+			t.Errorf("width %d: a=%v, b=%v, want %v, %v", test.width, a.Rect, b.Rect, test.a, test.b)
+		}
+	}
+}
+
+// TestAtShared checks that children built from the same defaults do
+// not share the breakpoints they add.
+func TestAtShared(t *testing.T) {
+	a, b := box(100, 10), box(100, 10)
+	Row(
+		Defaults(At(100, Grow(1)), At(200, Grow(2)), At(300, Grow(3))),
+		Of(a, At(400, Grow(4))),
+		Of(b, At(400, Grow(5))),
+	)
+	for i, n := range []*widget.Node{a, b} {
+		bps := *n.LayoutData.(flex.LayoutData).Breakpoints
+		if len(bps) != 4 || bps[3].LayoutData.Grow != float64(4+i) {
+			t.Errorf("child %d Breakpoints = %+v", i, bps)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	if got := Row(Reverse()).Direction; got != flex.RowReverse {
+		t.Errorf("Row(Reverse()).Direction=%v", got)
+	}
+	if got := Column(Reverse()).Direction; got != flex.ColumnReverse {
+		t.Errorf("Column(Reverse()).Direction=%v", got)
+	}
+}
+
+func TestOfPanics(t *testing.T) {
+	for _, child := range []interface{}{"label", widget.Node{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Of(%T) did not panic", child)
+				}
+			}()
+			Of(child)
+		}()
+	}
+
+	n := box(1, 1)
+	Row(Of(n))
+	defer func() {
+		if recover() == nil {
+			t.Error("adding a child twice did not panic")
+		}
+	}()
+	Row(Of(n))
+}
+
+func TestEqualSplit(t *testing.T) {
+	a, b, c := box(10, 10), box(50, 10), box(0, 10)
+	root := Row(Of(a), Of(b, Align(flex.AlignItemEnd)), Of(c), EqualSplit())
+	root.Class.Measure(&root.Node, nil)
+	root.Rect = image.Rectangle{Max: image.Pt(100, 20)}
+	root.Class.Layout(&root.Node, nil)
+
+	if got, want := []int{a.Rect.Dx(), b.Rect.Dx(), c.Rect.Dx()}, []int{34, 33, 33}; got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("widths %v, want %v", got, want)
+	}
+	if b.Rect.Min.Y != 10 {
+		t.Errorf("b.Rect=%v, want its Align kept", b.Rect)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	a, b := box(10, 10), box(10, 10)
+	root := Row(Defaults(Grow(1), Shrink(0)),
+		Of(a),
+		Of(b, Grow(3)),
+	)
+	da := a.LayoutData.(flex.LayoutData)
+	db := b.LayoutData.(flex.LayoutData)
+	if da.Grow != 1 || da.Shrink == nil || *da.Shrink != 0 {
+		t.Errorf("a LayoutData=%+v", da)
+	}
+	if db.Grow != 3 || db.Shrink == nil || *db.Shrink != 0 {
+		t.Errorf("b LayoutData=%+v", db)
+	}
+	if root.DefaultLayoutData == nil || root.DefaultLayoutData.Grow != 1 {
+		t.Errorf("DefaultLayoutData=%+v", root.DefaultLayoutData)
+	}
+}
+
+func TestGapSpace(t *testing.T) {
+	root := Row(GapSpace(flex.SpaceL))
+	if root.GapSpace != flex.SpaceL {
+		t.Errorf("GapSpace=%v, want l", root.GapSpace)
+	}
+}
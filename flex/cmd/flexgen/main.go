@@ -0,0 +1,590 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Flexgen converts an HTML mockup into Go source that constructs the
+// same widget tree, so that a prototype laid out in a browser becomes
+// the starting point of an app instead of being typed in again.
+//
+// Usage:
+//
+//	flexgen [flags] [mockup.html]
+//
+// The mockup is read from the named file, or from standard input. It
+// is in the restricted HTML read by package htmlflex: divs with inline
+// flexbox styles, and img and span placeholders.
+//
+// The generated function builds the tree with package build. Every
+// element with an id becomes a local variable of the same name, in
+// camel case, so that it is easy to find and replace the placeholders
+// with real widgets.
+//
+// The flags are:
+//
+//	-o file
+//		the Go file to write (default standard output)
+//	-pkg name
+//		the package clause of the generated file (default ui)
+//	-func name
+//		the name of the generated function (default New)
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/htmlflex"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "flexgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("flexgen", flag.ContinueOnError)
+	var (
+		outFlag  = fs.String("o", "", "output Go `file` (default stdout)")
+		pkgFlag  = fs.String("pkg", "ui", "package `name` of the generated file")
+		funcFlag = fs.String("func", "New", "`name` of the generated function")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("at most one mockup may be given")
+	}
+	for _, name := range []string{*pkgFlag, *funcFlag} {
+		if !isIdent(name) {
+			return fmt.Errorf("%q is not a Go identifier", name)
+		}
+	}
+
+	in, src := stdin, "standard input"
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in, src = f, fs.Arg(0)
+	}
+	doc, err := htmlflex.Parse(in)
+	if err != nil {
+		return err
+	}
+	b, err := generate(doc, src, *pkgFlag, *funcFlag)
+	if err != nil {
+		return err
+	}
+
+	if *outFlag == "" {
+		_, err = stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(*outFlag, b, 0666)
+}
+
+// generate returns the formatted source of a file in package pkg with
+// a function fn that constructs the tree described by doc.
+func generate(doc *flex.Doc, src, pkg, fn string) ([]byte, error) {
+	// Building the tree checks the mockup, and resolves its styles into
+	// the fields that the generated code sets.
+	tree, err := flex.Build(doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Type != "flex" {
+		return nil, errors.New("the root element is not a flex container")
+	}
+
+	g := &generator{
+		imports: make(map[string]bool),
+		idents:  make(map[string]bool),
+	}
+	g.use("github.com/crawshaw/exp/flex")
+	for _, pkg := range []string{"build", "color", "flex", "math", "unit", "widget", pkg, fn} {
+		g.idents[pkg] = true
+	}
+	root := g.node(doc, tree.Root)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// This file was generated by flexgen from %s,\n", src)
+	fmt.Fprintf(&buf, "// as a starting point to be edited.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	// Standard library imports come first, in their own group.
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	buf.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString("\n")
+	for _, path := range other {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+	fmt.Fprintf(&buf, "// %s returns the widget tree of the mockup.\n", fn)
+	fmt.Fprintf(&buf, "func %s() *flex.Flex {\n", fn)
+	buf.Write(g.decls.Bytes())
+	fmt.Fprintf(&buf, "return %s\n}\n", root)
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return b, nil
+}
+
+// A generator writes the Go expressions that construct a tree.
+type generator struct {
+	imports map[string]bool // import paths used
+	idents  map[string]bool // identifiers in use
+	decls   bytes.Buffer    // declarations of the nodes with ids
+}
+
+func (g *generator) use(path string) {
+	g.imports[path] = true
+}
+
+// node returns an expression for the node n built from doc. If doc has
+// an id, n is declared as a variable and the expression is its name.
+func (g *generator) node(doc *flex.Doc, n *widget.Node) string {
+	var expr string
+	if fl := flex.FlexOf(n); fl != nil {
+		expr = g.flex(doc, fl)
+	} else {
+		expr = g.uniform(doc)
+	}
+	if doc.ID == "" {
+		return expr
+	}
+	name := g.ident(doc.ID)
+	fmt.Fprintf(&g.decls, "%s := %s\n", name, expr)
+	return name
+}
+
+func (g *generator) flex(doc *flex.Doc, fl *flex.Flex) string {
+	g.use("github.com/crawshaw/exp/flex/build")
+	var opts []string
+	def := flex.NewFlex()
+
+	switch fl.Direction {
+	case flex.RowReverse, flex.ColumnReverse:
+		opts = append(opts, "build.Reverse()")
+	}
+	if fl.Wrap != def.Wrap {
+		opts = append(opts, "build.Wrap("+g.enum(wrapIdents[:], int(fl.Wrap))+")")
+	}
+	switch {
+	case fl.RowGap == fl.ColumnGap && fl.RowGap != 0:
+		opts = append(opts, fmt.Sprintf("build.Gap(%d)", fl.RowGap))
+	default:
+		if fl.RowGap != 0 {
+			opts = append(opts, fmt.Sprintf("build.RowGap(%d)", fl.RowGap))
+		}
+		if fl.ColumnGap != 0 {
+			opts = append(opts, fmt.Sprintf("build.ColumnGap(%d)", fl.ColumnGap))
+		}
+	}
+	if fl.GapSpace != def.GapSpace {
+		opts = append(opts, "build.GapSpace("+g.enum(spaceIdents[:], int(fl.GapSpace))+")")
+	}
+	if fl.Justify != def.Justify {
+		opts = append(opts, "build.Justify("+g.enum(justifyIdents[:], int(fl.Justify))+")")
+	}
+	if fl.AlignItem != def.AlignItem {
+		opts = append(opts, "build.AlignItems("+g.enum(alignItemIdents[:], int(fl.AlignItem))+")")
+	}
+	if fl.AlignContent != def.AlignContent {
+		opts = append(opts, "build.AlignContent("+g.enum(alignContentIdents[:], int(fl.AlignContent))+")")
+	}
+
+	c := fl.FirstChild
+	for _, cdoc := range doc.Children {
+		args := []string{g.node(cdoc, c)}
+		if d, ok := c.LayoutData.(flex.LayoutData); ok {
+			args = append(args, g.item(d)...)
+		}
+		if len(args) > 4 || strings.Contains(strings.Join(args, ""), "func(") {
+			// One argument per line keeps long calls readable.
+			opts = append(opts, "build.Of(\n"+strings.Join(args, ",\n")+",\n)")
+		} else {
+			opts = append(opts, "build.Of("+strings.Join(args, ", ")+")")
+		}
+		c = c.NextSibling
+	}
+
+	fn := "build.Row"
+	if fl.Direction == flex.Column || fl.Direction == flex.ColumnReverse {
+		fn = "build.Column"
+	}
+	if len(opts) == 0 {
+		return fn + "()"
+	}
+	return fn + "(\n" + strings.Join(opts, ",\n") + ",\n)"
+}
+
+// item returns the build.ItemOptions that set the properties of d.
+// Properties with no ItemOption are set by a function literal.
+func (g *generator) item(d flex.LayoutData) []string {
+	var opts []string
+	if d.Grow != 0 {
+		opts = append(opts, "build.Grow("+float(d.Grow)+")")
+	}
+	if d.Fraction != 0 {
+		opts = append(opts, "build.Fraction("+float(d.Fraction)+")")
+	}
+	if d.Shrink != nil {
+		opts = append(opts, "build.Shrink("+float(*d.Shrink)+")")
+	}
+	switch d.Basis {
+	case flex.Content:
+		opts = append(opts, g.setter("d.Basis = flex.Content"))
+	case flex.Definite:
+		opts = append(opts, fmt.Sprintf("build.Basis(%d)", d.BasisPx))
+	case flex.FitContent:
+		opts = append(opts, fmt.Sprintf("build.FitContent(%d)", d.BasisPx))
+	}
+	if c := d.BasisClamp; c != nil {
+		opts = append(opts, "build.BasisClamp("+g.length(c.Min)+", "+g.length(c.Preferred)+", "+g.length(c.Max)+")")
+	}
+	if d.CrossBasis == flex.Definite {
+		opts = append(opts, fmt.Sprintf("build.CrossSize(%d)", d.CrossSize))
+	}
+	if d.Align != flex.AlignItemAuto {
+		opts = append(opts, "build.Align("+g.enum(alignItemIdents[:], int(d.Align))+")")
+	}
+	if d.MinSize != (image.Point{}) {
+		opts = append(opts, fmt.Sprintf("build.MinSize(%d, %d)", d.MinSize.X, d.MinSize.Y))
+	}
+	if d.MaxSize != nil {
+		opts = append(opts, "build.MaxSize("+g.maxPx(d.MaxSize.X)+", "+g.maxPx(d.MaxSize.Y)+")")
+	}
+	if d.MinLength != nil {
+		opts = append(opts, g.setter("d.MinLength = "+g.size(*d.MinLength)))
+	}
+	if d.MaxLength != nil {
+		opts = append(opts, g.setter("d.MaxLength = "+g.size(*d.MaxLength)))
+	}
+	if d.BreakAfter {
+		opts = append(opts, "build.BreakAfter()")
+	}
+	if d.FullBleed {
+		opts = append(opts, "build.FullBleed()")
+	}
+	if d.ZIndex != 0 {
+		opts = append(opts, fmt.Sprintf("build.ZIndex(%d)", d.ZIndex))
+	}
+	if d.Pin != flex.PinNone {
+		opts = append(opts, "build.Pin("+g.enum(pinIdents[:], int(d.Pin))+")")
+	}
+	if m := d.Margin; m != (flex.Insets{}) {
+		g.use("github.com/crawshaw/exp/flex")
+		opts = append(opts, fmt.Sprintf("build.Margin(flex.Insets{Top: %d, Right: %d, Bottom: %d, Left: %d})", m.Top, m.Right, m.Bottom, m.Left))
+	}
+	if d.PointerEvents != flex.PointerAuto {
+		opts = append(opts, "build.PointerEvents("+g.enum(pointerEventsIdents[:], int(d.PointerEvents))+")")
+	}
+	if d.Offset != (image.Point{}) {
+		opts = append(opts, fmt.Sprintf("build.Offset(%d, %d)", d.Offset.X, d.Offset.Y))
+	}
+	return opts
+}
+
+// setter returns a function literal usable as a build.ItemOption that
+// runs stmt on its LayoutData d.
+func (g *generator) setter(stmt string) string {
+	g.use("github.com/crawshaw/exp/flex")
+	return "func(d *flex.LayoutData) { " + stmt + " }"
+}
+
+// maxPx returns a MaxSize dimension, which is math.MaxInt32 when it
+// has no limit.
+func (g *generator) maxPx(px int) string {
+	if px == math.MaxInt32 {
+		g.use("math")
+		return "math.MaxInt32"
+	}
+	return strconv.Itoa(px)
+}
+
+func (g *generator) size(s flex.Size) string {
+	g.use("github.com/crawshaw/exp/flex")
+	var fields []string
+	if s.Width != (flex.Length{}) {
+		fields = append(fields, "Width: "+g.length(s.Width))
+	}
+	if s.Height != (flex.Length{}) {
+		fields = append(fields, "Height: "+g.length(s.Height))
+	}
+	return "&flex.Size{" + strings.Join(fields, ", ") + "}"
+}
+
+func (g *generator) length(l flex.Length) string {
+	g.use("github.com/crawshaw/exp/flex")
+	switch l.Kind {
+	case flex.LengthPercent:
+		return "flex.Length{Kind: flex.LengthPercent, Percent: " + float(l.Percent) + "}"
+	case flex.LengthValue:
+		return "flex.Length{Kind: flex.LengthValue, Value: " + g.value(l.Value) + "}"
+	}
+	return "flex.Length{}"
+}
+
+func (g *generator) value(v unit.Value) string {
+	g.use("golang.org/x/exp/shiny/unit")
+	if int(v.U) < len(unitFuncs) {
+		return "unit." + unitFuncs[v.U] + "(" + float(v.F) + ")"
+	}
+	return fmt.Sprintf("unit.Value{F: %s, U: %d}", float(v.F), v.U)
+}
+
+// enum returns the qualified name of the flex constant v, whose names
+// are idents.
+func (g *generator) enum(idents []string, v int) string {
+	g.use("github.com/crawshaw/exp/flex")
+	return "flex." + idents[v]
+}
+
+// uniform returns an expression for the placeholder built from doc.
+func (g *generator) uniform(doc *flex.Doc) string {
+	g.use("golang.org/x/exp/shiny/widget")
+	g.use("image/color")
+	var (
+		c    = "color.Transparent"
+		w, h = "unit.Value{}", "unit.Value{}"
+	)
+	for _, decl := range strings.Split(doc.Style, ";") {
+		i := strings.IndexByte(decl, ':')
+		if i < 0 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(decl[:i]))
+		val := strings.TrimSpace(decl[i+1:])
+		switch prop {
+		case "width":
+			w = g.value(parseValue(val))
+		case "height":
+			h = g.value(parseValue(val))
+		case "background-color":
+			c = colorLit(parseColor(val))
+		}
+	}
+	if w == "unit.Value{}" || h == "unit.Value{}" {
+		g.use("golang.org/x/exp/shiny/unit")
+	}
+	return "widget.NewUniform(" + c + ", " + w + ", " + h + ").Node"
+}
+
+// ident returns an unused Go identifier for the id of an element.
+func (g *generator) ident(id string) string {
+	var b bytes.Buffer
+	upper := false
+	for _, r := range id {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upper && b.Len() > 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "n" + name
+	}
+	if token.Lookup(name).IsKeyword() {
+		name += "_"
+	}
+	base := name
+	for i := 2; g.idents[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.idents[name] = true
+	return name
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != "" && !token.Lookup(s).IsKeyword()
+}
+
+func float(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func colorLit(c color.Color) string {
+	switch c := c.(type) {
+	case color.RGBA:
+		return fmt.Sprintf("color.RGBA{%#02x, %#02x, %#02x, %#02x}", c.R, c.G, c.B, c.A)
+	case color.NRGBA:
+		return fmt.Sprintf("color.NRGBA{%#02x, %#02x, %#02x, %#02x}", c.R, c.G, c.B, c.A)
+	}
+	return "color.Transparent"
+}
+
+// parseValue parses a CSS length, as flex.Load does. The mockup has
+// already been checked by flex.Build, so invalid lengths are zero.
+func parseValue(val string) unit.Value {
+	s := strings.ToLower(val)
+	for u, suffix := range unitSuffixes {
+		if strings.HasSuffix(s, suffix) {
+			f, _ := strconv.ParseFloat(strings.TrimSuffix(s, suffix), 64)
+			return unit.Value{F: f, U: unit.Unit(u)}
+		}
+	}
+	return unit.Value{}
+}
+
+// parseColor parses a CSS color, as flex.Load does. Invalid colors are
+// nil.
+func parseColor(val string) color.Color {
+	s := strings.ToLower(val)
+	switch {
+	case strings.HasPrefix(s, "#"):
+		s = s[1:]
+		if len(s) == 3 {
+			s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+		}
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil || len(s) != 6 {
+			return nil
+		}
+		return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba("):
+		args := strings.Split(strings.TrimSuffix(s[strings.IndexByte(s, '(')+1:], ")"), ",")
+		c := color.NRGBA{A: 0xff}
+		for i, arg := range args {
+			arg = strings.TrimSpace(arg)
+			if i == 3 {
+				a, _ := strconv.ParseFloat(arg, 64)
+				c.A = uint8(a*0xff + 0.5)
+				continue
+			}
+			v, _ := strconv.ParseUint(arg, 10, 8)
+			switch i {
+			case 0:
+				c.R = uint8(v)
+			case 1:
+				c.G = uint8(v)
+			case 2:
+				c.B = uint8(v)
+			}
+		}
+		return c
+	}
+	return nil
+}
+
+// unitSuffixes are the CSS suffixes of the units, indexed by unit.Unit.
+var unitSuffixes = [...]string{
+	unit.Px: "px",
+	unit.Dp: "dp",
+	unit.Pt: "pt",
+	unit.Mm: "mm",
+	unit.In: "in",
+	unit.Em: "em",
+	unit.Ex: "ex",
+	unit.Ch: "ch",
+}
+
+// unitFuncs are the constructors of unit.Values, indexed by unit.Unit.
+var unitFuncs = [...]string{
+	unit.Px: "Pixels",
+	unit.Dp: "DIPs",
+	unit.Pt: "Points",
+	unit.Mm: "Millimetres",
+	unit.In: "Inches",
+	unit.Em: "Ems",
+	unit.Ex: "Exs",
+	unit.Ch: "Chs",
+}
+
+var wrapIdents = [...]string{
+	flex.NoWrap:      "NoWrap",
+	flex.Wrap:        "Wrap",
+	flex.WrapReverse: "WrapReverse",
+}
+
+var spaceIdents = [...]string{
+	flex.SpaceNone: "SpaceNone",
+	flex.SpaceXS:   "SpaceXS",
+	flex.SpaceS:    "SpaceS",
+	flex.SpaceM:    "SpaceM",
+	flex.SpaceL:    "SpaceL",
+	flex.SpaceXL:   "SpaceXL",
+}
+
+var justifyIdents = [...]string{
+	flex.JustifyStart:        "JustifyStart",
+	flex.JustifyEnd:          "JustifyEnd",
+	flex.JustifyCenter:       "JustifyCenter",
+	flex.JustifySpaceBetween: "JustifySpaceBetween",
+	flex.JustifySpaceAround:  "JustifySpaceAround",
+}
+
+var alignItemIdents = [...]string{
+	flex.AlignItemAuto:         "AlignItemAuto",
+	flex.AlignItemStart:        "AlignItemStart",
+	flex.AlignItemEnd:          "AlignItemEnd",
+	flex.AlignItemCenter:       "AlignItemCenter",
+	flex.AlignItemBaseline:     "AlignItemBaseline",
+	flex.AlignItemStretch:      "AlignItemStretch",
+	flex.AlignItemLastBaseline: "AlignItemLastBaseline",
+}
+
+var alignContentIdents = [...]string{
+	flex.AlignContentStretch:      "AlignContentStretch",
+	flex.AlignContentStart:        "AlignContentStart",
+	flex.AlignContentEnd:          "AlignContentEnd",
+	flex.AlignContentCenter:       "AlignContentCenter",
+	flex.AlignContentSpaceBetween: "AlignContentSpaceBetween",
+	flex.AlignContentSpaceAround:  "AlignContentSpaceAround",
+}
+
+var pinIdents = [...]string{
+	flex.PinNone:  "PinNone",
+	flex.PinStart: "PinStart",
+	flex.PinEnd:   "PinEnd",
+}
+
+var pointerEventsIdents = [...]string{
+	flex.PointerAuto: "PointerAuto",
+	flex.PointerNone: "PointerNone",
+}
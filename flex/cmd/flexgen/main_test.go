@@ -0,0 +1,109 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const testMockup = `<body>
+<div style="display: flex; flex-direction: column; gap: 8px">
+	<div id="tool-bar" style="display: flex; justify-content: space-between">
+		<img width="24" height="24">
+		<span id="title" style="flex: 1; background-color: #336"></span>
+	</div>
+	<div style="flex-grow: 1; min-width: 50%; max-height: 300px"></div>
+</div>
+</body>`
+
+const wantSource = `// This file was generated by flexgen from standard input,
+// as a starting point to be edited.
+
+package screens
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/build"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Main returns the widget tree of the mockup.
+func Main() *flex.Flex {
+	title := widget.NewUniform(color.RGBA{0x33, 0x33, 0x66, 0xff}, unit.Value{}, unit.Value{}).Node
+	toolBar := build.Row(
+		build.Justify(flex.JustifySpaceBetween),
+		build.Of(widget.NewUniform(color.Transparent, unit.Pixels(24), unit.Pixels(24)).Node),
+		build.Of(title, build.Grow(1), build.Shrink(1), build.Basis(0)),
+	)
+	return build.Column(
+		build.Gap(8),
+		build.Of(toolBar),
+		build.Of(
+			widget.NewUniform(color.Transparent, unit.Value{}, unit.Value{}).Node,
+			build.Grow(1),
+			build.MaxSize(math.MaxInt32, 300),
+			func(d *flex.LayoutData) {
+				d.MinLength = &flex.Size{Width: flex.Length{Kind: flex.LengthPercent, Percent: 50}}
+			},
+		),
+	)
+}
+`
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-pkg", "screens", "-func", "Main"}, strings.NewReader(testMockup), &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != wantSource {
+		t.Errorf("generated:\n%s\nwant:\n%s", got, wantSource)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", out.Bytes(), 0); err != nil {
+		t.Errorf("generated code does not parse: %v", err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		mockup string
+	}{
+		{[]string{"-pkg", "a-b"}, testMockup},
+		{[]string{"-func", "func"}, testMockup},
+		{[]string{"a.html", "b.html"}, testMockup},
+		{nil, `<div style="width: 10px"></div>`},
+		{nil, `<div style="display: flex; flex-grow: x"><div></div></div>`},
+	} {
+		var out bytes.Buffer
+		if err := run(tc.args, strings.NewReader(tc.mockup), &out); err == nil {
+			t.Errorf("run(%q, %q) succeeded, want error", tc.args, tc.mockup)
+		}
+	}
+}
+
+func TestIdent(t *testing.T) {
+	g := &generator{idents: map[string]bool{"flex": true}}
+	for _, tc := range []struct{ id, want string }{
+		{"side-bar", "sideBar"},
+		{"side_bar", "sideBar2"},
+		{"sideBar", "sideBar3"},
+		{"flex", "flex2"},
+		{"2col", "n2col"},
+		{"type", "type_"},
+		{"-", "n"},
+	} {
+		if got := g.ident(tc.id); got != tc.want {
+			t.Errorf("ident(%q) = %q, want %q", tc.id, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Flexrender lays out a widget tree described by a flex.Doc and writes
+// it as a PNG image, for documentation, bug reports and visual checks
+// in continuous integration.
+//
+// Usage:
+//
+//	flexrender [flags] [doc.json]
+//
+// The document is read from the named file, or from standard input.
+// It is in the JSON form read by flex.Load. A YAML document can be
+// converted to JSON first; the field names are the same.
+//
+// The flags are:
+//
+//	-size WxH
+//		the size of the root node and of the image (default 640x480)
+//	-o file
+//		the PNG file to write (default standard output)
+//	-dpi n
+//		the Theme's DPI, for lengths in physical units
+//	-css file
+//		a style sheet to apply to the tree before layout
+//	-bg color
+//		the background, #rgb or #rrggbb, or none (default #fff)
+//	-outline
+//		draw a 1px border around every node
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/render"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "flexrender: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// outlineColor is the border drawn by -outline.
+var outlineColor = color.RGBA{0x80, 0x80, 0x80, 0xff}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("flexrender", flag.ContinueOnError)
+	var (
+		sizeFlag = fs.String("size", "640x480", "image `size` as WxH")
+		outFlag  = fs.String("o", "", "output PNG `file` (default stdout)")
+		dpiFlag  = fs.Float64("dpi", 0, "theme `DPI`")
+		cssFlag  = fs.String("css", "", "style sheet `file` to apply")
+		bgFlag   = fs.String("bg", "#fff", "background `color`, or none")
+		outline  = fs.Bool("outline", false, "outline every node")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("at most one document may be given")
+	}
+	size, err := parseSize(*sizeFlag)
+	if err != nil {
+		return err
+	}
+	bg, err := parseColor(*bgFlag)
+	if err != nil {
+		return err
+	}
+
+	in := stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	tree, err := flex.Load(in)
+	if err != nil {
+		return err
+	}
+	if *cssFlag != "" {
+		b, err := ioutil.ReadFile(*cssFlag)
+		if err != nil {
+			return err
+		}
+		ss, err := flex.ParseStyleSheet(string(b))
+		if err != nil {
+			return err
+		}
+		ss.Apply(tree.Root, tree.Names)
+	}
+
+	opts := &render.Options{
+		Theme:      &widget.Theme{DPI: *dpiFlag},
+		Background: bg,
+	}
+	if *outline {
+		opts.Decorations = make(map[*widget.Node]render.Decoration)
+		outlineAll(opts.Decorations, tree.Root)
+	}
+	img := render.Render(tree.Root, size, opts)
+
+	out := stdout
+	if *outFlag != "" {
+		f, err := os.Create(*outFlag)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := png.Encode(out, img); err != nil {
+		return err
+	}
+	if f, ok := out.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
+
+func outlineAll(decs map[*widget.Node]render.Decoration, n *widget.Node) {
+	decs[n] = render.Decoration{BorderColor: outlineColor, BorderWidth: 1}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		outlineAll(decs, c)
+	}
+}
+
+// parseSize parses a size of the form WxH.
+func parseSize(s string) (image.Point, error) {
+	i := strings.IndexByte(s, 'x')
+	if i < 0 {
+		return image.Point{}, fmt.Errorf("invalid size %q, want WxH", s)
+	}
+	w, err1 := strconv.Atoi(s[:i])
+	h, err2 := strconv.Atoi(s[i+1:])
+	if err1 != nil || err2 != nil || w <= 0 || h <= 0 {
+		return image.Point{}, fmt.Errorf("invalid size %q, want WxH", s)
+	}
+	return image.Pt(w, h), nil
+}
+
+// parseColor parses #rgb, #rrggbb or none, which is nil.
+func parseColor(s string) (color.Color, error) {
+	if s == "none" {
+		return nil, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 || !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+}
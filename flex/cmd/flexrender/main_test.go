@@ -0,0 +1,68 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"type": "flex",
+	"style": "justify-content: flex-end",
+	"children": [
+		{"type": "uniform", "id": "box", "style": "width: 10px; height: 5px; background-color: #f00"}
+	]
+}`
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-size", "40x20"}, strings.NewReader(testDoc), &out); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds(); got != image.Rect(0, 0, 40, 20) {
+		t.Errorf("bounds = %v", got)
+	}
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	for _, p := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{35, 2, red},
+		{25, 2, white},
+		{35, 10, white},
+	} {
+		if got := color.RGBAModel.Convert(img.At(p.x, p.y)); got != p.want {
+			t.Errorf("pixel (%d,%d) = %v, want %v", p.x, p.y, got, p.want)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-size", "40"},
+		{"-size", "0x10"},
+		{"-bg", "red"},
+		{"a.json", "b.json"},
+	} {
+		var out bytes.Buffer
+		if err := run(args, strings.NewReader(testDoc), &out); err == nil {
+			t.Errorf("run(%q) succeeded, want error", args)
+		}
+	}
+	var out bytes.Buffer
+	if err := run(nil, strings.NewReader(`{"type": "button"}`), &out); err == nil {
+		t.Error("unknown node type: want error")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package debughttp serves the layout of a running app's widget tree
+// over HTTP, so that apps without a developer at the screen, such as
+// kiosks and embedded devices, can be inspected from another machine
+// through an SSH tunnel.
+//
+// A Handler serves two pages: the snapshot as JSON at layout.json, and
+// at the root, a viewer that draws the Rects of the tree and shows the
+// LayoutData and flex lines of the node clicked on:
+//
+//	var s flex.Sync
+//	...
+//	l, err := debughttp.Start("localhost:6060", &debughttp.Handler{
+//		Result: s.Result,
+//		Names:  tree.Names,
+//	})
+//
+// The app lays out its tree with s.Layout or s.Update, and the Handler
+// only reads the snapshots they record, so serving never races with
+// the app's event loop.
+package debughttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Handler serves a layout snapshot for inspection.
+type Handler struct {
+	// Result returns the snapshot to serve, or nil if there is none
+	// yet. It is called for every request, from the server's
+	// goroutines. It is usually the Result method of a flex.Sync.
+	Result func() *flex.LayoutResult
+
+	// Names gives the names shown for each node. If nil,
+	// flex.DefaultNodeNames is used; a flex.Tree's Names method shows
+	// the ids and classes of its Doc.
+	Names func(n *widget.Node) flex.NodeNames
+}
+
+// Node is a node of a snapshot, as served at layout.json.
+type Node struct {
+	// Index is the position of the node in a depth-first walk of the
+	// tree, from 0 at the root.
+	Index int `json:"index"`
+
+	// Type, ID and Classes are the names of the node.
+	Type    string   `json:"type,omitempty"`
+	ID      string   `json:"id,omitempty"`
+	Classes []string `json:"classes,omitempty"`
+
+	// Class is the Go type of the node's Class.
+	Class string `json:"class"`
+
+	// Rect is the node's Rect, relative to its parent, and ScreenRect
+	// the same box relative to the root.
+	Rect       image.Rectangle `json:"rect"`
+	ScreenRect image.Rectangle `json:"screen-rect"`
+
+	LayoutData *flex.LayoutData `json:"layout-data,omitempty"`
+	Lines      []flex.Line      `json:"lines,omitempty"`
+	Children   []*Node          `json:"children,omitempty"`
+}
+
+// Tree returns the tree of Nodes recorded by r, or nil if r is nil.
+func (h *Handler) Tree(r *flex.LayoutResult) *Node {
+	if r == nil {
+		return nil
+	}
+	names := h.Names
+	if names == nil {
+		names = flex.DefaultNodeNames
+	}
+	index := 0
+	var walk func(n *widget.Node, origin image.Point) *Node
+	walk = func(n *widget.Node, origin image.Point) *Node {
+		rect, _ := r.Rect(n)
+		nm := names(n)
+		dn := &Node{
+			Index:      index,
+			Type:       nm.Type,
+			ID:         nm.ID,
+			Classes:    nm.Classes,
+			Class:      fmt.Sprintf("%T", n.Class),
+			Rect:       rect,
+			ScreenRect: rect.Add(origin),
+			Lines:      r.Lines(n),
+		}
+		if d, ok := r.LayoutData(n); ok {
+			dn.LayoutData = &d
+		}
+		index++
+		for _, c := range r.Children(n) {
+			dn.Children = append(dn.Children, walk(c, dn.ScreenRect.Min))
+		}
+		return dn
+	}
+	return walk(r.Root, image.Point{})
+}
+
+// ServeHTTP serves the snapshot as JSON at layout.json, and the viewer
+// at any other path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if strings.HasSuffix(req.URL.Path, "/layout.json") {
+		h.serveJSON(w)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, viewerHTML)
+}
+
+func (h *Handler) serveJSON(w http.ResponseWriter) {
+	var r *flex.LayoutResult
+	if h.Result != nil {
+		r = h.Result()
+	}
+	if r == nil {
+		http.Error(w, "no layout yet", http.StatusServiceUnavailable)
+		return
+	}
+	b, err := json.MarshalIndent(h.Tree(r), "", "\t")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(b)
+}
+
+// Start serves h on addr in a new goroutine, until the returned
+// Listener is closed. The host of addr must be a loopback address,
+// such as localhost, so that the tree is not exposed to the network.
+// A port of 0 picks a free port, which is in the Listener's Addr.
+func Start(addr string, h http.Handler) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !isLoopback(host) {
+		return nil, fmt.Errorf("debughttp: %q is not a loopback address", addr)
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go http.Serve(l, h)
+	return l, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// viewerHTML is the viewer page. It fetches layout.json, relative to
+// its own URL, so the Handler can be mounted at any path.
+const viewerHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Layout</title>
+<style>
+body { font: 13px sans-serif; margin: 0; display: flex; height: 100vh; }
+#tree { width: 320px; overflow: auto; border-right: 1px solid #ccc; padding: 4px; }
+#tree div { cursor: pointer; white-space: nowrap; }
+#tree div.sel { background: #cde; }
+#main { flex: 1; display: flex; flex-direction: column; overflow: auto; }
+#view { position: relative; margin: 8px; background: #fff; outline: 1px solid #888; }
+#view div { position: absolute; box-sizing: border-box; border: 1px solid rgba(0, 0, 0, 0.2); }
+#view div.sel { border: 2px solid #e40; background: rgba(238, 68, 0, 0.1); }
+#view div.line { border: 1px dashed #08c; }
+#details { margin: 8px; white-space: pre; font-family: monospace; }
+</style>
+</head>
+<body>
+<div id="tree"></div>
+<div id="main">
+<div><button id="reload">Reload</button></div>
+<div id="view"></div>
+<div id="details"></div>
+</div>
+<script>
+var root, selected = 0;
+
+function label(n) {
+	var s = n.type || n.class;
+	if (n.id) s += "#" + n.id;
+	if (n.classes) s += "." + n.classes.join(".");
+	return s;
+}
+
+function box(r, cls) {
+	var d = document.createElement("div");
+	d.className = cls || "";
+	d.style.left = r.Min.X + "px";
+	d.style.top = r.Min.Y + "px";
+	d.style.width = (r.Max.X - r.Min.X) + "px";
+	d.style.height = (r.Max.Y - r.Min.Y) + "px";
+	return d;
+}
+
+function render() {
+	var tree = document.getElementById("tree");
+	var view = document.getElementById("view");
+	var details = document.getElementById("details");
+	tree.textContent = "";
+	view.textContent = "";
+	details.textContent = "";
+	if (!root) return;
+	view.style.width = (root["screen-rect"].Max.X) + "px";
+	view.style.height = (root["screen-rect"].Max.Y) + "px";
+	(function walk(n, depth) {
+		var row = document.createElement("div");
+		row.textContent = label(n);
+		row.style.paddingLeft = (depth * 12) + "px";
+		var b = box(n["screen-rect"]);
+		if (n.index === selected) {
+			row.className = b.className = "sel";
+			details.textContent = label(n) + "\n" + JSON.stringify(n, function(k, v) {
+				return k === "children" ? undefined : v;
+			}, 2);
+			(n.lines || []).forEach(function(l) {
+				var o = n["screen-rect"].Min;
+				view.appendChild(box({
+					Min: {X: l.rect.Min.X + o.X, Y: l.rect.Min.Y + o.Y},
+					Max: {X: l.rect.Max.X + o.X, Y: l.rect.Max.Y + o.Y}
+				}, "line"));
+			});
+		}
+		row.onclick = b.onclick = function(e) {
+			e.stopPropagation();
+			selected = n.index;
+			render();
+		};
+		tree.appendChild(row);
+		view.appendChild(b);
+		(n.children || []).forEach(function(c) { walk(c, depth + 1); });
+	})(root, 0);
+}
+
+function load() {
+	fetch("layout.json", {cache: "no-store"}).then(function(r) {
+		if (!r.ok) throw new Error(r.statusText);
+		return r.json();
+	}).then(function(j) {
+		root = j;
+		render();
+	}).catch(function(err) {
+		document.getElementById("details").textContent = "error: " + err.message;
+	});
+}
+
+document.getElementById("reload").onclick = load;
+load();
+</script>
+</body>
+</html>
+`
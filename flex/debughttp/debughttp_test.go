@@ -0,0 +1,127 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debughttp
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+func testTree() (*flex.Flex, *flex.Sync) {
+	fl := flex.NewFlex()
+	fl.Justify = flex.JustifyEnd
+	inner := flex.NewFlex()
+	inner.LayoutData = flex.LayoutData{Grow: 1}
+	inner.AppendChild(widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node)
+	fl.AppendChild(&inner.Node)
+	fl.AppendChild(widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(20)).Node)
+	s := new(flex.Sync)
+	s.Layout(&fl.Node, nil, image.Pt(100, 50))
+	return fl, s
+}
+
+func TestServeJSON(t *testing.T) {
+	_, s := testTree()
+	srv := httptest.NewServer(&Handler{Result: s.Result})
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var root Node
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		t.Fatal(err)
+	}
+
+	if root.Type != "flex" || root.Rect != image.Rect(0, 0, 100, 50) || root.LayoutData != nil {
+		t.Errorf("root = %+v", root)
+	}
+	if len(root.Lines) != 1 || root.Lines[0].End != 2 {
+		t.Errorf("root lines = %+v", root.Lines)
+	}
+	if len(root.Children) != 2 {
+		t.Fatalf("root has %d children", len(root.Children))
+	}
+	inner := root.Children[0]
+	if inner.Index != 1 || inner.LayoutData == nil || inner.LayoutData.Grow != 1 {
+		t.Errorf("inner = %+v", inner)
+	}
+	if len(inner.Children) != 1 || inner.Children[0].Index != 2 {
+		t.Fatalf("inner children = %+v", inner.Children)
+	}
+	if got := root.Children[1]; got.Index != 3 || got.Rect != image.Rect(80, 0, 100, 20) {
+		t.Errorf("second child = %+v", got)
+	}
+}
+
+func TestScreenRect(t *testing.T) {
+	fl, s := testTree()
+	fl.Direction = flex.Column
+	fl.Justify = flex.JustifyStart
+	fl.AlignItem = flex.AlignItemCenter
+	s.Layout(&fl.Node, nil, image.Pt(100, 50))
+
+	root := (&Handler{}).Tree(s.Result())
+	leaf := root.Children[0].Children[0]
+	want := root.Children[0].ScreenRect.Min.Add(leaf.Rect.Min)
+	if leaf.ScreenRect.Min != want || leaf.ScreenRect.Size() != leaf.Rect.Size() {
+		t.Errorf("leaf ScreenRect = %v, Rect = %v, want origin %v", leaf.ScreenRect, leaf.Rect, want)
+	}
+}
+
+func TestServeViewer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Handler{}).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/layout/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `fetch("layout.json"`) {
+		t.Errorf("viewer: %d %.100q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	(&Handler{}).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/layout/layout.json", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("layout.json with no Result: %d", rec.Code)
+	}
+}
+
+func TestStart(t *testing.T) {
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "example.com:80", "localhost"} {
+		if l, err := Start(addr, &Handler{}); err == nil {
+			l.Close()
+			t.Errorf("Start(%q) succeeded", addr)
+		}
+	}
+
+	_, s := testTree()
+	l, err := Start("127.0.0.1:0", &Handler{Result: s.Result})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	resp, err := http.Get("http://" + l.Addr().String() + "/layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(b), `"screen-rect"`) {
+		t.Errorf("GET layout.json: %s %s", resp.Status, b)
+	}
+}
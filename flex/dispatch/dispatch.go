@@ -0,0 +1,209 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dispatch routes mouse and touch events through a laid-out
+// widget tree.
+//
+// Like DOM events, each event is dispatched along the path from the
+// root to the deepest node under the pointer, found by flex.HitTest:
+// first down the path to capturing handlers, then to the target, then
+// back up to bubbling handlers. Any handler may stop the event from
+// going further. A handler may also grab the pointer, so that the
+// pointer's events go to its node until the pointer is released, as a
+// split pane or scrollbar does while it is dragged.
+package dispatch
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/touch"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Phase is the phase of the dispatch of an Event.
+type Phase int8
+
+// Possible values of Phase.
+const (
+	Capturing Phase = iota // going down from the root to the target
+	AtTarget               // at the target
+	Bubbling               // going up from the target to the root
+)
+
+// Pointer identifies a pointer: the mouse, or a touch by its sequence.
+type Pointer int64
+
+// Mouse is the Pointer of mouse events.
+const Mouse Pointer = -1
+
+// Event is an event being dispatched to a node.
+type Event struct {
+	// Event is the mouse.Event or touch.Event dispatched.
+	Event interface{}
+
+	Pointer Pointer
+
+	// Pos is the position of the pointer, in the coordinates of the
+	// root's Rect, and Local is the same position relative to
+	// Node.Rect.Min.
+	Pos, Local image.Point
+
+	// Target is the deepest node under the pointer, or the node that
+	// grabbed it, and Node is the node the event is dispatched to.
+	Target, Node *widget.Node
+	Phase        Phase
+
+	d       *Dispatcher
+	stopped bool
+}
+
+// StopPropagation stops the event from being dispatched to any other
+// node.
+func (e *Event) StopPropagation() { e.stopped = true }
+
+// Grab directs the events of e's pointer to e.Node until it is
+// released, wherever it moves. The grabbing node receives them
+// AtTarget, without capturing or bubbling.
+func (e *Event) Grab() { e.d.grabs[e.Pointer] = e.Node }
+
+// Handler is implemented by the Class of a node that handles pointer
+// events in the AtTarget and Bubbling phases.
+type Handler interface {
+	PointerEvent(n *widget.Node, e *Event)
+}
+
+// Dispatcher dispatches pointer events to the tree rooted at Root.
+type Dispatcher struct {
+	Root *widget.Node
+
+	bubble  map[*widget.Node]func(*Event)
+	capture map[*widget.Node]func(*Event)
+	grabs   map[Pointer]*widget.Node
+}
+
+// Handle sets f to handle the events of n in the AtTarget and Bubbling
+// phases, in place of n's Class. A nil f removes it.
+func (d *Dispatcher) Handle(n *widget.Node, f func(e *Event)) {
+	d.bubble = setHandler(d.bubble, n, f)
+}
+
+// HandleCapture sets f to handle the events of n's descendants in the
+// Capturing phase, before they reach their target. A nil f removes it.
+func (d *Dispatcher) HandleCapture(n *widget.Node, f func(e *Event)) {
+	d.capture = setHandler(d.capture, n, f)
+}
+
+func setHandler(m map[*widget.Node]func(*Event), n *widget.Node, f func(*Event)) map[*widget.Node]func(*Event) {
+	if f == nil {
+		delete(m, n)
+		return m
+	}
+	if m == nil {
+		m = make(map[*widget.Node]func(*Event))
+	}
+	m[n] = f
+	return m
+}
+
+// Grabbed returns the node that grabbed p, or nil.
+func (d *Dispatcher) Grabbed(p Pointer) *widget.Node {
+	return d.grabs[p]
+}
+
+// Ungrab releases a grab of p before the pointer is released.
+func (d *Dispatcher) Ungrab(p Pointer) {
+	delete(d.grabs, p)
+}
+
+// Dispatch dispatches e, a mouse.Event or touch.Event, and reports
+// whether any handler received it. Other events are ignored.
+//
+// A grab ends after the release of a mouse button or the end of a
+// touch is dispatched.
+func (d *Dispatcher) Dispatch(e interface{}) bool {
+	var (
+		ptr Pointer
+		pos image.Point
+		end bool
+	)
+	switch e := e.(type) {
+	case mouse.Event:
+		ptr, pos = Mouse, image.Pt(int(e.X), int(e.Y))
+		end = e.Direction == mouse.DirRelease && !e.Button.IsWheel()
+	case touch.Event:
+		ptr, pos = Pointer(e.Sequence), image.Pt(int(e.X), int(e.Y))
+		end = e.Type == touch.TypeEnd
+	default:
+		return false
+	}
+	if d.grabs == nil {
+		d.grabs = make(map[Pointer]*widget.Node)
+	}
+	if end {
+		defer delete(d.grabs, ptr)
+	}
+
+	ev := &Event{Event: e, Pointer: ptr, Pos: pos, d: d}
+	if g := d.grabs[ptr]; g != nil {
+		ev.Target, ev.Phase = g, AtTarget
+		return d.send(g, ev, d.bubble)
+	}
+
+	path := flex.HitTest(d.Root, pos)
+	if len(path) == 0 {
+		return false
+	}
+	ev.Target = path[len(path)-1]
+	handled := false
+	ev.Phase = Capturing
+	for _, n := range path[:len(path)-1] {
+		handled = d.send(n, ev, d.capture) || handled
+		if ev.stopped {
+			return handled
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		ev.Phase = Bubbling
+		if i == len(path)-1 {
+			ev.Phase = AtTarget
+		}
+		handled = d.send(path[i], ev, d.bubble) || handled
+		if ev.stopped {
+			break
+		}
+	}
+	return handled
+}
+
+// send dispatches e to n with the handler of n in m, or for the
+// AtTarget and Bubbling phases, n's Class. It reports whether there
+// was a handler.
+func (d *Dispatcher) send(n *widget.Node, e *Event, m map[*widget.Node]func(*Event)) bool {
+	e.Node = n
+	e.Local = e.Pos.Sub(d.origin(n))
+	if f := m[n]; f != nil {
+		f(e)
+		return true
+	}
+	if e.Phase == Capturing {
+		return false
+	}
+	if h, ok := n.Class.(Handler); ok {
+		h.PointerEvent(n, e)
+		return true
+	}
+	return false
+}
+
+// origin returns n's Rect.Min in the coordinates of the root's Rect.
+func (d *Dispatcher) origin(n *widget.Node) image.Point {
+	if n == d.Root {
+		return n.Rect.Min
+	}
+	r, _ := flex.RectIn(n, d.Root)
+	return r.Min.Add(d.Root.Rect.Min)
+}
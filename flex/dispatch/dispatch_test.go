@@ -0,0 +1,128 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatch
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/touch"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+var phaseNames = [...]string{"capture", "target", "bubble"}
+
+func TestDispatch(t *testing.T) {
+	root := flex.NewFlex()
+	inner := flex.NewFlex()
+	a := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(40)).Node
+	b := widget.NewUniform(color.White, unit.Pixels(40), unit.Pixels(40)).Node
+	inner.AppendChild(a)
+	root.AppendChild(&inner.Node)
+	root.AppendChild(b)
+	root.Rect = image.Rect(10, 10, 110, 60)
+	root.Class.Measure(&root.Node, nil)
+	root.Class.Layout(&root.Node, nil)
+
+	d := &Dispatcher{Root: &root.Node}
+	var got []string
+	record := func(name string, f func(e *Event)) func(e *Event) {
+		return func(e *Event) {
+			got = append(got, fmt.Sprintf("%s %s %v", name, phaseNames[e.Phase], e.Local))
+			if f != nil {
+				f(e)
+			}
+		}
+	}
+	d.HandleCapture(&root.Node, record("root", nil))
+	d.HandleCapture(&inner.Node, record("inner", nil))
+	d.Handle(&root.Node, record("root", nil))
+	d.Handle(&inner.Node, record("inner", nil))
+	d.Handle(a, record("a", nil))
+
+	check := func(what string, want ...string) {
+		t.Helper()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, want %q", what, got, want)
+		}
+		got = nil
+	}
+
+	press := mouse.Event{X: 15, Y: 15, Button: mouse.ButtonLeft, Direction: mouse.DirPress}
+	if !d.Dispatch(press) {
+		t.Error("press not handled")
+	}
+	check("press",
+		"root capture (5,5)",
+		"inner capture (5,5)",
+		"a target (5,5)",
+		"inner bubble (5,5)",
+		"root bubble (5,5)",
+	)
+
+	// b has no handler, so it reaches only its ancestors.
+	d.Dispatch(mouse.Event{X: 55, Y: 15})
+	check("over b", "root capture (45,5)", "root bubble (45,5)")
+
+	// Stopping propagation while capturing.
+	d.HandleCapture(&inner.Node, record("inner", (*Event).StopPropagation))
+	d.Dispatch(press)
+	check("stopped", "root capture (5,5)", "inner capture (5,5)")
+	d.HandleCapture(&inner.Node, nil)
+
+	// a grabs the mouse on press, and gets its events until release.
+	d.Handle(a, record("a", func(e *Event) {
+		if me, ok := e.Event.(mouse.Event); ok && me.Direction == mouse.DirPress {
+			e.Grab()
+		}
+	}))
+	d.Dispatch(press)
+	got = nil
+	if d.Grabbed(Mouse) != a {
+		t.Fatal("a did not grab the mouse")
+	}
+	d.Dispatch(mouse.Event{X: 80, Y: 15})
+	d.Dispatch(mouse.Event{X: 80, Y: 15, Button: mouse.ButtonLeft, Direction: mouse.DirRelease})
+	check("grabbed", "a target (70,5)", "a target (70,5)")
+	if d.Grabbed(Mouse) != nil {
+		t.Error("grab not released")
+	}
+
+	// Touches outside the root go nowhere.
+	if d.Dispatch(touch.Event{X: 200, Y: 200, Type: touch.TypeBegin}) {
+		t.Error("touch outside the root handled")
+	}
+	check("outside")
+}
+
+func TestPointerEventsNone(t *testing.T) {
+	root := flex.NewFlex()
+	below := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(40)).Node
+	overlay := widget.NewUniform(color.White, unit.Pixels(40), unit.Pixels(40)).Node
+	overlay.LayoutData = flex.LayoutData{Offset: image.Pt(-40, 0), PointerEvents: flex.PointerNone}
+	root.AppendChild(below)
+	root.AppendChild(overlay)
+	root.Rect = image.Rect(0, 0, 100, 50)
+	root.Class.Measure(&root.Node, nil)
+	root.Class.Layout(&root.Node, nil)
+
+	d := &Dispatcher{Root: &root.Node}
+	var got []*widget.Node
+	d.Handle(below, func(e *Event) { got = append(got, e.Target) })
+	d.Handle(overlay, func(e *Event) { got = append(got, e.Target) })
+
+	// The overlay is painted over below, but the press goes through it.
+	d.Dispatch(mouse.Event{X: 10, Y: 10, Button: mouse.ButtonLeft, Direction: mouse.DirPress})
+	if len(got) != 1 || got[0] != below {
+		t.Errorf("press went to %v, want below %p", got, below)
+	}
+}
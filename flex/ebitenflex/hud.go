@@ -0,0 +1,126 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ebitenflex lays out Ebiten game HUDs and menus described as
+// flex widget trees.
+//
+// Games do not run shiny's widget lifecycle, so a HUD holds the tree
+// and lays it out on demand: each frame the game calls Layout with the
+// window size and queries the Rect of the nodes it draws. The tree is
+// only laid out again when the window size changes or a part of it is
+// invalidated.
+//
+// The package does not import ebiten. HUD.Layout has the signature of
+// the Layout method of ebiten.Game, so a game can forward to it.
+package ebitenflex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// HUD holds a widget tree laid out to fill the game screen.
+type HUD struct {
+	Root  *widget.Node
+	Theme *widget.Theme
+
+	size    image.Point
+	laidOut bool
+	pending []*widget.Node
+	rects   map[*widget.Node]image.Rectangle
+}
+
+// New returns a HUD for the tree rooted at root.
+func New(root *widget.Node) *HUD {
+	return &HUD{Root: root}
+}
+
+// Invalidate records that n, its LayoutData or its children have
+// changed, so the part of the tree affected by n is laid out again by
+// the next call to Layout.
+//
+// If n is nil, the whole tree is laid out again.
+func (h *HUD) Invalidate(n *widget.Node) {
+	if n == nil {
+		h.laidOut = false
+		h.pending = nil
+		return
+	}
+	h.pending = append(h.pending, n)
+}
+
+// Layout lays out the tree to fill a screen of the given size, if the
+// size has changed or the tree has been invalidated. It returns the
+// size unchanged, so a game whose logical screen matches its window can
+// return the result from its own Layout method.
+func (h *HUD) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	size := image.Pt(outsideWidth, outsideHeight)
+	switch {
+	case !h.laidOut || size != h.size:
+		h.size = size
+		h.Root.Class.Measure(h.Root, h.Theme)
+		h.Root.Rect = image.Rectangle{Max: size}
+		h.Root.Class.Layout(h.Root, h.Theme)
+		h.laidOut = true
+		h.pending = nil
+		h.rects = nil
+	case len(h.pending) > 0:
+		for _, n := range h.pending {
+			h.relayout(n)
+		}
+		h.pending = nil
+		h.rects = nil
+	}
+	return outsideWidth, outsideHeight
+}
+
+// relayout lays out the smallest subtree affected by a change to n.
+//
+// A change to a node can change how its parent places its children, so
+// the parent is measured again. If that changes the parent's
+// MeasuredSize, its own parent is affected in turn, and so on up the
+// tree. The highest affected node keeps its Rect and is laid out again.
+func (h *HUD) relayout(n *widget.Node) {
+	if n.Parent != nil {
+		n = n.Parent
+	}
+	for {
+		old := n.MeasuredSize
+		n.Class.Measure(n, h.Theme)
+		if n.Parent == nil || n.MeasuredSize == old {
+			break
+		}
+		n = n.Parent
+	}
+	n.Class.Layout(n, h.Theme)
+}
+
+// Err returns the error of the last call to Layout, if any, as
+// reported by flex.CheckLayout.
+func (h *HUD) Err() error {
+	return flex.CheckLayout(h.Root)
+}
+
+// Rect returns the rectangle occupied by n, in screen coordinates, as
+// of the last call to Layout.
+func (h *HUD) Rect(n *widget.Node) image.Rectangle {
+	if h.rects == nil {
+		h.rects = make(map[*widget.Node]image.Rectangle)
+		h.walk(h.Root, image.Point{})
+	}
+	return h.rects[n]
+}
+
+// walk records the screen rectangle of n and its descendants, where
+// origin is the screen position of n's parent.
+func (h *HUD) walk(n *widget.Node, origin image.Point) {
+	r := n.Rect.Add(origin)
+	h.rects[n] = r
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		h.walk(c, r.Min)
+	}
+}
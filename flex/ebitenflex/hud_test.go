@@ -0,0 +1,82 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ebitenflex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// countingClass counts calls to Layout.
+type countingClass struct {
+	widget.Class
+	layouts int
+}
+
+func (k *countingClass) Layout(n *widget.Node, t *widget.Theme) {
+	k.layouts++
+	k.Class.Layout(n, t)
+}
+
+func newTile(w, h float64) *widget.Node {
+	return widget.NewUniform(color.Black, unit.Pixels(w), unit.Pixels(h)).Node
+}
+
+func TestHUD(t *testing.T) {
+	// A column with a top bar, and a row holding a health bar.
+	root, err := flex.ParseStyle("flex-direction: column")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bar := newTile(10, 20)
+	row, _ := flex.ParseStyle("justify-content: flex-end")
+	row.LayoutData = flex.LayoutData{Grow: 1, Align: flex.AlignItemStretch}
+	health := newTile(50, 10)
+	root.AppendChild(bar)
+	root.AppendChild(&row.Node)
+	row.AppendChild(health)
+
+	rootClass := &countingClass{Class: root.Class}
+	root.Class = rootClass
+	rowClass := &countingClass{Class: row.Class}
+	row.Class = rowClass
+
+	h := New(&root.Node)
+	if w, ht := h.Layout(320, 240); w != 320 || ht != 240 {
+		t.Errorf("Layout returned %d, %d", w, ht)
+	}
+	if got, want := h.Rect(health), image.Rect(270, 20, 320, 30); got != want {
+		t.Errorf("health Rect=%v, want %v", got, want)
+	}
+
+	h.Layout(320, 240)
+	if rootClass.layouts != 1 || rowClass.layouts != 1 {
+		t.Errorf("unchanged Layout ran again: root %d, row %d", rootClass.layouts, rowClass.layouts)
+	}
+
+	health.LayoutData = flex.LayoutData{Grow: 1}
+	h.Invalidate(health)
+	h.Layout(320, 240)
+	if rootClass.layouts != 1 || rowClass.layouts != 2 {
+		t.Errorf("after Invalidate: root %d layouts, row %d, want 1, 2", rootClass.layouts, rowClass.layouts)
+	}
+	if got, want := h.Rect(health), image.Rect(0, 20, 320, 30); got != want {
+		t.Errorf("grown health Rect=%v, want %v", got, want)
+	}
+
+	h.Layout(640, 480)
+	if rootClass.layouts != 2 {
+		t.Errorf("resize: root %d layouts, want 2", rootClass.layouts)
+	}
+	if got, want := h.Rect(health), image.Rect(0, 20, 640, 30); got != want {
+		t.Errorf("resized health Rect=%v, want %v", got, want)
+	}
+}
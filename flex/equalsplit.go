@@ -0,0 +1,76 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+// EqualSplit configures fl and its current children so that the
+// children share the container's main size equally, less any gaps,
+// whatever their measured sizes.
+//
+// Sizes are whole pixels. When the space does not divide evenly, each
+// edge between children is placed on the first pixel at or after its
+// exact position, so the extra pixels are spread evenly, sizes differ
+// by at most one, and the children exactly fill the container. The
+// same container size always gives the same split.
+//
+// Each child is given a grow and shrink factor of 1 and a definite
+// basis of 0, and its Fraction, its BasisClamp, its main axis minimum
+// and maximum sizes and margins, its Pin and its Breakpoints are
+// cleared, so that every child takes part in the split. Its other
+// LayoutData, such as Align, is kept. Children added to fl later must
+// be configured by calling EqualSplit again.
+func EqualSplit(fl *Flex) {
+	fl.Wrap = NoWrap
+	one := 1.0
+	for c := fl.FirstChild; c != nil; c = c.NextSibling {
+		d := fl.itemData(c)
+		d.Grow = 1
+		d.Fraction = 0
+		d.Shrink = &one
+		d.Basis, d.BasisPx = Definite, 0
+		d.BasisClamp = nil
+		d.BreakAfter = false
+		d.Pin = PinNone
+		d.Breakpoints = nil
+		switch fl.Direction {
+		case Row, RowReverse:
+			d.MinSize.X = 0
+			d.Margin.Left, d.Margin.Right = 0, 0
+			if d.MaxSize != nil {
+				max := *d.MaxSize
+				max.X = Unbounded
+				d.MaxSize = &max
+			}
+			if d.MinLength != nil {
+				min := *d.MinLength
+				min.Width = Length{}
+				d.MinLength = &min
+			}
+			if d.MaxLength != nil {
+				max := *d.MaxLength
+				max.Width = Length{}
+				d.MaxLength = &max
+			}
+		default:
+			d.MinSize.Y = 0
+			d.Margin.Top, d.Margin.Bottom = 0, 0
+			if d.MaxSize != nil {
+				max := *d.MaxSize
+				max.Y = Unbounded
+				d.MaxSize = &max
+			}
+			if d.MinLength != nil {
+				min := *d.MinLength
+				min.Height = Length{}
+				d.MinLength = &min
+			}
+			if d.MaxLength != nil {
+				max := *d.MaxLength
+				max.Height = Length{}
+				d.MaxLength = &max
+			}
+		}
+		c.LayoutData = d
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestEqualSplit(t *testing.T) {
+	for _, dir := range []Direction{Row, RowReverse, Column, ColumnReverse} {
+		for n := 1; n <= 12; n++ {
+			for _, gap := range []int{0, 3} {
+				for size := 0; size <= 200; size++ {
+					fl := NewFlex()
+					fl.Direction = dir
+					fl.RowGap, fl.ColumnGap = gap, gap
+					var children []*widget.Node
+					for i := 0; i < n; i++ {
+						// Varied measured sizes and constraints must not matter.
+						c := widget.NewUniform(color.Black, unit.Pixels(float64(7*i)), unit.Pixels(10)).Node
+						c.LayoutData = LayoutData{MinSize: image.Pt(i, i), Grow: float64(i)}
+						children = append(children, c)
+						fl.AppendChild(c)
+					}
+					EqualSplit(fl)
+					fl.Class.Measure(&fl.Node, nil)
+					fl.Rect = image.Rect(0, 0, size, size)
+					fl.Class.Layout(&fl.Node, nil)
+
+					space := size - gap*(n-1)
+					if space < 0 {
+						continue
+					}
+					visual := fl.VisualOrder()
+					for i, c := range visual {
+						// Edge i is at the pixel at or after i*space/n.
+						want := ((i+1)*space+n-1)/n - (i*space+n-1)/n
+						got := c.Rect.Dx()
+						if dir == Column || dir == ColumnReverse {
+							got = c.Rect.Dy()
+						}
+						if got != want {
+							t.Fatalf("%v n=%d gap=%d size=%d: child %d (visual) is %d, want %d", dir, n, gap, size, i, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestEqualSplitClears checks that EqualSplit clears the LayoutData
+// that would otherwise make the split unequal.
+func TestEqualSplitClears(t *testing.T) {
+	for _, d := range []LayoutData{
+		{BasisClamp: &BasisClamp{Min: Length{Kind: LengthValue, Value: unit.Pixels(70)}}},
+		{Fraction: 3},
+		{Margin: Insets{Left: 6, Right: 4}},
+		{Pin: PinEnd},
+	} {
+		fl := NewFlex()
+		a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+		b := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+		a.LayoutData = d
+		fl.AppendChild(a)
+		fl.AppendChild(b)
+		EqualSplit(fl)
+		fl.Class.Measure(&fl.Node, nil)
+		fl.Rect = image.Rect(0, 0, 100, 10)
+		fl.Class.Layout(&fl.Node, nil)
+		if a.Rect != image.Rect(0, 0, 50, 10) || b.Rect != image.Rect(50, 0, 100, 10) {
+			t.Errorf("%+v: Rects %v and %v, want an equal split", d, a.Rect, b.Rect)
+		}
+	}
+}
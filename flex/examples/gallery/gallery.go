@@ -0,0 +1,454 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/build"
+	"github.com/crawshaw/exp/flex/dispatch"
+	"github.com/crawshaw/exp/flex/flexwidget"
+)
+
+// Colors of the gallery.
+var (
+	navSelected = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	controlBg   = color.RGBA{0xd8, 0xe4, 0xf8, 0xff}
+	boxColors   = []color.Color{
+		color.RGBA{0xe5, 0x73, 0x73, 0xff},
+		color.RGBA{0x64, 0xb5, 0xf6, 0xff},
+		color.RGBA{0x81, 0xc7, 0x84, 0xff},
+		color.RGBA{0xff, 0xb7, 0x4d, 0xff},
+		color.RGBA{0xba, 0x68, 0xc8, 0xff},
+		color.RGBA{0x4d, 0xb6, 0xac, 0xff},
+	}
+)
+
+// A page shows one feature of the flex layout: a demo Flex, above
+// the controls that change it.
+type page struct {
+	title    string
+	about    string
+	demo     *flex.Flex
+	controls []*control
+}
+
+// A control is a button that steps a property through a list of
+// values, one per click, wrapping around at the end.
+type control struct {
+	name   string
+	values []string
+	set    func(i int)
+	i      int
+
+	button *flexwidget.Button
+}
+
+// click moves the control to its next value.
+func (c *control) click() {
+	c.i = (c.i + 1) % len(c.values)
+	c.set(c.i)
+	c.button.Label.Text = c.label()
+}
+
+func (c *control) label() string {
+	return c.name + ": " + c.values[c.i]
+}
+
+// gallery is the widget tree of the app: a column of buttons, one per
+// page, beside a Deck showing the selected page.
+type gallery struct {
+	root    *flex.Flex
+	deck    *flexwidget.Deck
+	screens []*page
+	nav     []*flexwidget.Button
+
+	d       dispatch.Dispatcher
+	hovered *flexwidget.Button // the button under the mouse
+	hit     *flexwidget.Button // the button the last event went to
+	changed bool               // a button changed while dispatching
+}
+
+func newGallery() *gallery {
+	g := &gallery{
+		screens: []*page{
+			growShrinkScreen(),
+			wrapScreen(),
+			justifyScreen(),
+			alignScreen(),
+			gapsScreen(),
+			nestingScreen(),
+			reverseScreen(),
+		},
+	}
+
+	nav := build.Column(build.AlignItems(flex.AlignItemStretch), build.Gap(2))
+	var pages []*widget.Node
+	for i, s := range g.screens {
+		i := i
+		b := flexwidget.NewButton(s.title, func() { g.show(i) })
+		g.nav = append(g.nav, b)
+		g.handle(b)
+		build.Of(&b.Node)(nav)
+		pages = append(pages, &g.newPage(s).Node)
+	}
+	g.deck = flexwidget.NewDeck(pages...)
+
+	g.root = build.Row(build.AlignItems(flex.AlignItemStretch),
+		build.Of(nav, build.Shrink(0)),
+		build.Of(&g.deck.Node, build.Grow(1), build.Basis(0)),
+	)
+	g.d.Root = &g.root.Node
+	g.show(0)
+	return g
+}
+
+// newPage returns the node of the Deck showing s.
+func (g *gallery) newPage(s *page) *flex.Flex {
+	title := flexwidget.NewLabel(s.title)
+	about := flexwidget.NewLabel(s.about)
+	controls := build.Row(build.Wrap(flex.Wrap), build.Gap(4))
+	for _, c := range s.controls {
+		// Start the demo at the values the controls show.
+		c.set(c.i)
+		c.button = flexwidget.NewButton(c.label(), c.click)
+		c.button.Background = controlBg
+		g.handle(c.button)
+		build.Of(&c.button.Node)(controls)
+	}
+	return build.Column(build.AlignItems(flex.AlignItemStretch), build.Gap(8),
+		build.SafeArea(flex.Insets{Top: 8, Right: 8, Bottom: 8, Left: 8}),
+		build.Of(&title.Node),
+		build.Of(&about.Node),
+		build.Of(controls),
+		build.Of(s.demo, build.Grow(1), build.Basis(0)),
+	)
+}
+
+// show shows the i'th page.
+func (g *gallery) show(i int) {
+	g.deck.Show(i)
+	for j, b := range g.nav {
+		b.Background = nil
+		if j == i {
+			b.Background = navSelected
+		}
+	}
+}
+
+// handle routes the mouse events of b to it.
+func (g *gallery) handle(b *flexwidget.Button) {
+	g.d.Handle(&b.Node, func(e *dispatch.Event) {
+		me, ok := e.Event.(mouse.Event)
+		if !ok {
+			return
+		}
+		e.StopPropagation()
+		over := e.Local.In(image.Rectangle{Max: b.Rect.Size()})
+		if over {
+			g.hit = b
+		}
+		if b.SetHovered(over) {
+			g.changed = true
+		}
+		switch me.Direction {
+		case mouse.DirPress:
+			e.Grab()
+			if b.Press() {
+				g.changed = true
+			}
+		case mouse.DirRelease:
+			if b.Release() {
+				g.changed = true
+			}
+		}
+	})
+}
+
+// event handles a window event, and reports whether the tree changed.
+func (g *gallery) event(e interface{}) bool {
+	if _, ok := e.(mouse.Event); !ok {
+		return false
+	}
+	g.hit, g.changed = nil, false
+	g.d.Dispatch(e)
+	if g.hovered != nil && g.hovered != g.hit && g.hovered.SetHovered(false) {
+		g.changed = true
+	}
+	g.hovered = g.hit
+	return g.changed
+}
+
+// box returns a numbered, colored leaf for the demos.
+func box(i int) *widget.Node {
+	b := flexwidget.NewButton(fmt.Sprint(i+1), nil)
+	b.Background = boxColors[i%len(boxColors)]
+	b.HoverBackground = b.Background
+	return &b.Node
+}
+
+// item returns the LayoutData of c, a child of a Flex.
+func item(c *widget.Node) flex.LayoutData {
+	d, _ := c.LayoutData.(flex.LayoutData)
+	return d
+}
+
+// enumControl returns a control that sets an enum of n values, named
+// by str.
+func enumControl(name string, n int, str func(i int) string, set func(i int)) *control {
+	c := &control{name: name, set: set}
+	for i := 0; i < n; i++ {
+		c.values = append(c.values, str(i))
+	}
+	return c
+}
+
+// intControl returns a control that sets an integer to one of values.
+func intControl(name string, values []int, set func(v int)) *control {
+	c := &control{name: name, set: func(i int) { set(values[i]) }}
+	for _, v := range values {
+		c.values = append(c.values, fmt.Sprint(v))
+	}
+	return c
+}
+
+// boolControl returns a control that turns a property off and on.
+func boolControl(name string, set func(on bool)) *control {
+	return &control{name: name, values: []string{"off", "on"}, set: func(i int) { set(i == 1) }}
+}
+
+func directionControl(fl *flex.Flex) *control {
+	return enumControl("flex-direction", 4,
+		func(i int) string { return flex.Direction(i).String() },
+		func(i int) { fl.Direction = flex.Direction(i) })
+}
+
+func justifyControl(fl *flex.Flex) *control {
+	return enumControl("justify-content", 5,
+		func(i int) string { return flex.Justify(i).String() },
+		func(i int) { fl.Justify = flex.Justify(i) })
+}
+
+// alignItems are the values of align-items, which has no auto.
+var alignItems = []flex.AlignItem{
+	flex.AlignItemStart,
+	flex.AlignItemEnd,
+	flex.AlignItemCenter,
+	flex.AlignItemBaseline,
+	flex.AlignItemStretch,
+	flex.AlignItemLastBaseline,
+}
+
+func alignItemsControl(fl *flex.Flex) *control {
+	return enumControl("align-items", len(alignItems),
+		func(i int) string { return alignItems[i].String() },
+		func(i int) { fl.AlignItem = alignItems[i] })
+}
+
+func alignContentControl(fl *flex.Flex) *control {
+	return enumControl("align-content", 6,
+		func(i int) string { return flex.AlignContent(i).String() },
+		func(i int) { fl.AlignContent = flex.AlignContent(i) })
+}
+
+func wrapControl(fl *flex.Flex) *control {
+	return enumControl("flex-wrap", 3,
+		func(i int) string { return flex.FlexWrap(i).String() },
+		func(i int) { fl.Wrap = flex.FlexWrap(i) })
+}
+
+func safeControl(fl *flex.Flex) *control {
+	return boolControl("safe", func(on bool) { fl.Safe = on })
+}
+
+func growShrinkScreen() *page {
+	fl := build.Row()
+	var boxes []*widget.Node
+	for i := 0; i < 3; i++ {
+		b := box(i)
+		build.Of(b, build.MinSize(20, 40), build.Basis(120))(fl)
+		boxes = append(boxes, b)
+	}
+	s := &page{
+		title: "Grow and shrink",
+		about: "Free space is shared out by flex-grow; overflow is taken back by flex-shrink, weighted by the basis.",
+		demo:  fl,
+		controls: []*control{
+			intControl("flex-basis", []int{120, 60, 240}, func(v int) {
+				for _, b := range boxes {
+					d := item(b)
+					d.BasisPx = v
+					fl.SetLayoutData(b, d)
+				}
+			}),
+		},
+	}
+	for i, b := range boxes {
+		b := b
+		s.controls = append(s.controls,
+			intControl(fmt.Sprintf("%d: flex-grow", i+1), []int{0, 1, 2}, func(v int) {
+				d := item(b)
+				d.Grow = float64(v)
+				fl.SetLayoutData(b, d)
+			}),
+			intControl(fmt.Sprintf("%d: flex-shrink", i+1), []int{1, 0, 3}, func(v int) {
+				d := item(b)
+				f := float64(v)
+				d.Shrink = &f
+				fl.SetLayoutData(b, d)
+			}),
+		)
+	}
+	return s
+}
+
+func wrapScreen() *page {
+	fl := build.Row(build.Wrap(flex.Wrap), build.Gap(4))
+	for i := 0; i < 14; i++ {
+		build.Of(box(i), build.MinSize(40+i%4*25, 30+i%3*15))(fl)
+	}
+	return &page{
+		title:    "Wrap",
+		about:    "Items that do not fit break onto new lines, which align-content spreads across the container.",
+		demo:     fl,
+		controls: []*control{wrapControl(fl), alignContentControl(fl), directionControl(fl)},
+	}
+}
+
+func justifyScreen() *page {
+	fl := build.Row()
+	for i := 0; i < 4; i++ {
+		build.Of(box(i), build.MinSize(60, 40))(fl)
+	}
+	return &page{
+		title: "Justify",
+		about: "justify-content places items along the main axis. With safe on, content that overflows starts at the start edge.",
+		demo:  fl,
+		controls: []*control{
+			justifyControl(fl),
+			safeControl(fl),
+			intControl("items", []int{4, 12}, func(n int) {
+				for fl.FirstChild != nil {
+					fl.RemoveChild(fl.FirstChild)
+				}
+				for i := 0; i < n; i++ {
+					build.Of(box(i), build.MinSize(60, 40))(fl)
+				}
+			}),
+		},
+	}
+}
+
+func alignScreen() *page {
+	fl := build.Row(build.Gap(4))
+	var boxes []*widget.Node
+	for i := 0; i < 5; i++ {
+		b := box(i)
+		build.Of(b, build.MinSize(50, 30+i*20))(fl)
+		boxes = append(boxes, b)
+	}
+	self := boxes[2]
+	return &page{
+		title: "Align",
+		about: "align-items places items across their line; align-self overrides it for one item.",
+		demo:  fl,
+		controls: []*control{
+			alignItemsControl(fl),
+			enumControl("3: align-self", 7,
+				func(i int) string { return flex.AlignItem(i).String() },
+				func(i int) {
+					d := item(self)
+					d.Align = flex.AlignItem(i)
+					fl.SetLayoutData(self, d)
+				}),
+			safeControl(fl),
+			intControl("3: min-height", []int{70, 400}, func(v int) {
+				d := item(self)
+				d.MinSize.Y = v
+				fl.SetLayoutData(self, d)
+			}),
+		},
+	}
+}
+
+func gapsScreen() *page {
+	fl := build.Row(build.Wrap(flex.Wrap))
+	for i := 0; i < 12; i++ {
+		build.Of(box(i), build.MinSize(60, 40))(fl)
+	}
+	return &page{
+		title: "Gaps",
+		about: "row-gap and column-gap separate lines and items, as in CSS; gap steps use the Spacing scale.",
+		demo:  fl,
+		controls: []*control{
+			intControl("row-gap", []int{0, 4, 16, 32}, func(v int) { fl.RowGap = v }),
+			intControl("column-gap", []int{0, 4, 16, 32}, func(v int) { fl.ColumnGap = v }),
+			enumControl("gap", 6,
+				func(i int) string { return flex.Space(i).String() },
+				func(i int) { fl.GapSpace = flex.Space(i) }),
+		},
+	}
+}
+
+func nestingScreen() *page {
+	inner := []*flex.Flex{
+		build.Column(build.Gap(4), build.Of(box(0), build.MinSize(40, 30)), build.Of(box(1), build.MinSize(40, 30), build.Grow(1))),
+		build.Row(build.Gap(4), build.Of(box(2), build.MinSize(40, 30), build.Grow(1)), build.Of(box(3), build.MinSize(40, 30))),
+		build.Column(build.Gap(4), build.Of(box(4), build.MinSize(40, 30)), build.Of(box(5), build.MinSize(40, 30))),
+	}
+	var dirs []flex.Direction
+	for _, c := range inner {
+		dirs = append(dirs, c.Direction)
+	}
+	fl := build.Row(build.Gap(8),
+		build.Of(inner[0], build.Grow(1)),
+		build.Of(inner[1], build.Grow(2)),
+		build.Of(inner[2]),
+	)
+	return &page{
+		title: "Nesting",
+		about: "Flex containers are flex items too: each is sized by its parent, then lays out its own children.",
+		demo:  fl,
+		controls: []*control{
+			directionControl(fl),
+			alignItemsControl(fl),
+			enumControl("inner flex-direction", 2,
+				func(i int) string { return []string{"as built", "swapped"}[i] },
+				func(i int) {
+					for j, c := range inner {
+						c.Direction = dirs[j]
+						if i == 1 && c.Direction == flex.Row {
+							c.Direction = flex.Column
+						} else if i == 1 {
+							c.Direction = flex.Row
+						}
+					}
+				}),
+		},
+	}
+}
+
+func reverseScreen() *page {
+	fl := build.Row(build.Gap(4), build.Wrap(flex.Wrap))
+	for i := 0; i < 8; i++ {
+		build.Of(box(i), build.MinSize(70, 40))(fl)
+	}
+	return &page{
+		title: "Reverse",
+		about: "Reversed directions swap the main-start and main-end edges; wrap-reverse swaps the cross edges.",
+		demo:  fl,
+		controls: []*control{
+			directionControl(fl),
+			wrapControl(fl),
+			justifyControl(fl),
+		},
+	}
+}
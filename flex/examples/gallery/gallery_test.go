@@ -0,0 +1,109 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+func layout(g *gallery) {
+	root := &g.root.Node
+	root.Class.Measure(root, nil)
+	root.Rect = image.Rect(0, 0, 900, 600)
+	root.Class.Layout(root, nil)
+}
+
+// center returns the center of n in the coordinates of the root.
+func center(n *widget.Node) image.Point {
+	p := n.Rect.Min.Add(n.Rect.Size().Div(2))
+	for a := n.Parent; a != nil; a = a.Parent {
+		p = p.Add(a.Rect.Min)
+	}
+	return p
+}
+
+// click clicks the mouse at the center of n, and reports whether the
+// tree changed.
+func click(g *gallery, n *widget.Node) bool {
+	p := center(n)
+	x, y := float32(p.X), float32(p.Y)
+	changed := g.event(mouse.Event{X: x, Y: y})
+	changed = g.event(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}) || changed
+	changed = g.event(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}) || changed
+	return changed
+}
+
+func TestNavigate(t *testing.T) {
+	g := newGallery()
+	layout(g)
+	if g.deck.Shown != 0 || g.nav[0].Background != navSelected {
+		t.Fatalf("first screen not shown")
+	}
+	if !click(g, &g.nav[3].Node) {
+		t.Error("click reported no change")
+	}
+	if g.deck.Shown != 3 {
+		t.Errorf("Shown = %d after clicking %q", g.deck.Shown, g.screens[3].title)
+	}
+	if g.nav[0].Background != nil || g.nav[3].Background != navSelected {
+		t.Error("nav selection not moved")
+	}
+
+	// Moving off a button unhovers it.
+	if !g.nav[3].Hovered() {
+		t.Error("button not hovered after click")
+	}
+	if !g.event(mouse.Event{X: 899, Y: 599}) || g.nav[3].Hovered() {
+		t.Error("button still hovered after the mouse left it")
+	}
+}
+
+func TestControl(t *testing.T) {
+	g := newGallery()
+	g.show(2)
+	layout(g)
+	s := g.screens[2]
+	c := s.controls[0]
+	if !click(g, &c.button.Node) {
+		t.Error("click reported no change")
+	}
+	if s.demo.Justify != flex.JustifyEnd {
+		t.Errorf("Justify = %v after one click", s.demo.Justify)
+	}
+	if got, want := c.button.Label.Text, "justify-content: flex-end"; got != want {
+		t.Errorf("label = %q, want %q", got, want)
+	}
+}
+
+// TestControlsCycle steps every control through all its values, laying
+// out the gallery at each, and checks that it comes back to the start.
+func TestControlsCycle(t *testing.T) {
+	g := newGallery()
+	for i, s := range g.screens {
+		g.show(i)
+		for _, c := range s.controls {
+			first := c.button.Label.Text
+			for range c.values {
+				c.click()
+				layout(g)
+				for n := s.demo.FirstChild; n != nil; n = n.NextSibling {
+					if n.Rect.Empty() {
+						t.Errorf("%s: %s: a child has an empty Rect %v", s.title, c.label(), n.Rect)
+						break
+					}
+				}
+			}
+			if c.button.Label.Text != first {
+				t.Errorf("%s: control ended at %q, want %q", s.title, c.button.Label.Text, first)
+			}
+		}
+	}
+}
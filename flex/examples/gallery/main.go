@@ -0,0 +1,35 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Gallery shows the features of the flex layout, one screen each:
+// grow and shrink, wrapping, justification, alignment, gaps, nesting
+// and reversed directions.
+//
+// Each screen has buttons that step the properties of its demo through
+// their values, so that every combination can be seen live. It doubles
+// as a manual test of the layout.
+package main
+
+import (
+	"log"
+
+	"golang.org/x/exp/shiny/driver"
+	"golang.org/x/exp/shiny/screen"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+func main() {
+	driver.Main(func(s screen.Screen) {
+		g := newGallery()
+		err := flex.RunWindow(s, &g.root.Node, &flex.WindowOptions{
+			Width:  900,
+			Height: 600,
+			Event:  g.event,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	})
+}
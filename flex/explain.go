@@ -0,0 +1,272 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Clamp says whether a size was raised to a minimum or lowered to a
+// maximum.
+type Clamp int8
+
+// Possible values of Clamp.
+const (
+	ClampNone Clamp = iota
+	ClampMin
+	ClampMax
+)
+
+func clampOf(unclamped, clamped float64) Clamp {
+	switch {
+	case clamped > unclamped:
+		return ClampMin
+	case clamped < unclamped:
+		return ClampMax
+	}
+	return ClampNone
+}
+
+var clampNames = [...]string{
+	ClampNone: "none",
+	ClampMin:  "min",
+	ClampMax:  "max",
+}
+
+func (c Clamp) String() string { return enumName(clampNames[:], "Clamp", int(c)) }
+
+// CrossSource is where the hypothetical cross size of an item came
+// from.
+type CrossSource int8
+
+// Possible values of CrossSource.
+const (
+	CrossMeasured    CrossSource = iota // its MeasuredSize
+	CrossDefinite                       // LayoutData.CrossSize
+	CrossForMainSize                    // measured at its main size, as for wrapping text
+	CrossAspectRatio                    // its MinSize aspect ratio, as it was shrunk
+)
+
+var crossSourceNames = [...]string{
+	CrossMeasured:    "measured",
+	CrossDefinite:    "definite",
+	CrossForMainSize: "measured at main size",
+	CrossAspectRatio: "aspect ratio",
+}
+
+func (c CrossSource) String() string {
+	return enumName(crossSourceNames[:], "CrossSource", int(c))
+}
+
+// An Explanation says how the layout of a Flex arrived at the Rect of
+// one of its children, following the steps of the flex algorithm.
+// Sizes are in fractional pixels, before the Rect is rounded.
+type Explanation struct {
+	Child *widget.Node
+
+	// Hidden reports that the child is beyond the MaxLines of its
+	// container, and not laid out. No other field but LayoutData is
+	// set.
+	Hidden bool
+
+	// Pinned reports that the child is pinned to an edge, outside the
+	// flow. No other field but LayoutData and Rect is set.
+	Pinned bool
+
+	// LayoutData is the child's LayoutData in effect, with any
+	// Breakpoint applied and Lengths resolved. Breakpoint is the
+	// index in its Breakpoints of the one applied, or -1.
+	LayoutData LayoutData
+	Breakpoint int
+
+	// Line is the index of the flex line holding the child.
+	Line int
+
+	// FlexBaseSize is the main size the child starts from: its
+	// definite Basis (BasisDefinite) or measured main size.
+	FlexBaseSize  float64
+	BasisDefinite bool
+
+	// HypotheticalMainSize is FlexBaseSize clamped to the child's
+	// minimum and maximum main sizes.
+	HypotheticalMainSize float64
+
+	// Grow reports whether the child's line had free space to grow
+	// into, rather than overflowing and shrinking. Inflexible reports
+	// that the child did neither, keeping its HypotheticalMainSize,
+	// as it had a zero grow or shrink factor or was already clamped.
+	Grow       bool
+	Inflexible bool
+
+	// FreeSpace is the free space given to the child by growing (if
+	// positive) or taken by shrinking (if negative), before MainClamp
+	// clamped the result to the child's minimum or maximum.
+	FreeSpace float64
+	MainClamp Clamp
+	MainSize  float64
+
+	// HypotheticalCrossSize is the cross size from CrossSource,
+	// before CrossClamp clamped it to the minimum or maximum.
+	// Stretched reports that AlignItemStretch then made it as large
+	// as its line.
+	CrossSource           CrossSource
+	HypotheticalCrossSize float64
+	CrossClamp            Clamp
+	Stretched             bool
+	CrossSize             float64
+
+	// Justify is the main axis alignment of the child's line, and
+	// LineFreeSpace the space it left over to distribute, negative if
+	// the line overflows. MainOffset is where the child starts along
+	// the main axis.
+	Justify       Justify
+	LineFreeSpace float64
+	MainOffset    float64
+
+	// Align is the cross axis alignment of the child, and AlignOffset
+	// the offset it gave the child from the edge of its line.
+	// CrossOffset is where the child starts along the cross axis.
+	Align       AlignItem
+	AlignOffset float64
+	CrossOffset float64
+
+	// Rect is the resulting Rect of the child, including its Offset.
+	Rect image.Rectangle
+}
+
+// Explain explains the layout of child, a child of fl, at the last
+// Layout of fl. It re-runs that layout with fl's current properties
+// and Rect, and the Theme of the last Layout, so it matches the
+// child's Rect unless the tree has changed since. It returns nil if
+// child is not a child of fl.
+func Explain(fl *Flex, child *widget.Node) *Explanation {
+	if child.Parent != &fl.Node {
+		return nil
+	}
+	k := fl.Class.(*flexClass)
+	t := k.theme
+	content := fl.contentBox(fl.Rect.Size())
+	items := k.items(&fl.Node, t, content.Size())
+	index := 0
+	for c := fl.FirstChild; c != child; c = c.NextSibling {
+		index++
+	}
+
+	d := fl.itemData(child)
+	e := &Explanation{
+		Child:      child,
+		LayoutData: items[index].LayoutData,
+		Breakpoint: breakpointIndex(d, fl.mainSize(content.Size())),
+	}
+
+	rects, lines := fl.solve(content.Size(), items, t)
+	var el *element
+	var line *flexLine
+	for i := range lines {
+		for _, c := range lines[i].child {
+			if c.index == index {
+				el, line, e.Line = c, &lines[i], i
+			}
+		}
+	}
+	fl.place(rects, items, content, fl.Rect.Size())
+	if e.LayoutData.Pin != PinNone {
+		e.Pinned = true
+		e.Rect = rects[index]
+		return e
+	}
+	if el == nil {
+		e.Hidden = true
+		return e
+	}
+
+	e.FlexBaseSize = el.flexBaseSize
+	e.BasisDefinite = el.LayoutData.Basis == Definite
+	e.HypotheticalMainSize = el.hypoMainSize
+	e.Grow = line.mainSize < float64(fl.mainSize(content.Size()))
+	e.Inflexible = el.inflexible
+	if !el.inflexible {
+		e.FreeSpace = el.unclamped - el.flexBaseSize
+		e.MainClamp = el.mainClamp
+	}
+	e.MainSize = el.mainSize
+
+	e.CrossSource = el.crossSource
+	e.HypotheticalCrossSize = el.hypoCrossSize
+	e.CrossClamp = el.crossClamp
+	e.Stretched = el.stretched
+	e.CrossSize = el.crossSize
+
+	mainGap, _ := fl.gaps(t)
+	used := mainGap * float64(len(line.child)-1)
+	for _, c := range line.child {
+		used += c.mainSize + c.mainMargin()
+	}
+	e.Justify = fl.Justify
+	if e.Line == len(lines)-1 && fl.LastLineJustify != nil {
+		e.Justify = *fl.LastLineJustify
+	}
+	e.LineFreeSpace = float64(fl.mainSize(content.Size())) - used
+	e.MainOffset = el.mainOffset
+
+	e.Align = fl.alignItem(el.LayoutData)
+	e.AlignOffset = el.crossOffset - line.crossOffset
+	e.CrossOffset = el.crossOffset
+
+	e.Rect = rects[index]
+	return e
+}
+
+// String describes the explanation in a few lines of text.
+func (e *Explanation) String() string {
+	buf := new(bytes.Buffer)
+	if e.Breakpoint >= 0 {
+		fmt.Fprintf(buf, "breakpoint %d applies\n", e.Breakpoint)
+	}
+	if e.Hidden {
+		buf.WriteString("hidden beyond MaxLines\n")
+		return buf.String()
+	}
+	if e.Pinned {
+		fmt.Fprintf(buf, "pinned to %s\nrect %v\n", e.LayoutData.Pin, e.Rect)
+		return buf.String()
+	}
+	source := "measured"
+	if e.BasisDefinite {
+		source = "definite basis"
+	}
+	fmt.Fprintf(buf, "line %d\n", e.Line)
+	fmt.Fprintf(buf, "main: base size %g (%s), hypothetical %g\n", e.FlexBaseSize, source, e.HypotheticalMainSize)
+	verb := "shrink"
+	if e.Grow {
+		verb = "grow"
+	}
+	switch {
+	case e.Inflexible:
+		fmt.Fprintf(buf, "main: inflexible, line would %s\n", verb)
+	default:
+		fmt.Fprintf(buf, "main: %s by %g", verb, e.FreeSpace)
+		if e.MainClamp != ClampNone {
+			fmt.Fprintf(buf, ", clamped to %s", e.MainClamp)
+		}
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(buf, "main: size %g at %g (justify %s, line free space %g)\n", e.MainSize, e.MainOffset, e.Justify, e.LineFreeSpace)
+	fmt.Fprintf(buf, "cross: hypothetical %g (%s)", e.HypotheticalCrossSize, e.CrossSource)
+	if e.CrossClamp != ClampNone {
+		fmt.Fprintf(buf, ", clamped to %s", e.CrossClamp)
+	}
+	if e.Stretched {
+		buf.WriteString(", stretched")
+	}
+	buf.WriteString("\n")
+	fmt.Fprintf(buf, "cross: size %g at %g (align %s, offset %g in line)\n", e.CrossSize, e.CrossOffset, e.Align, e.AlignOffset)
+	fmt.Fprintf(buf, "rect %v\n", e.Rect)
+	return buf.String()
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestExplain(t *testing.T) {
+	box := func(w, h int, d LayoutData) *widget.Node {
+		n := widget.NewUniform(color.Black, unit.Pixels(float64(w)), unit.Pixels(float64(h))).Node
+		n.LayoutData = d
+		return n
+	}
+	fl := NewFlex()
+	fl.Justify = JustifyCenter
+	fl.AlignItem = AlignItemStretch
+	a := box(50, 10, LayoutData{Grow: 1})
+	b := box(50, 10, LayoutData{Grow: 3, MaxSize: sizeptr(80, 100)})
+	c := box(30, 10, LayoutData{Basis: Definite, BasisPx: 20, Align: AlignItemCenter})
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.AppendChild(c)
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 200, 40)
+	fl.Class.Layout(&fl.Node, nil)
+
+	// 80px of free space: b would take 60 but is clamped at 80 wide,
+	// so a takes the rest.
+	ea, eb, ec := Explain(fl, a), Explain(fl, b), Explain(fl, c)
+	for _, e := range []*Explanation{ea, eb, ec} {
+		if e.Rect != e.Child.Rect {
+			t.Errorf("explained Rect %v, laid out at %v", e.Rect, e.Child.Rect)
+		}
+	}
+	if !eb.Grow || eb.FreeSpace != 60 || eb.MainClamp != ClampMax || eb.MainSize != 80 {
+		t.Errorf("b: %+v", eb)
+	}
+	if ea.FreeSpace != 50 || ea.MainClamp != ClampNone || ea.MainSize != 100 {
+		t.Errorf("a: %+v", ea)
+	}
+	if !ea.Stretched || ea.HypotheticalCrossSize != 10 || ea.CrossSize != 40 {
+		t.Errorf("a cross: %+v", ea)
+	}
+	if !ec.Inflexible || !ec.BasisDefinite || ec.FlexBaseSize != 20 || ec.MainOffset != 180 {
+		t.Errorf("c: %+v", ec)
+	}
+	if ec.Stretched || ec.Align != AlignItemCenter || ec.AlignOffset != 15 {
+		t.Errorf("c cross: %+v", ec)
+	}
+	if Explain(fl, &fl.Node) != nil {
+		t.Error("Explain of a non-child")
+	}
+
+	// Shrinking, with a clamp to the minimum: a's share of the 20px
+	// overflow, by its scaled shrink factor, is 50/120 of it.
+	a.LayoutData = LayoutData{MinSize: size(45, 0)}
+	b.LayoutData = LayoutData{}
+	fl.Rect = image.Rect(0, 0, 100, 40)
+	fl.Class.Layout(&fl.Node, nil)
+	ea = Explain(fl, a)
+	if ea.Grow || math.Abs(ea.FreeSpace+20*50.0/120) > 1e-9 || ea.MainClamp != ClampMin || ea.MainSize != 45 {
+		t.Errorf("shrinking a: %+v", ea)
+	}
+	if s := ea.String(); !strings.Contains(s, "shrink by -8.33") || !strings.Contains(s, "clamped to min") {
+		t.Errorf("String():\n%s", s)
+	}
+
+	// A child beyond MaxLines is hidden.
+	fl.Wrap = Wrap
+	fl.MaxLines = 1
+	fl.Class.Layout(&fl.Node, nil)
+	if e := Explain(fl, c); !e.Hidden {
+		t.Errorf("c not hidden: %+v", e)
+	}
+}
+
+func TestExplainMargin(t *testing.T) {
+	fl := NewFlex()
+	fl.Justify = JustifyCenter
+	a := widget.NewUniform(color.Black, unit.Pixels(50), unit.Pixels(10)).Node
+	a.LayoutData = LayoutData{Margin: Insets{Left: 10, Right: 20}}
+	b := widget.NewUniform(color.Black, unit.Pixels(50), unit.Pixels(10)).Node
+	b.LayoutData = LayoutData{Margin: Insets{Left: -40}}
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 200, 40)
+	fl.Class.Layout(&fl.Node, nil)
+
+	// The line uses 50+10+20 and 50-40 of the 200px.
+	ea, eb := Explain(fl, a), Explain(fl, b)
+	if ea.LineFreeSpace != 110 || ea.MainOffset != 65 || ea.Rect != a.Rect {
+		t.Errorf("a: %+v, laid out at %v", ea, a.Rect)
+	}
+	if eb.MainOffset != 95 || eb.Rect != b.Rect {
+		t.Errorf("b: %+v, laid out at %v", eb, b.Rect)
+	}
+}
@@ -7,9 +7,9 @@
 //
 // As the shiny widget model does not provide all of the layout features
 // of CSS, the flex package diverges in several ways. There is no item
-// inline-axis, no item margins or padding to be accounted for, and the
-// container size provided by the outer widget is taken as gospel and
-// never expanded.
+// inline-axis, no item padding, and no auto margins: an item's Margin
+// is a fixed number of pixels. The container size provided by the
+// outer widget is taken as gospel and never expanded.
 package flex
 
 import (
@@ -17,6 +17,7 @@ import (
 	"image"
 	"math"
 
+	"golang.org/x/exp/shiny/unit"
 	"golang.org/x/exp/shiny/widget"
 )
 
@@ -30,6 +31,112 @@ type Flex struct {
 	Justify      Justify
 	AlignItem    AlignItem
 	AlignContent AlignContent
+
+	// RowGap and ColumnGap are the CSS row-gap and column-gap
+	// properties, in pixels. In a Row, ColumnGap separates the items
+	// of a line and RowGap separates lines. In a Column it is the
+	// other way around.
+	RowGap, ColumnGap int
+
+	// GapSpace, if not SpaceNone, is the step of the Spacing used for
+	// RowGap and ColumnGap where they are zero.
+	GapSpace Space
+
+	// Spacing is the scale GapSpace is resolved against. If nil,
+	// DefaultSpacing is used.
+	Spacing *Spacing
+
+	// LastLineJustify, if non-nil, overrides Justify for the last
+	// flex line, like the CSS text-align-last property does for text.
+	// For example, a wrapping container can spread full lines with
+	// JustifySpaceBetween and keep its last line at JustifyStart.
+	// A single-line container's only line is its last line.
+	LastLineJustify *Justify
+
+	// SafeArea insets the box the children are laid out in from the
+	// edges of the Flex, keeping them clear of notches, rounded
+	// corners and system bars. Platforms report these insets; an app
+	// typically sets SafeArea on its root Flex when they change. See
+	// LayoutData.FullBleed.
+	SafeArea Insets
+
+	// LineMinCrossSize is the minimum cross size of each flex line,
+	// converted to pixels by the Theme. Lines of small items, such as
+	// a wrapped list of chips, are made at least this tall (in a Row)
+	// or wide (in a Column), keeping touch targets and rows of a list
+	// consistent. Items are aligned within the line as usual.
+	LineMinCrossSize unit.Value
+
+	// MaxLines, if positive, limits a wrapping container to that
+	// many flex lines. It is measured as if it had only those lines,
+	// and the children that do not fit are overflowed: LineOverflow
+	// says what becomes of them, and Overflow reports them.
+	MaxLines     int
+	LineOverflow LineOverflow
+
+	// Scroll moves the children that are not pinned towards main-start
+	// by Scroll pixels, for a Flex whose children overflow it and are
+	// scrolled. Pinned children stay at their edges. Children are not
+	// clipped to the Flex.
+	Scroll int
+
+	// Safe makes alignment fall back to start alignment where it would
+	// push content past the start edge, like CSS's safe keyword: a
+	// line that overflows the main axis is packed to main-start
+	// whatever the Justify, and an item larger than its line is
+	// aligned to cross-start whatever its AlignItem. Content that
+	// overflows then does so only at the end edges, where it can be
+	// scrolled into view. AlignContent only distributes positive free
+	// space, so it is always safe.
+	Safe bool
+
+	// DefaultLayoutData, if non-nil, is the LayoutData of children
+	// whose Node.LayoutData is not a LayoutData, such as those added
+	// without one. It saves setting the same properties on every
+	// child, as in a toolbar whose buttons all grow and shrink alike.
+	DefaultLayoutData *LayoutData
+
+	// Compat selects a flexbox implementation to match where it
+	// deviates from CSS.
+	Compat Compat
+}
+
+// itemData returns the LayoutData of c, a child of fl.
+func (fl *Flex) itemData(c *widget.Node) LayoutData {
+	if d, ok := c.LayoutData.(LayoutData); ok {
+		return d
+	}
+	if fl.DefaultLayoutData != nil {
+		return *fl.DefaultLayoutData
+	}
+	return LayoutData{}
+}
+
+// lineInfo describes a flex line of a completed layout.
+type lineInfo struct {
+	start, end  int   // range [start, end) in sibling order; may hold pinned children
+	children    []int // sibling indices of the children in the line
+	crossOffset float64
+	crossSize   float64
+}
+
+// has reports whether the child with sibling index i is in the line.
+func (l lineInfo) has(i int) bool {
+	for _, j := range l.children {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
+
+// FlexOf returns the Flex whose Node is n, or nil if n is not the node
+// of a Flex.
+func FlexOf(n *widget.Node) *Flex {
+	if k, ok := n.Class.(*flexClass); ok {
+		return k.flex
+	}
+	return nil
 }
 
 // NewFlex returns a new Flex widget.
@@ -96,8 +203,9 @@ const (
 	AlignItemStart
 	AlignItemEnd
 	AlignItemCenter
-	AlignItemBaseline // TODO requires introducing inline-axis concept
+	AlignItemBaseline // aligns first baselines in a Row; start in a Column
 	AlignItemStretch
+	AlignItemLastBaseline // aligns last baselines in a Row; start in a Column
 )
 
 // AlignContent is the 'align-content' property.
@@ -116,23 +224,61 @@ const (
 	AlignContentSpaceAround
 )
 
+// Compat is a flexbox implementation whose behavior the layout
+// algorithm can match.
+type Compat int8
+
+// Possible values of Compat.
+const (
+	// CompatCSS follows the CSS flexbox specification.
+	CompatCSS Compat = iota
+
+	// CompatYoga follows Facebook's Yoga layout engine, the de facto
+	// flexbox implementation for native UIs. It differs from CSS in
+	// that:
+	//
+	//	- the default flex shrink factor is 0, not 1,
+	//	- the flex base size is clamped by the min and max sizes,
+	//	- align-items defaults to stretch, and
+	//	- positions are rounded to the nearest pixel, not up.
+	//
+	// Yoga's default flex-direction of column and align-content of
+	// flex-start are not matched, as they are the zero values of
+	// Direction and AlignContent. Set them explicitly.
+	//
+	// Yoga, like this package, gives items no automatic minimum size.
+	// CSS's min-width: auto would keep an item from shrinking below
+	// its min-content size.
+	CompatYoga
+)
+
 // Basis sets the base size of a flex item.
 //
 // A default basis of Auto means the flex container uses the
 // MeasuredSize of an item. Otherwise a Definite Basis will
 // override the MeasuredSize with BasisPx.
-//
-// TODO: do we (or will we )have a useful notion of Content in the
-// widget layout model that is separate from MeasuredSize? If not,
-// we could consider completely removing this concept from this
-// flex implementation.
 type Basis int8
 
 // Possible values of Basis.
 const (
-	Auto    Basis = iota
-	Content       // TODO
+	Auto Basis = iota
+
+	// Content, like CSS's content, sizes the item by its contents:
+	// an item with an aspect ratio and a Definite CrossBasis takes
+	// the main size of that ratio, and any other item its
+	// MeasuredSize, its max-content size.
+	Content
+
 	Definite
+
+	// FitContent, like CSS's fit-content(BasisPx), uses the
+	// MeasuredSize of the item up to a limit of BasisPx, so that an
+	// item such as a long label does not take more than its share of
+	// a line. The item still shrinks below it if the line overflows.
+	// As items have no min-content size, the result is not bounded
+	// below as in CSS. FitContent is only a main axis Basis: as a
+	// CrossBasis it is the same as Auto.
+	FitContent
 )
 
 // LayoutData is the Node.LayoutData type for a Flex's children.
@@ -140,80 +286,398 @@ type LayoutData struct {
 	MinSize image.Point
 	MaxSize *image.Point
 
+	// MinLength and MaxLength give the minimum and maximum size in
+	// any unit, or as a percentage of the container's size. Layout
+	// resolves them against its Theme, so they follow DPI changes.
+	// Each dimension that is set overrides the same dimension of
+	// MinSize or MaxSize. Solve uses only MinSize and MaxSize.
+	MinLength, MaxLength *Size
+
 	// Grow is the flex grow factor which determines how much a Node
 	// will grow relative to its siblings.
 	Grow float64
 
+	// Fraction, if positive, sizes the item like a fr track of a CSS
+	// grid: the items with a Fraction share the free space of their
+	// line in proportion to their Fractions, from a basis of zero, so
+	// that their content does not affect their size. The free space
+	// is the container's main size less the gaps and the sizes of the
+	// other items, which do not grow when any item has a Fraction.
+	// MinSize and MaxSize still apply.
+	//
+	// An item with a Fraction overrides Grow, Basis and BasisClamp.
+	// As its basis is zero, it takes no space when a wrapping
+	// container breaks its items into lines; use BreakAfter to place
+	// it.
+	Fraction float64
+
 	// Shrink is the flex shrink factor which determines how much a Node
 	// will shrink relative to its siblings. If nil, a default shrink
 	// factor of 1 is used.
 	Shrink *float64
 
 	// Basis determines the initial main size of the of the Node.
-	// If set to Definite, the value stored in BasisPx is used.
+	// If set to Definite, the value stored in BasisPx is used; if
+	// FitContent, it is the limit.
 	Basis   Basis
 	BasisPx int // TODO use unit package?
 
+	// BasisClamp, if non-nil, overrides Basis and BasisPx with a
+	// Definite basis that follows the container's main size.
+	BasisClamp *BasisClamp
+
+	// CrossBasis determines the cross size of the Node. If set to
+	// Definite, the value stored in CrossSize is used instead of the
+	// MeasuredSize, and the Node is not stretched by AlignItemStretch.
+	// MinSize and MaxSize still apply.
+	CrossBasis Basis
+	CrossSize  int
+
 	Align AlignItem
 
 	// BreakAfter forces the next node onto the next flex line.
 	BreakAfter bool
+
+	// FullBleed extends the item into its container's SafeArea on
+	// each side where it touches the safe area's edge, for
+	// backgrounds that should reach the edges of the screen.
+	FullBleed bool
+
+	// ZIndex orders overlapping siblings when painting: children with
+	// a higher ZIndex are painted later, on top. See PaintOrder.
+	ZIndex int
+
+	// Pin takes the item out of the flow and keeps it at the
+	// main-start or main-end edge of its container, whatever the
+	// container's Justify and Scroll, as for an "Apply" button at the
+	// end of a scrolling column of settings. Pinned items are painted
+	// above unpinned siblings with the same ZIndex.
+	Pin Pin
+
+	// PointerEvents, if PointerNone, excludes the item and its
+	// descendants from hit testing, so that pointer events reach the
+	// items painted below it, as for a decorative overlay. It is
+	// still painted.
+	PointerEvents PointerEvents
+
+	// Margin is space around the item, in pixels, that is part of its
+	// flex line but not of its Rect. As with gaps, main-axis margins
+	// are taken from the free space before items grow or shrink, and
+	// cross-axis margins from the line's cross size before items are
+	// stretched and aligned. MinSize, MaxSize and the other limits
+	// clamp the item's own size, never its margin.
+	//
+	// A margin may be negative, which pulls the item's neighbours
+	// over it and adds to the free space, so that items overlap
+	// rather than shrink, as in a pile of avatars or a stack of
+	// cards. Which of two overlapping items is on top is set by
+	// ZIndex. Pinned items ignore their Margin.
+	Margin Insets
+
+	// Offset moves the item by a number of pixels after the layout is
+	// complete, without affecting its size or its siblings, for small
+	// adjustments and effects such as shaking or sliding an item in.
+	Offset image.Point
+
+	// Breakpoints make the item responsive to the size of its
+	// container. When laid out, the Breakpoint with the largest
+	// MinMainSize not exceeding the container's main size replaces
+	// this LayoutData. If none applies, this LayoutData is used.
+	//
+	// A Breakpoint replaces the whole LayoutData rather than
+	// overriding some of its fields: the fields it leaves unset have
+	// their zero values, not those of this LayoutData. A field that
+	// should be the same at every size, such as an animated Offset,
+	// must be set in each Breakpoint too.
+	//
+	// The list is held by a pointer so that LayoutData stays
+	// comparable. It may be shared by several items, so it must not
+	// be modified once set.
+	Breakpoints *[]Breakpoint
+}
+
+// A Breakpoint is the LayoutData of an item in a container whose main
+// size is at least MinMainSize pixels.
+//
+// For example, an item can have a fixed width beside its siblings in
+// wide containers and take a line of its own in narrow ones:
+//
+//	LayoutData{
+//		Grow:       1,
+//		BreakAfter: true,
+//		Breakpoints: &[]Breakpoint{
+//			{MinMainSize: 600, LayoutData: LayoutData{Basis: Definite, BasisPx: 200}},
+//		},
+//	}
+//
+// In containers at least 600 pixels wide the item has a Grow of 0 and
+// no BreakAfter, as the Breakpoint's LayoutData does not set them.
+type Breakpoint struct {
+	MinMainSize int        `json:"min-main-size"`
+	LayoutData  LayoutData `json:"layout-data"` // its Breakpoints are ignored
+}
+
+// resolve returns the LayoutData in effect in a container with the
+// given main size.
+func (d LayoutData) resolve(mainSize int) LayoutData {
+	best := breakpointIndex(d, mainSize)
+	if best < 0 {
+		return d
+	}
+	r := (*d.Breakpoints)[best].LayoutData
+	r.Breakpoints = nil
+	return r
+}
+
+// breakpointIndex returns the index of the Breakpoint of d in effect
+// in a container of the given main size, or -1.
+func breakpointIndex(d LayoutData, mainSize int) int {
+	if d.Breakpoints == nil {
+		return -1
+	}
+	bps := *d.Breakpoints
+	best := -1
+	for i, b := range bps {
+		if b.MinMainSize > mainSize {
+			continue
+		}
+		if best < 0 || b.MinMainSize >= bps[best].MinMainSize {
+			best = i
+		}
+	}
+	return best
 }
 
 type flexClass struct {
 	widget.ContainerClassEmbed
 
 	flex *Flex
+
+	// lines are the flex lines of the last Layout, and theme its Theme.
+	lines []lineInfo
+	theme *widget.Theme
+
+	observers []*layoutObserver
+
+	// invalidators are called by Invalidate. While updates is
+	// positive, Invalidate only sets invalid.
+	invalidators []*invalidateObserver
+	updates      int
+	invalid      bool
+
+	// overflowed are the children beyond MaxLines at the last Layout.
+	// If clipped, painting is clipped to clip, relative to the Flex.
+	overflowed []*widget.Node
+	clip       image.Rectangle
+	clipped    bool
+
+	// err is the error of the last Layout, if any.
+	err *LoopError
 }
 
 func (k *flexClass) Measure(n *widget.Node, t *widget.Theme) {
 	// As Measure is a bottom-up calculation of natural size, we have no
-	// hint yet as to how we should flex. So we ignore Wrap, Justify,
-	// AlignItem, AlignContent.
+	// hint yet as to how we should flex. The natural size is that of
+	// the items laid out with unbounded space, on a single line.
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		c.Class.Measure(c, t)
-		if d, ok := c.LayoutData.(LayoutData); ok {
-			_ = d
-			// TODO Measure
-		}
 	}
+	n.MeasuredSize = k.MeasureConstrained(n, t, image.Point{}, image.Point{Unbounded, Unbounded})
 }
 
 func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
-	var children []element
+	k.theme = t
+	content := k.flex.contentBox(n.Rect.Size())
+	rects, lines := k.arrange(n, t, n.Rect.Size())
+	k.err = newLoopError(k.flex, n, lines)
+	k.lines = appendLineInfo(k.lines[:0], lines, k.flex.crossSize(content.Min))
+	k.overflow(n, content, len(rects))
+	var changes []ChildChange
+	i := 0
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		children = append(children, element{
-			flexBaseSize: float64(k.flexBaseSize(c)),
-			n:            c,
+		r := rects[i]
+		if len(k.observers) > 0 && c.Rect != r {
+			changes = append(changes, ChildChange{Node: c, Old: c.Rect, New: r})
+		}
+		c.Rect = r
+		c.Class.Layout(c, t)
+		i++
+	}
+	if len(changes) > 0 {
+		k.notify(changes)
+	}
+}
+
+// arrange returns the Rects of the children of n, in sibling order,
+// and its flex lines, if n were the given size. It changes nothing.
+func (k *flexClass) arrange(n *widget.Node, t *widget.Theme, size image.Point) ([]image.Rectangle, []flexLine) {
+	fl := k.flex
+	content := fl.contentBox(size)
+	items := k.items(n, t, content.Size())
+	rects, lines := fl.solve(content.Size(), items, t)
+	fl.place(rects, items, content, size)
+	return rects, lines
+}
+
+// place moves rects, the Rects that Solve gave items, from the content
+// box of a container of the given size to the container, applying the
+// FullBleed and Offset of the items.
+func (fl *Flex) place(rects []image.Rectangle, items []Item, content image.Rectangle, size image.Point) {
+	for i, it := range items {
+		r := rects[i].Add(content.Min)
+		if it.LayoutData.FullBleed {
+			r = bleed(r, content, size)
+		}
+		rects[i] = r.Add(it.LayoutData.Offset)
+	}
+}
+
+// resolvedData returns the LayoutData of c, a child of fl, with the
+// Breakpoint for fl's current Rect applied.
+func (fl *Flex) resolvedData(c *widget.Node) LayoutData {
+	return fl.itemData(c).resolve(fl.mainSize(fl.contentBox(fl.Rect.Size()).Size()))
+}
+
+// appendLineInfo appends the lineInfo of lines to infos. The lines
+// start crossStart pixels into the container.
+func appendLineInfo(infos []lineInfo, lines []flexLine, crossStart int) []lineInfo {
+	for _, line := range lines {
+		children := make([]int, len(line.child))
+		for i, child := range line.child {
+			children[i] = child.index
+		}
+		infos = append(infos, lineInfo{
+			start:       line.child[0].index,
+			end:         line.child[len(line.child)-1].index + 1,
+			children:    children,
+			crossOffset: line.crossOffset + float64(crossStart),
+			crossSize:   line.crossSize,
 		})
 	}
+	return infos
+}
+
+// Item is a flex item as seen by the layout algorithm, independent of
+// any widget tree.
+type Item struct {
+	MeasuredSize image.Point
+	LayoutData   LayoutData
+
+	// CrossSizeFor, if non-nil, returns the cross size of the item
+	// when its main size is mainSize. It is called once the main size
+	// is resolved and before lines are sized, so that items such as
+	// wrapping text get the height that matches their width.
+	CrossSizeFor func(mainSize int) int
+
+	// Baseline, if non-nil, returns the first baseline of the item in
+	// pixels from its top edge. If nil, the baseline is synthesized
+	// at the item's bottom edge.
+	Baseline func() int
+
+	// LastBaseline, if non-nil, returns the last baseline of the item
+	// in pixels from its top edge, when the item has the given size.
+	// If nil, the baseline is synthesized at the item's bottom edge.
+	LastBaseline func(size image.Point) int
+}
+
+// Solve runs the flex layout algorithm over items in a container of the
+// given size, and returns the Rect of each item relative to the
+// container. Items hidden by MaxLines are given an empty Rect.
+//
+// Layout uses Solve for the children of a Flex node. It is exported so
+// the algorithm can drive other widget toolkits. LineMinCrossSize and
+// BasisClamp are converted to pixels by the default Theme.
+//
+// Solve drops the error of SolveErr, which a toolkit should use to
+// report items with NaN or infinite inputs.
+func (fl *Flex) Solve(size image.Point, items []Item) []image.Rectangle {
+	rects, _ := fl.SolveErr(size, items)
+	return rects
+}
+
+// SolveErr is Solve, additionally returning a *LoopError if the flex
+// loop could not resolve the main sizes of some items. The Rects are
+// still complete, with those items at their hypothetical main sizes.
+func (fl *Flex) SolveErr(size image.Point, items []Item) ([]image.Rectangle, error) {
+	rects, lines := fl.solve(size, items, nil)
+	if err := newLoopError(fl, nil, lines); err != nil {
+		return rects, err
+	}
+	return rects, nil
+}
+
+// solve implements Solve, additionally returning the flex lines.
+// Units are converted to pixels by t.
+func (fl *Flex) solve(size image.Point, items []Item, t *widget.Theme) ([]image.Rectangle, []flexLine) {
+	items = fl.resolveBasisClamps(size, items, t)
+	items = fl.resolveFractions(size, items)
+	if fl.Scroll != 0 {
+		return fl.solvePinned(size, items, t)
+	}
+	for _, it := range items {
+		if it.LayoutData.resolve(fl.mainSize(size)).Pin != PinNone {
+			return fl.solvePinned(size, items, t)
+		}
+	}
+	return fl.solveFlow(size, items, t)
+}
+
+// solveFlow lays out items that are not pinned.
+func (fl *Flex) solveFlow(size image.Point, items []Item, t *widget.Theme) ([]image.Rectangle, []flexLine) {
+	rects := make([]image.Rectangle, len(items))
+	if len(items) == 0 {
+		return rects, nil
+	}
+	children := make([]element, len(items))
+	for i, it := range items {
+		it.LayoutData = it.LayoutData.resolve(fl.mainSize(size))
+		base := float64(fl.flexBaseSize(it))
+		if fl.Compat == CompatYoga {
+			base = fl.clampMain(it.LayoutData, base)
+		}
+		children[i] = element{
+			Item:         it,
+			flexBaseSize: base,
+			index:        i,
+		}
+		children[i].marginMain, children[i].marginCross = fl.margins(it.LayoutData)
+	}
 
-	containerMainSize := float64(k.mainSize(n.Rect.Size()))
-	containerCrossSize := float64(k.crossSize(n.Rect.Size()))
+	containerMainSize := float64(fl.mainSize(size))
+	containerCrossSize := float64(fl.crossSize(size))
+	indefiniteCross := containerCrossSize < 0 // see flexClass.MeasureConstrained
+	if indefiniteCross {
+		containerCrossSize = 0
+	}
+	mainGap, crossGap := fl.gaps(t)
 
 	// §9.3.5 collect children into flex lines
 	var lines []flexLine
-	if k.flex.Wrap == NoWrap {
+	if fl.Wrap == NoWrap {
 		line := flexLine{child: make([]*element, len(children))}
 		for i := range children {
 			child := &children[i]
 			line.child[i] = child
-			line.mainSize += child.flexBaseSize
+			line.mainSize += child.flexBaseSize + child.mainMargin()
 		}
+		line.mainSize += mainGap * float64(len(children)-1)
 		lines = []flexLine{line}
 	} else {
 		var line flexLine
 
 		for i := range children {
 			child := &children[i]
-			if line.mainSize > 0 && line.mainSize+child.flexBaseSize > containerMainSize {
+			if line.mainSize > 0 && line.mainSize+mainGap+child.flexBaseSize+child.mainMargin() > containerMainSize {
 				lines = append(lines, line)
 				line = flexLine{}
 			}
+			if len(line.child) > 0 {
+				line.mainSize += mainGap
+			}
 			line.child = append(line.child, child)
-			line.mainSize += child.flexBaseSize
+			line.mainSize += child.flexBaseSize + child.mainMargin()
 
-			if d, ok := child.n.LayoutData.(LayoutData); ok && d.BreakAfter {
+			if child.LayoutData.BreakAfter {
 				lines = append(lines, line)
 				line = flexLine{}
 			}
@@ -221,11 +685,8 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 		if len(line.child) > 0 {
 			lines = append(lines, line)
 		}
-
-		if k.flex.Wrap == WrapReverse {
-			for i := 0; i < len(lines)/2; i++ {
-				lines[i], lines[len(lines)-i-1] = lines[len(lines)-i-1], lines[i]
-			}
+		if fl.MaxLines > 0 && len(lines) > fl.MaxLines && fl.LineOverflow == LineOverflowHide {
+			lines = lines[:fl.MaxLines]
 		}
 	}
 
@@ -234,34 +695,42 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 		line := &lines[lineNum]
 		grow := line.mainSize < containerMainSize // §9.7.1
 
-		// §9.7.2 freeze inflexible children.
+		// Gaps and margins are not free space.
+		innerMainSize := containerMainSize - mainGap*float64(len(line.child)-1)
+		for _, child := range line.child {
+			innerMainSize -= child.mainMargin()
+		}
+
+		// §9.7.2 freeze inflexible children at their hypothetical main size.
 		for _, child := range line.child {
-			mainSize := k.mainSize(child.n.MeasuredSize)
+			hypoMainSize := fl.clampMain(child.LayoutData, child.flexBaseSize)
+			child.hypoMainSize = hypoMainSize
 			if grow {
-				if growFactor(child.n) == 0 || k.flexBaseSize(child.n) > mainSize {
+				if growFactor(child.LayoutData) == 0 || child.flexBaseSize > hypoMainSize {
 					child.frozen = true
-					child.mainSize = float64(mainSize)
+					child.mainSize = hypoMainSize
 				}
 			} else {
-				if shrinkFactor(child.n) == 0 || k.flexBaseSize(child.n) < mainSize {
+				if fl.shrinkFactor(child.LayoutData) == 0 || child.flexBaseSize < hypoMainSize {
 					child.frozen = true
-					child.mainSize = float64(mainSize)
+					child.mainSize = hypoMainSize
 				}
 			}
+			child.inflexible = child.frozen
 		}
 
 		// §9.7.3 calculate initial free space
-		initFreeSpace := float64(k.mainSize(n.Rect.Size()))
+		initFreeSpace := innerMainSize
 		for _, child := range line.child {
 			if child.frozen {
 				initFreeSpace -= child.mainSize
 			} else {
-				initFreeSpace -= float64(k.flexBaseSize(child.n))
+				initFreeSpace -= child.flexBaseSize
 			}
 		}
 
 		// §9.7.4 flex loop
-		for {
+		for iter := 0; ; iter++ {
 			// Check for flexible items.
 			allFrozen := true
 			for _, child := range line.child {
@@ -274,18 +743,25 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 				break
 			}
 
+			// Each iteration freezes at least one item, so the loop
+			// only runs longer than that on NaN sizes or factors.
+			if iter == len(line.child) {
+				fl.unstick(line, grow)
+				break
+			}
+
 			// Calculate remaining free space.
-			remFreeSpace := float64(k.mainSize(n.Rect.Size()))
+			remFreeSpace := innerMainSize
 			unfrozenFlexFactor := 0.0
 			for _, child := range line.child {
 				if child.frozen {
 					remFreeSpace -= child.mainSize
 				} else {
-					remFreeSpace -= float64(k.flexBaseSize(child.n))
+					remFreeSpace -= child.flexBaseSize
 					if grow {
-						unfrozenFlexFactor += growFactor(child.n)
+						unfrozenFlexFactor += growFactor(child.LayoutData)
 					} else {
-						unfrozenFlexFactor += shrinkFactor(child.n)
+						unfrozenFlexFactor += fl.shrinkFactor(child.LayoutData)
 					}
 				}
 			}
@@ -302,8 +778,8 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 					if child.frozen {
 						continue
 					}
-					r := growFactor(child.n) / unfrozenFlexFactor
-					child.mainSize = float64(k.flexBaseSize(child.n)) + r*remFreeSpace
+					r := growFactor(child.LayoutData) / unfrozenFlexFactor
+					child.mainSize = child.flexBaseSize + r*remFreeSpace
 				}
 			} else {
 				sumScaledShrinkFactor := 0.0
@@ -311,16 +787,21 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(k.flexBaseSize(child.n)) * shrinkFactor(child.n)
+					scaledShrinkFactor := child.flexBaseSize * fl.shrinkFactor(child.LayoutData)
 					sumScaledShrinkFactor += scaledShrinkFactor
 				}
 				for _, child := range line.child {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(k.flexBaseSize(child.n)) * shrinkFactor(child.n)
+					if sumScaledShrinkFactor == 0 {
+						// Nothing can shrink; avoid dividing by zero.
+						child.mainSize = child.flexBaseSize
+						continue
+					}
+					scaledShrinkFactor := child.flexBaseSize * fl.shrinkFactor(child.LayoutData)
 					r := float64(scaledShrinkFactor) / sumScaledShrinkFactor
-					child.mainSize = float64(k.flexBaseSize(child.n)) - r*math.Abs(float64(remFreeSpace))
+					child.mainSize = child.flexBaseSize - r*math.Abs(float64(remFreeSpace))
 				}
 			}
 
@@ -334,21 +815,9 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 					continue
 				}
 				child.unclamped = child.mainSize
-				if d, ok := child.n.LayoutData.(LayoutData); ok {
-					minSize := float64(k.mainSize(d.MinSize))
-					if minSize > child.mainSize {
-						child.mainSize = minSize
-					} else if d.MaxSize != nil {
-						maxSize := float64(k.mainSize(*d.MaxSize))
-						if child.mainSize > maxSize {
-							child.mainSize = maxSize
-						}
-					}
-				}
-				if child.mainSize < 0 {
-					child.mainSize = 0
-				}
+				child.mainSize = fl.clampMain(child.LayoutData, child.mainSize)
 				sumClampDiff += child.mainSize - child.unclamped
+				child.mainClamp = clampOf(child.unclamped, child.mainSize)
 			}
 
 			// Freeze over-flexed items.
@@ -380,56 +849,85 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 	// §9.4.7 calculate hypothetical cross size of each element
 	for lineNum := range lines {
 		for _, child := range lines[lineNum].child {
-			child.crossSize = float64(k.crossSize(child.n.MeasuredSize))
-			if child.mainSize < float64(k.mainSize(child.n.MeasuredSize)) {
-				if r, ok := aspectRatio(child.n); ok {
+			child.crossSize = float64(fl.crossSize(child.MeasuredSize))
+			child.crossSource = CrossMeasured
+			if child.LayoutData.CrossBasis == Definite {
+				child.crossSize = float64(child.LayoutData.CrossSize)
+				child.crossSource = CrossDefinite
+			} else if child.CrossSizeFor != nil {
+				child.crossSize = float64(child.CrossSizeFor(int(math.Ceil(child.mainSize))))
+				child.crossSource = CrossForMainSize
+			} else if child.mainSize < float64(fl.mainSize(child.MeasuredSize)) {
+				if r, ok := aspectRatio(child.LayoutData); ok {
 					child.crossSize = child.mainSize / r
+					child.crossSource = CrossAspectRatio
 				}
 			}
-			if d, ok := child.n.LayoutData.(LayoutData); ok {
-				minSize := float64(k.crossSize(d.MinSize))
-				if minSize > child.crossSize {
-					child.crossSize = minSize
-				} else if d.MaxSize != nil {
-					maxSize := float64(k.crossSize(*d.MaxSize))
-					if child.crossSize > maxSize {
-						child.crossSize = maxSize
-					}
+			child.hypoCrossSize = child.crossSize
+			d := child.LayoutData
+			minSize := float64(fl.crossSize(d.MinSize))
+			if minSize > child.crossSize {
+				child.crossSize = minSize
+			} else if d.MaxSize != nil {
+				maxSize := float64(fl.crossSize(*d.MaxSize))
+				if child.crossSize > maxSize {
+					child.crossSize = maxSize
 				}
 			}
+			child.crossClamp = clampOf(child.hypoCrossSize, child.crossSize)
 		}
 	}
-	if len(lines) == 1 {
+	lineMin := 0.0
+	if fl.LineMinCrossSize.F > 0 {
+		lineMin = float64(t.Pixels(fl.LineMinCrossSize).Round())
+	}
+	if len(lines) == 1 && !indefiniteCross {
 		// §9.4.8 single line
-		switch k.flex.Direction {
+		switch fl.Direction {
 		case Row, RowReverse:
-			lines[0].crossSize = float64(n.Rect.Size().Y)
+			lines[0].crossSize = float64(size.Y)
 		case Column, ColumnReverse:
-			lines[0].crossSize = float64(n.Rect.Size().X)
+			lines[0].crossSize = float64(size.X)
 		}
+		lines[0].crossSize = math.Max(lines[0].crossSize, lineMin)
 	} else {
 		// §9.4.8 multi-line
 		for lineNum := range lines {
 			line := &lines[lineNum]
-			// TODO §9.4.8.1, no concept of inline-axis yet
-			max := 0.0
+			// §9.4.8.1 baseline-aligned items contribute their
+			// largest ascent plus their largest descent.
+			// First- and last-baseline items are aligned separately.
+			max := lineMin
+			var ascent, descent [2]float64
 			for _, child := range line.child {
-				if child.crossSize > max {
-					max = child.crossSize
+				if g := fl.baselineGroup(child); g >= 0 {
+					b := child.baseline(g)
+					ascent[g] = math.Max(ascent[g], child.marginCross[0]+b)
+					descent[g] = math.Max(descent[g], child.crossSize-b+child.marginCross[1])
+					continue
+				}
+				if outer := child.crossSize + child.crossMargin(); outer > max {
+					max = outer
 				}
 			}
+			for g := range ascent {
+				max = math.Max(max, ascent[g]+descent[g])
+			}
 			line.crossSize = max
 		}
 	}
 	off := 0.0
 	for lineNum := range lines {
 		line := &lines[lineNum]
+		if lineNum > 0 {
+			off += crossGap
+		}
 		line.crossOffset = off
 		off += line.crossSize
 	}
 	// §9.4.9 align-content: stretch
 	remCrossSize := containerCrossSize - off
-	if k.flex.AlignContent == AlignContentStretch && remCrossSize > 0 {
+	if fl.AlignContent == AlignContentStretch && remCrossSize > 0 {
 		add := remCrossSize / float64(len(lines))
 		for lineNum := range lines {
 			line := &lines[lineNum]
@@ -442,9 +940,11 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 	for lineNum := range lines {
 		line := &lines[lineNum]
 		for _, child := range line.child {
-			align := k.alignItem(child.n)
-			if align == AlignItemStretch && child.crossSize < line.crossSize {
-				child.crossSize = line.crossSize
+			align := fl.alignItem(child.LayoutData)
+			inner := line.crossSize - child.crossMargin()
+			if align == AlignItemStretch && child.LayoutData.CrossBasis != Definite && child.crossSize < inner {
+				child.crossSize = fl.clampCross(child.LayoutData, inner)
+				child.stretched = true
 			}
 		}
 	}
@@ -452,43 +952,50 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 	// §9.5 main axis alignment
 	for lineNum := range lines {
 		line := &lines[lineNum]
-		total := 0.0
+		total := mainGap * float64(len(line.child)-1)
 		for _, child := range line.child {
-			total += child.mainSize
+			total += child.mainSize + child.mainMargin()
 		}
 		remFree := containerMainSize - total
-		switch k.flex.Justify {
+		justify := fl.Justify
+		if lineNum == len(lines)-1 && fl.LastLineJustify != nil {
+			justify = *fl.LastLineJustify
+		}
+		if fl.Safe && remFree < 0 {
+			justify = JustifyStart
+		}
+		switch justify {
 		case JustifyStart:
 			off := 0.0
 			for _, child := range line.child {
-				child.mainOffset = off
-				off += child.mainSize
+				child.mainOffset = off + child.marginMain[0]
+				off += child.mainSize + child.mainMargin() + mainGap
 			}
 		case JustifyEnd:
 			off := remFree
 			for _, child := range line.child {
-				child.mainOffset = off
-				off += child.mainSize
+				child.mainOffset = off + child.marginMain[0]
+				off += child.mainSize + child.mainMargin() + mainGap
 			}
 		case JustifyCenter:
 			off := remFree / 2
 			for _, child := range line.child {
-				child.mainOffset = off
-				off += child.mainSize
+				child.mainOffset = off + child.marginMain[0]
+				off += child.mainSize + child.mainMargin() + mainGap
 			}
 		case JustifySpaceBetween:
 			spacing := remFree / float64(len(line.child)-1)
 			off := 0.0
 			for _, child := range line.child {
-				child.mainOffset = off
-				off += spacing + child.mainSize
+				child.mainOffset = off + child.marginMain[0]
+				off += spacing + child.mainSize + child.mainMargin() + mainGap
 			}
 		case JustifySpaceAround:
 			spacing := remFree / float64(len(line.child))
 			off := spacing / 2
 			for _, child := range line.child {
-				child.mainOffset = off
-				off += spacing + child.mainSize
+				child.mainOffset = off + child.marginMain[0]
+				off += spacing + child.mainSize + child.mainMargin() + mainGap
 			}
 		}
 	}
@@ -498,23 +1005,38 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 	// §9.6.14 align items inside line, 'align-self'.
 	for lineNum := range lines {
 		line := &lines[lineNum]
+		var maxBaseline [2]float64
 		for _, child := range line.child {
-			child.crossOffset = line.crossOffset
-			if child.crossSize == line.crossSize {
+			if g := fl.baselineGroup(child); g >= 0 {
+				maxBaseline[g] = math.Max(maxBaseline[g], child.marginCross[0]+child.baseline(g))
+			}
+		}
+		for _, child := range line.child {
+			child.crossOffset = line.crossOffset + child.marginCross[0]
+			if g := fl.baselineGroup(child); g >= 0 {
+				child.crossOffset += maxBaseline[g] - child.marginCross[0] - child.baseline(g)
+				continue
+			}
+			diff := line.crossSize - child.crossSize - child.crossMargin()
+			if diff == 0 {
 				continue
 			}
-			diff := line.crossSize - child.crossSize
-			switch k.alignItem(child.n) {
+			align := fl.alignItem(child.LayoutData)
+			if fl.Safe && diff < 0 {
+				align = AlignItemStart
+			}
+			switch align {
 			case AlignItemStart:
 				// already laid out correctly
 			case AlignItemEnd:
-				child.crossOffset = line.crossOffset + diff
+				child.crossOffset += diff
 			case AlignItemCenter:
-				child.crossOffset = line.crossOffset + diff/2
-			case AlignItemBaseline:
-				// TODO requires introducing inline-axis concept
+				child.crossOffset += diff / 2
+			case AlignItemBaseline, AlignItemLastBaseline:
+				// Baselines run along the main axis only in a Row, so
+				// in a Column baseline alignment is start alignment.
 			case AlignItemStretch:
-				// handled earlier, so child.crossSize == line.crossSize
+				// handled earlier, or a Definite CrossBasis: start alignment
 			}
 		}
 	}
@@ -524,7 +1046,7 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 
 	// §9.6.16 align flex lines, 'align-content'.
 	if remFree > 0 {
-		switch k.flex.AlignContent {
+		switch fl.AlignContent {
 		case AlignContentStart:
 			// already laid out correctly
 		case AlignContentEnd:
@@ -572,7 +1094,7 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 		}
 	}
 
-	switch k.flex.Direction {
+	switch fl.Direction {
 	case RowReverse, ColumnReverse:
 		// Invert main-start and main-end.
 		for lineNum := range lines {
@@ -583,30 +1105,54 @@ func (k *flexClass) Layout(n *widget.Node, t *widget.Theme) {
 		}
 	}
 
-	// Layout complete. Generate child Rect values.
+	if fl.Wrap == WrapReverse {
+		// Invert cross-start and cross-end.
+		for lineNum := range lines {
+			line := &lines[lineNum]
+			line.crossOffset = containerCrossSize - line.crossOffset - line.crossSize
+			for _, child := range line.child {
+				child.crossOffset = containerCrossSize - child.crossOffset - child.crossSize
+			}
+		}
+	}
+
+	// Layout complete. Generate child Rect values. Edges are rounded
+	// up, ignoring float error so that edges that land on a pixel,
+	// such as those of items sharing space equally, are not pushed to
+	// the next one.
+	round := func(x float64) float64 { return math.Ceil(x - roundingSlop) }
+	if fl.Compat == CompatYoga {
+		round = func(x float64) float64 { return math.Floor(x + 0.5) }
+	}
 	for lineNum := range lines {
 		line := &lines[lineNum]
 		for _, child := range line.child {
-			switch k.flex.Direction {
+			r := &rects[child.index]
+			switch fl.Direction {
 			case Row, RowReverse:
-				child.n.Rect.Min.X = int(math.Ceil(child.mainOffset))
-				child.n.Rect.Max.X = int(math.Ceil(child.mainOffset + child.mainSize))
-				child.n.Rect.Min.Y = int(math.Ceil(child.crossOffset))
-				child.n.Rect.Max.Y = int(math.Ceil(child.crossOffset + child.crossSize))
+				r.Min.X = int(round(child.mainOffset))
+				r.Max.X = int(round(child.mainOffset + child.mainSize))
+				r.Min.Y = int(round(child.crossOffset))
+				r.Max.Y = int(round(child.crossOffset + child.crossSize))
 			case Column, ColumnReverse:
-				child.n.Rect.Min.Y = int(math.Ceil(child.mainOffset))
-				child.n.Rect.Max.Y = int(math.Ceil(child.mainOffset + child.mainSize))
-				child.n.Rect.Min.X = int(math.Ceil(child.crossOffset))
-				child.n.Rect.Max.X = int(math.Ceil(child.crossOffset + child.crossSize))
+				r.Min.Y = int(round(child.mainOffset))
+				r.Max.Y = int(round(child.mainOffset + child.mainSize))
+				r.Min.X = int(round(child.crossOffset))
+				r.Max.X = int(round(child.crossOffset + child.crossSize))
 			default:
-				panic(fmt.Sprint("bad direction: ", k.flex.Direction))
+				panic(fmt.Sprint("bad direction: ", fl.Direction))
 			}
 		}
 	}
+	return rects, lines
 }
 
+// roundingSlop is the float error tolerated when rounding edges.
+const roundingSlop = 1e-6
+
 type element struct {
-	n            *widget.Node
+	Item
+	index        int // position in the items passed to Solve
 	flexBaseSize float64
 	frozen       bool
 	unclamped    float64
@@ -614,6 +1160,20 @@ type element struct {
 	mainOffset   float64
 	crossSize    float64
 	crossOffset  float64
+
+	// marginMain and marginCross are the margins at the start and
+	// end of each axis, in the direction of flow.
+	marginMain, marginCross [2]float64
+
+	// Recorded for Explain.
+	hypoMainSize  float64
+	inflexible    bool   // frozen before the flex loop
+	stuck         string // why the flex loop did not freeze it
+	mainClamp     Clamp
+	crossSource   CrossSource
+	hypoCrossSize float64
+	crossClamp    Clamp
+	stretched     bool
 }
 
 type flexLine struct {
@@ -623,76 +1183,161 @@ type flexLine struct {
 	child       []*element
 }
 
-func (k *flexClass) alignItem(n *widget.Node) AlignItem {
-	align := k.flex.AlignItem
-	if d, ok := n.LayoutData.(LayoutData); ok {
-		align = d.Align
+// baselineGroup returns the baseline-sharing group of child: 0 if it
+// is aligned by its first baseline, 1 if by its last baseline, and -1
+// if it takes no part in baseline alignment.
+func (fl *Flex) baselineGroup(child *element) int {
+	if fl.Direction != Row && fl.Direction != RowReverse {
+		return -1
+	}
+	switch fl.alignItem(child.LayoutData) {
+	case AlignItemBaseline:
+		return 0
+	case AlignItemLastBaseline:
+		return 1
 	}
-	return align
+	return -1
 }
 
-// flexBaseSize calculates flex base size as per §9.2.3
-func (k *flexClass) flexBaseSize(n *widget.Node) int {
-	basis := Auto
-	if d, ok := n.LayoutData.(LayoutData); ok {
-		basis = d.Basis
+// baseline returns the first (group 0) or last (group 1) baseline of
+// e, from its cross-start edge.
+func (e *element) baseline(group int) float64 {
+	if group == 1 {
+		if e.LastBaseline != nil {
+			size := image.Point{int(math.Ceil(e.mainSize)), int(math.Ceil(e.crossSize))}
+			return float64(e.LastBaseline(size))
+		}
+		return e.crossSize
+	}
+	if e.Baseline != nil {
+		return float64(e.Baseline())
 	}
-	switch basis {
+	return e.crossSize
+}
+
+func (fl *Flex) alignItem(d LayoutData) AlignItem {
+	if d.Align != AlignItemAuto {
+		return d.Align
+	}
+	if fl.AlignItem == AlignItemAuto && fl.Compat == CompatYoga {
+		return AlignItemStretch
+	}
+	return fl.AlignItem
+}
+
+// flexBaseSize calculates flex base size as per §9.2.3
+func (fl *Flex) flexBaseSize(it Item) int {
+	switch basis := it.LayoutData.Basis; basis {
 	case Definite: // A
-		return n.LayoutData.(LayoutData).BasisPx
+		return it.LayoutData.BasisPx
 	case Content:
-		// TODO §9.2.3.B
-		// TODO §9.2.3.C
-		// TODO §9.2.3.D
-		panic("flex-basis: content not supported")
+		// §9.2.3.B
+		if r, ok := aspectRatio(it.LayoutData); ok && it.LayoutData.CrossBasis == Definite {
+			return int(math.Floor(float64(it.LayoutData.CrossSize)*r + 0.5))
+		}
+		// §9.2.3.D: content is max-content, the MeasuredSize.
+		return fl.mainSize(it.MeasuredSize)
 	case Auto: // E
-		return k.mainSize(n.MeasuredSize)
+		return fl.mainSize(it.MeasuredSize)
+	case FitContent:
+		if size := fl.mainSize(it.MeasuredSize); size < it.LayoutData.BasisPx {
+			return size
+		}
+		return it.LayoutData.BasisPx
 	default:
 		panic(fmt.Sprintf("unknown flex-basis %v", basis))
 	}
 }
 
-func growFactor(n *widget.Node) float64 {
-	if d, ok := n.LayoutData.(LayoutData); ok {
-		return d.Grow
+// clampMain clamps a main size to the min and max main sizes of d.
+func (fl *Flex) clampMain(d LayoutData, size float64) float64 {
+	if minSize := float64(fl.mainSize(d.MinSize)); minSize > size {
+		size = minSize
+	} else if d.MaxSize != nil {
+		if maxSize := float64(fl.mainSize(*d.MaxSize)); size > maxSize {
+			size = maxSize
+		}
+	}
+	if size < 0 {
+		size = 0
 	}
-	return 0
+	return size
+}
+
+// clampCross clamps a cross size to the min and max cross sizes of d.
+func (fl *Flex) clampCross(d LayoutData, size float64) float64 {
+	if minSize := float64(fl.crossSize(d.MinSize)); minSize > size {
+		size = minSize
+	} else if d.MaxSize != nil {
+		if maxSize := float64(fl.crossSize(*d.MaxSize)); size > maxSize {
+			size = maxSize
+		}
+	}
+	return size
+}
+
+func growFactor(d LayoutData) float64 {
+	return d.Grow
 }
 
-func shrinkFactor(n *widget.Node) float64 {
-	if d, ok := n.LayoutData.(LayoutData); ok && d.Shrink != nil {
+func (fl *Flex) shrinkFactor(d LayoutData) float64 {
+	if d.Shrink != nil {
 		return *d.Shrink
 	}
+	if fl.Compat == CompatYoga {
+		return 0
+	}
 	return 1
 }
 
-func aspectRatio(n *widget.Node) (ratio float64, ok bool) {
+func aspectRatio(d LayoutData) (ratio float64, ok bool) {
 	// TODO: source a formal description of "intrinsic aspect ratio"
-	d, ok := n.LayoutData.(LayoutData)
-	if ok && d.MinSize.X != 0 && d.MinSize.Y != 0 {
+	if d.MinSize.X != 0 && d.MinSize.Y != 0 {
 		return float64(d.MinSize.X) / float64(d.MinSize.Y), true
 	}
 	return 0, false
 }
 
-func (k *flexClass) mainSize(p image.Point) int {
-	switch k.flex.Direction {
+// gaps returns the gap between items in a line and between lines.
+func (fl *Flex) gaps(t *widget.Theme) (mainGap, crossGap float64) {
+	rowGap, columnGap := fl.RowGap, fl.ColumnGap
+	if fl.GapSpace != SpaceNone {
+		px := fl.spacing().Pixels(t, fl.GapSpace)
+		if rowGap == 0 {
+			rowGap = px
+		}
+		if columnGap == 0 {
+			columnGap = px
+		}
+	}
+	switch fl.Direction {
+	case Row, RowReverse:
+		return float64(columnGap), float64(rowGap)
+	case Column, ColumnReverse:
+		return float64(rowGap), float64(columnGap)
+	default:
+		panic(fmt.Sprint("bad direction: ", fl.Direction))
+	}
+}
+
+func (fl *Flex) mainSize(p image.Point) int {
+	switch fl.Direction {
 	case Row, RowReverse:
 		return p.X
 	case Column, ColumnReverse:
 		return p.Y
 	default:
-		panic(fmt.Sprint("bad direction: ", k.flex.Direction))
+		panic(fmt.Sprint("bad direction: ", fl.Direction))
 	}
 }
 
-func (k *flexClass) crossSize(p image.Point) int {
-	switch k.flex.Direction {
+func (fl *Flex) crossSize(p image.Point) int {
+	switch fl.Direction {
 	case Row, RowReverse:
 		return p.Y
 	case Column, ColumnReverse:
 		return p.X
 	default:
-		panic(fmt.Sprint("bad direction: ", k.flex.Direction))
+		panic(fmt.Sprint("bad direction: ", fl.Direction))
 	}
 }
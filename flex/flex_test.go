@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"reflect"
 	"testing"
 
 	"golang.org/x/exp/shiny/unit"
@@ -19,6 +20,8 @@ type layoutTest struct {
 	direction    Direction
 	wrap         FlexWrap
 	alignContent AlignContent
+	rowGap       int
+	columnGap    int
 	size         image.Point       // size of container
 	measured     [][2]float64      // MeasuredSize of child elements
 	layoutData   []LayoutData      // LayoutData of child elements
@@ -63,6 +66,12 @@ func (test *layoutTest) html() string {
 	case AlignContentStretch:
 		fmt.Fprintf(buf, "\talign-content: stretch;\n")
 	}
+	if test.rowGap != 0 {
+		fmt.Fprintf(buf, "\trow-gap: %dpx;\n", test.rowGap)
+	}
+	if test.columnGap != 0 {
+		fmt.Fprintf(buf, "\tcolumn-gap: %dpx;\n", test.columnGap)
+	}
 	fmt.Fprintf(buf, "}\n")
 
 	for i, m := range test.measured {
@@ -226,6 +235,69 @@ var layoutTests = []layoutTest{
 			{Grow: 1},
 		},
 	},
+	{
+		size:      image.Point{350, 100},
+		columnGap: 10,
+		measured:  [][2]float64{{100, 100}, {100, 100}, {100, 100}},
+		want: []image.Rectangle{
+			{size(0, 0), size(100, 100)},
+			{size(110, 0), size(210, 100)},
+			{size(220, 0), size(320, 100)},
+		},
+	},
+	{
+		size:         image.Point{300, 200},
+		wrap:         Wrap,
+		alignContent: AlignContentStart,
+		rowGap:       10,
+		columnGap:    20,
+		measured:     [][2]float64{{100, 50}, {100, 50}, {100, 50}, {100, 50}},
+		want: []image.Rectangle{
+			{size(0, 0), size(100, 50)},
+			{size(120, 0), size(220, 50)},
+			{size(0, 60), size(100, 110)},
+			{size(120, 60), size(220, 110)},
+		},
+	},
+	{
+		size:      image.Point{300, 100},
+		columnGap: 15,
+		measured:  [][2]float64{{0, 100}, {0, 100}, {0, 100}},
+		want: []image.Rectangle{
+			{size(0, 0), size(90, 100)},
+			{size(105, 0), size(195, 100)},
+			{size(210, 0), size(300, 100)},
+		},
+		layoutData: []LayoutData{
+			{Grow: 1},
+			{Grow: 1},
+			{Grow: 1},
+		},
+	},
+	{
+		size:      image.Point{30, 100},
+		columnGap: 15,
+		measured:  [][2]float64{{0, 100}, {0, 100}, {0, 100}},
+		want: []image.Rectangle{
+			{size(0, 0), size(0, 100)},
+			{size(15, 0), size(15, 100)},
+			{size(30, 0), size(30, 100)},
+		},
+	},
+	{
+		size:     image.Point{300, 100},
+		measured: [][2]float64{{100, 50}, {100, 50}, {100, 50}},
+		want: []image.Rectangle{
+			{size(0, 0), size(100, 100)},
+			{size(100, 0), size(200, 30)},
+			{size(200, 0), size(300, 80)},
+		},
+		layoutData: []LayoutData{
+			{Align: AlignItemStretch},
+			{Align: AlignItemStretch, CrossBasis: Definite, CrossSize: 30},
+			{CrossBasis: Definite, CrossSize: 200, MaxSize: sizeptr(100, 80)},
+		},
+	},
 }
 
 func size(x, y int) image.Point { return image.Pt(x, y) }
@@ -240,6 +312,8 @@ func TestLayout(t *testing.T) {
 		fl.Direction = test.direction
 		fl.Wrap = test.wrap
 		fl.AlignContent = test.alignContent
+		fl.RowGap = test.rowGap
+		fl.ColumnGap = test.columnGap
 
 		var children []*widget.Node
 		for i, sz := range test.measured {
@@ -272,3 +346,238 @@ func TestLayout(t *testing.T) {
 		}
 	}
 }
+
+func TestBreakpoints(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	items := []Item{
+		{MeasuredSize: size(100, 50), LayoutData: LayoutData{
+			Grow:       1,
+			BreakAfter: true,
+			Breakpoints: &[]Breakpoint{
+				{MinMainSize: 1000, LayoutData: LayoutData{Grow: 5}},
+				{MinMainSize: 600, LayoutData: LayoutData{Basis: Definite, BasisPx: 200}},
+			},
+		}},
+		{MeasuredSize: size(100, 50)},
+	}
+	tests := []struct {
+		size image.Point
+		want []image.Rectangle
+	}{
+		{size(400, 100), []image.Rectangle{{size(0, 0), size(400, 50)}, {size(0, 50), size(100, 100)}}},
+		{size(700, 100), []image.Rectangle{{size(0, 0), size(200, 50)}, {size(200, 0), size(300, 50)}}},
+		{size(1000, 100), []image.Rectangle{{size(0, 0), size(900, 50)}, {size(900, 0), size(1000, 50)}}},
+	}
+	for _, test := range tests {
+		got := fl.Solve(test.size, items)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Solve(%v) = %v, want %v", test.size, got, test.want)
+		}
+	}
+
+	// LayoutData with Breakpoints stays comparable.
+	if d := items[0].LayoutData; d != d || d == (LayoutData{Grow: 1, BreakAfter: true}) {
+		t.Errorf("LayoutData with Breakpoints does not compare")
+	}
+}
+
+func TestFitContent(t *testing.T) {
+	fl := NewFlex()
+	fit := LayoutData{Basis: FitContent, BasisPx: 100}
+	tests := []struct {
+		label image.Point
+		size  image.Point
+		want  []image.Rectangle
+	}{
+		// A long label is limited, a short one keeps its size, and
+		// both shrink when the line overflows.
+		{size(150, 10), size(200, 10), []image.Rectangle{{size(0, 0), size(100, 10)}, {size(100, 0), size(200, 10)}}},
+		{size(60, 10), size(200, 10), []image.Rectangle{{size(0, 0), size(60, 10)}, {size(60, 0), size(200, 10)}}},
+		{size(150, 10), size(90, 10), []image.Rectangle{{size(0, 0), size(75, 10)}, {size(75, 0), size(90, 10)}}},
+	}
+	for _, test := range tests {
+		items := []Item{
+			{MeasuredSize: test.label, LayoutData: fit},
+			{MeasuredSize: size(20, 10), LayoutData: LayoutData{Grow: 1}},
+		}
+		got := fl.Solve(test.size, items)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("label %v in %v: got %v, want %v", test.label, test.size, got, test.want)
+		}
+	}
+}
+
+// TestContentBasis parses flex-basis: content and lays it out.
+func TestContentBasis(t *testing.T) {
+	fl := NewFlex()
+	d, err := ParseItemStyle("flex: 0 0 content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratio := d
+	ratio.MinSize = size(2, 1)
+	ratio.CrossBasis, ratio.CrossSize = Definite, 15
+	items := []Item{
+		{MeasuredSize: size(40, 10), LayoutData: d},
+		{MeasuredSize: size(40, 10), LayoutData: ratio},
+	}
+	got := fl.Solve(size(200, 20), items)
+	want := []image.Rectangle{{size(0, 0), size(40, 10)}, {size(40, 0), size(70, 15)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBasisClamp(t *testing.T) {
+	fl := NewFlex()
+	items := []Item{{
+		MeasuredSize: size(10, 10),
+		LayoutData: LayoutData{BasisClamp: &BasisClamp{
+			Min:       Length{Kind: LengthValue, Value: unit.Pixels(100)},
+			Preferred: Length{Kind: LengthPercent, Percent: 30},
+			Max:       Length{Kind: LengthValue, Value: unit.Pixels(300)},
+		}},
+	}}
+	for _, test := range []struct{ container, want int }{
+		{200, 100},
+		{500, 150},
+		{2000, 300},
+	} {
+		got := fl.Solve(size(test.container, 10), items)
+		if got[0].Dx() != test.want {
+			t.Errorf("in %d: width %d, want %d", test.container, got[0].Dx(), test.want)
+		}
+	}
+}
+
+func TestFraction(t *testing.T) {
+	fl := NewFlex()
+	fl.ColumnGap = 10
+	items := []Item{
+		{MeasuredSize: size(200, 10), LayoutData: LayoutData{Fraction: 1}},
+		{MeasuredSize: size(10, 10), LayoutData: LayoutData{Fraction: 2}},
+		{MeasuredSize: size(30, 10), LayoutData: LayoutData{Grow: 1}}, // does not grow
+		{MeasuredSize: size(10, 10), LayoutData: LayoutData{Fraction: 1, MinSize: size(80, 0)}},
+	}
+	got := fl.Solve(size(330, 10), items)
+	want := []image.Rectangle{
+		{size(0, 0), size(64, 10)}, // 190 * 1/3, after the last is clamped
+		{size(74, 0), size(200, 10)},
+		{size(210, 0), size(240, 10)},
+		{size(250, 0), size(330, 10)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestSafe(t *testing.T) {
+	items := []Item{
+		{MeasuredSize: size(60, 10), LayoutData: LayoutData{Shrink: new(float64)}},
+		{MeasuredSize: size(60, 30), LayoutData: LayoutData{Shrink: new(float64), Align: AlignItemEnd}},
+	}
+	tests := []struct {
+		safe bool
+		want []image.Rectangle
+	}{
+		{false, []image.Rectangle{{size(-10, 5), size(50, 15)}, {size(50, -10), size(110, 20)}}},
+		{true, []image.Rectangle{{size(0, 5), size(60, 15)}, {size(60, 0), size(120, 30)}}},
+	}
+	for _, test := range tests {
+		fl := &Flex{Justify: JustifyCenter, AlignItem: AlignItemCenter, Safe: test.safe}
+		got := fl.Solve(size(100, 20), items)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Safe=%t: got %v, want %v", test.safe, got, test.want)
+		}
+	}
+}
+
+func TestLastLineJustify(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.Justify = JustifySpaceBetween
+	start := JustifyStart
+	fl.LastLineJustify = &start
+
+	var items []Item
+	for i := 0; i < 5; i++ {
+		items = append(items, Item{MeasuredSize: size(30, 10)})
+	}
+	want := []image.Rectangle{
+		{size(0, 0), size(30, 10)},
+		{size(35, 0), size(65, 10)},
+		{size(70, 0), size(100, 10)},
+		{size(0, 10), size(30, 20)},
+		{size(30, 10), size(60, 20)},
+	}
+	if got := fl.Solve(size(100, 20), items); !reflect.DeepEqual(got, want) {
+		t.Errorf("Solve = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultLayoutData(t *testing.T) {
+	fl := NewFlex()
+	fl.DefaultLayoutData = &LayoutData{Grow: 1, Basis: Definite}
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	c := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	c.LayoutData = LayoutData{ZIndex: -1} // its own LayoutData, with no Grow
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.AppendChild(c)
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 110, 10)
+	fl.Class.Layout(&fl.Node, nil)
+
+	for _, test := range []struct {
+		name string
+		n    *widget.Node
+		want int
+	}{
+		{"a", a, 50},
+		{"b", b, 50},
+		{"c", c, 10},
+	} {
+		if got := test.n.Rect.Dx(); got != test.want {
+			t.Errorf("%s is %d wide, want %d", test.name, got, test.want)
+		}
+	}
+	if got := PaintOrder(&fl.Node); got[0] != c {
+		t.Errorf("PaintOrder starts with %p, want c", got[0])
+	}
+}
+
+func TestLineMinCrossSize(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignContent = AlignContentStart
+	fl.LineMinCrossSize = unit.Points(24) // 48px at 144 DPI
+	theme := &widget.Theme{DPI: 144}
+	var children []*widget.Node
+	for i, h := range []float64{20, 60, 20} {
+		c := widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(h)).Node
+		if i == 2 {
+			c.LayoutData = LayoutData{Align: AlignItemCenter}
+		}
+		children = append(children, c)
+		fl.AppendChild(c)
+	}
+	fl.Class.Measure(&fl.Node, theme)
+	fl.Rect = image.Rect(0, 0, 60, 200)
+	fl.Class.Layout(&fl.Node, theme)
+
+	want := []image.Rectangle{
+		image.Rect(0, 0, 30, 20), // line of 60, taller than the minimum
+		image.Rect(30, 0, 60, 60),
+		image.Rect(0, 74, 30, 94), // centered in a line of 48
+	}
+	for i, c := range children {
+		if c.Rect != want[i] {
+			t.Errorf("child %d: Rect=%v, want %v", i, c.Rect, want[i])
+		}
+	}
+	if got := MeasureConstrained(&fl.Node, theme, image.Point{}, image.Point{60, Unbounded}); got.Y != 108 {
+		t.Errorf("measured height %d, want 108", got.Y)
+	}
+}
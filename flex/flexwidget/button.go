@@ -0,0 +1,257 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/focus"
+)
+
+// Default Button colors.
+var (
+	ButtonBackground        = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	ButtonHoverBackground   = color.RGBA{0xd0, 0xd0, 0xd0, 0xff}
+	ButtonPressedBackground = color.RGBA{0xb0, 0xb0, 0xb0, 0xff}
+	ButtonFocusRing         = color.RGBA{0x30, 0x60, 0xd0, 0xff}
+)
+
+// Button is a leaf widget that shows a Label on a background, and
+// calls OnClick when pressed and released.
+//
+// Its size is that of its label plus its padding, so it can be laid out
+// like any other flex item: it wraps its label when it is given less
+// than its natural width, and it reports the label's baselines.
+//
+// Button does not receive input events itself. An event loop calls
+// SetHovered, Press and Release as the pointer moves over it, and
+// repaints when they report a change. A Button can take focus from a
+// focus.Manager, and paints a ring in ButtonFocusRing while it has it.
+type Button struct {
+	widget.Node
+
+	// Label is the button's text. Its Node is not part of the
+	// widget tree; the Button measures and paints it.
+	Label *Label
+
+	// Padding separates the label from the edges of the button. If
+	// zero, flex.DefaultSpacing gives step S above and below the label
+	// and step M either side of it.
+	Padding flex.Insets
+
+	// Background, HoverBackground and PressedBackground fill the
+	// button in each of its states. If nil, the Button colors of this
+	// package are used.
+	Background        color.Color
+	HoverBackground   color.Color
+	PressedBackground color.Color
+
+	// OnClick, if non-nil, is called by Release.
+	OnClick func()
+
+	hovered, pressed, focused bool
+}
+
+// NewButton returns a new Button widget showing text.
+func NewButton(text string, onClick func()) *Button {
+	b := &Button{
+		Label:   NewLabel(text),
+		OnClick: onClick,
+	}
+	b.Node.Class = &buttonClass{button: b}
+	return b
+}
+
+// Hovered reports whether the pointer is over the button.
+func (b *Button) Hovered() bool { return b.hovered }
+
+// Pressed reports whether the button is held down.
+func (b *Button) Pressed() bool { return b.pressed }
+
+// Focused reports whether the button has focus.
+func (b *Button) Focused() bool { return b.focused }
+
+// SetHovered records whether the pointer is over the button, and
+// reports whether that changed.
+func (b *Button) SetHovered(hovered bool) (changed bool) {
+	changed = b.hovered != hovered
+	b.hovered = hovered
+	return changed
+}
+
+// Press holds the button down, and reports whether it was up.
+func (b *Button) Press() (changed bool) {
+	changed = !b.pressed
+	b.pressed = true
+	return changed
+}
+
+// Release lets the button up, and reports whether it was down. If it
+// was, and the pointer is still over it, OnClick is called. An event
+// loop cancels a press by calling SetHovered(false) first.
+func (b *Button) Release() (changed bool) {
+	if !b.pressed {
+		return false
+	}
+	b.pressed = false
+	if b.hovered && b.OnClick != nil {
+		b.OnClick()
+	}
+	return true
+}
+
+func (b *Button) background() color.Color {
+	switch {
+	case b.pressed && b.hovered:
+		if b.PressedBackground != nil {
+			return b.PressedBackground
+		}
+		return ButtonPressedBackground
+	case b.hovered:
+		if b.HoverBackground != nil {
+			return b.HoverBackground
+		}
+		return ButtonHoverBackground
+	}
+	if b.Background != nil {
+		return b.Background
+	}
+	return ButtonBackground
+}
+
+// insets returns the padding of the button.
+func (b *Button) insets(t *widget.Theme) flex.Insets {
+	if b.Padding != (flex.Insets{}) {
+		return b.Padding
+	}
+	sp := &flex.DefaultSpacing
+	v, h := sp.Pixels(t, flex.SpaceS), sp.Pixels(t, flex.SpaceM)
+	return flex.Insets{Top: v, Right: h, Bottom: v, Left: h}
+}
+
+// padding returns the total horizontal and vertical padding.
+func (b *Button) padding(t *widget.Theme) image.Point {
+	in := b.insets(t)
+	return image.Point{in.Left + in.Right, in.Top + in.Bottom}
+}
+
+// minHeight returns the minimum height of the button, from the
+// MinTarget of flex.DefaultSpacing.
+func (b *Button) minHeight(t *widget.Theme) int {
+	return flex.DefaultSpacing.MinTargetPixels(t)
+}
+
+var (
+	_ flex.ConstrainedMeasurer = (*buttonClass)(nil)
+	_ flex.Baseliner           = (*buttonClass)(nil)
+	_ flex.Accessible          = (*buttonClass)(nil)
+	_ focus.Focusable          = (*buttonClass)(nil)
+)
+
+type buttonClass struct {
+	widget.LeafClassEmbed
+
+	button *Button
+}
+
+func (k *buttonClass) Measure(n *widget.Node, t *widget.Theme) {
+	b := k.button
+	n.MeasuredSize = b.Label.size(-1).Add(b.padding(t))
+	n.MeasuredSize.Y = maxInt(n.MeasuredSize.Y, b.minHeight(t))
+}
+
+// MeasureConstrained implements flex.ConstrainedMeasurer. The label is
+// wrapped to fit within max, less the padding.
+func (k *buttonClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	b := k.button
+	pad := b.padding(t)
+	inner := max
+	if inner.X < flex.Unbounded {
+		inner.X = maxInt(inner.X-pad.X, 0)
+	}
+	if inner.Y < flex.Unbounded {
+		inner.Y = maxInt(inner.Y-pad.Y, 0)
+	}
+	l := &b.Label.Node
+	p := l.Class.(*labelClass).MeasureConstrained(l, t, image.Point{}, inner).Add(pad)
+	p.Y = maxInt(p.Y, b.minHeight(t))
+	if p.X > max.X {
+		p.X = max.X
+	}
+	if p.Y > max.Y {
+		p.Y = max.Y
+	}
+	if p.X < min.X {
+		p.X = min.X
+	}
+	if p.Y < min.Y {
+		p.Y = min.Y
+	}
+	return p
+}
+
+// layoutLabel sets the Rect of the label to fill n inside the padding,
+// relative to n.
+func (k *buttonClass) layoutLabel(n *widget.Node, t *widget.Theme) *widget.Node {
+	in := k.button.insets(t)
+	l := &k.button.Label.Node
+	l.Rect = image.Rect(in.Left, in.Top, n.Rect.Dx()-in.Right, n.Rect.Dy()-in.Bottom)
+	return l
+}
+
+func (k *buttonClass) Layout(n *widget.Node, t *widget.Theme) {
+	k.layoutLabel(n, t)
+}
+
+// FirstBaseline implements flex.Baseliner.
+func (k *buttonClass) FirstBaseline(n *widget.Node, t *widget.Theme) int {
+	l := &k.button.Label.Node
+	return k.button.insets(t).Top + l.Class.(*labelClass).FirstBaseline(l, t)
+}
+
+// LastBaseline implements flex.Baseliner.
+func (k *buttonClass) LastBaseline(n *widget.Node, t *widget.Theme, size image.Point) int {
+	in := k.button.insets(t)
+	l := &k.button.Label.Node
+	inner := image.Pt(size.X-in.Left-in.Right, size.Y-in.Top-in.Bottom)
+	return in.Top + l.Class.(*labelClass).LastBaseline(l, t, inner)
+}
+
+// Role implements flex.Accessible.
+func (k *buttonClass) Role(n *widget.Node) flex.Role { return flex.RoleButton }
+
+// Name implements flex.Accessible. It is the label's text.
+func (k *buttonClass) Name(n *widget.Node) string { return k.button.Label.Text }
+
+// FocusChanged implements focus.Focusable.
+func (k *buttonClass) FocusChanged(n *widget.Node, focused bool) {
+	k.button.focused = focused
+}
+
+func (k *buttonClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	r := n.Rect.Add(origin)
+	draw.Draw(dst, r, image.NewUniform(k.button.background()), image.Point{}, draw.Over)
+	l := k.layoutLabel(n, t)
+	l.Class.Paint(l, t, dst, r.Min)
+	if k.button.focused {
+		ring := image.NewUniform(ButtonFocusRing)
+		draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), ring, image.Point{}, draw.Src)
+		draw.Draw(dst, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), ring, image.Point{}, draw.Src)
+		draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y), ring, image.Point{}, draw.Src)
+		draw.Draw(dst, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), ring, image.Point{}, draw.Src)
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
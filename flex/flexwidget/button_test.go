@@ -0,0 +1,156 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/focus"
+)
+
+func TestButtonMeasure(t *testing.T) {
+	b := NewButton("the quick brown fox", nil)
+	b.Class.Measure(&b.Node, nil)
+	if want := image.Pt(133+16, 13+8); b.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", b.MeasuredSize, want)
+	}
+	// The label wraps inside the padding.
+	got := flex.MeasureConstrained(&b.Node, nil, image.Point{}, image.Pt(86, flex.Unbounded))
+	if want := image.Pt(63+16, 26+8); got != want {
+		t.Errorf("MeasureConstrained(86) = %v, want %v", got, want)
+	}
+}
+
+func TestButtonInRow(t *testing.T) {
+	row := flex.NewFlex()
+	row.AlignItem = flex.AlignItemBaseline
+	b := NewButton("OK", nil)
+	l := NewLabel("Save?")
+	row.AppendChild(&l.Node)
+	row.AppendChild(&b.Node)
+	row.Class.Measure(&row.Node, nil)
+	row.Rect = image.Rect(0, 0, 200, 40)
+	row.Class.Layout(&row.Node, nil)
+
+	// The label's baseline meets the button's, 4px lower.
+	if want := image.Rect(0, 4, 35, 17); l.Rect != want {
+		t.Errorf("label Rect=%v, want %v", l.Rect, want)
+	}
+	if want := image.Rect(35, 0, 65, 21); b.Rect != want {
+		t.Errorf("button Rect=%v, want %v", b.Rect, want)
+	}
+}
+
+func TestButtonStates(t *testing.T) {
+	clicks := 0
+	b := NewButton("OK", func() { clicks++ })
+	b.Class.Measure(&b.Node, nil)
+	b.Rect = image.Rectangle{Max: b.MeasuredSize}
+	b.Class.Layout(&b.Node, nil)
+
+	bg := func() color.Color {
+		dst := image.NewRGBA(b.Rect)
+		b.Class.Paint(&b.Node, nil, dst, image.Point{})
+		return dst.At(1, 1)
+	}
+	if got := bg(); got != ButtonBackground {
+		t.Errorf("idle background %v", got)
+	}
+	if !b.SetHovered(true) || b.SetHovered(true) {
+		t.Error("SetHovered did not report the change once")
+	}
+	if got := bg(); got != ButtonHoverBackground {
+		t.Errorf("hover background %v", got)
+	}
+	if !b.Press() || !b.Pressed() {
+		t.Error("Press did not press")
+	}
+	if got := bg(); got != ButtonPressedBackground {
+		t.Errorf("pressed background %v", got)
+	}
+	if !b.Release() || clicks != 1 {
+		t.Errorf("Release: %d clicks, want 1", clicks)
+	}
+	if b.Release() {
+		t.Error("second Release reported a change")
+	}
+
+	// Dragging off the button cancels the click.
+	b.Press()
+	b.SetHovered(false)
+	b.Release()
+	if clicks != 1 {
+		t.Errorf("cancelled press clicked: %d clicks", clicks)
+	}
+
+	a := flex.Accessibility(&b.Node, image.Point{})
+	if a.Role != flex.RoleButton || a.Name != "OK" {
+		t.Errorf("got role %v, name %q", a.Role, a.Name)
+	}
+}
+
+func TestButtonNarrow(t *testing.T) {
+	// A button squeezed below its padding still wraps its label.
+	col := flex.NewFlex()
+	col.Direction = flex.Column
+	b := NewButton("a b", nil)
+	col.AppendChild(&b.Node)
+	col.AppendChild(widget.NewUniform(color.Black, unit.Pixels(1), unit.Pixels(1)).Node)
+	col.Class.Measure(&col.Node, nil)
+	col.Rect = image.Rect(0, 0, 10, 100)
+	col.Class.Layout(&col.Node, nil)
+	if got := b.Rect.Dy(); got != 2*13+8 {
+		t.Errorf("button height %d, want %d", got, 2*13+8)
+	}
+}
+
+func TestButtonDensity(t *testing.T) {
+	defer func(sp flex.Spacing) { flex.DefaultSpacing = sp }(flex.DefaultSpacing)
+	flex.DefaultSpacing.Density = flex.DensityCompact
+	flex.DefaultSpacing.MinTarget = unit.Pixels(32)
+
+	b := NewButton("OK", nil)
+	b.Class.Measure(&b.Node, nil)
+	// Padding of 3px and 6px, and a minimum height of 24px.
+	if want := image.Pt(14+12, 24); b.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", b.MeasuredSize, want)
+	}
+
+	// Explicit padding is not scaled.
+	b.Padding = flex.Insets{Top: 1, Right: 1, Bottom: 1, Left: 1}
+	b.Class.Measure(&b.Node, nil)
+	if want := image.Pt(14+2, 24); b.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", b.MeasuredSize, want)
+	}
+}
+
+func TestButtonFocus(t *testing.T) {
+	b := NewButton("OK", nil)
+	row := flex.NewFlex()
+	row.AppendChild(&b.Node)
+	m := &focus.Manager{Root: &row.Node}
+	row.Class.Measure(&row.Node, nil)
+	row.Rect = image.Rect(0, 0, 100, 30)
+	row.Class.Layout(&row.Node, nil)
+
+	if !m.Next() || !b.Focused() {
+		t.Fatal("button did not take focus")
+	}
+	dst := image.NewRGBA(row.Rect)
+	row.Class.Paint(&row.Node, nil, dst, image.Point{})
+	if got := dst.RGBAAt(0, 5); got != ButtonFocusRing {
+		t.Errorf("edge of focused button is %v, want the focus ring", got)
+	}
+	m.Focus(nil)
+	if b.Focused() {
+		t.Error("button kept focus")
+	}
+}
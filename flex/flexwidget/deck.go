@@ -0,0 +1,166 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"time"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex/anim"
+)
+
+// Deck is a container widget that shows one of its children at a time,
+// like a deck of cards, filling the Deck's Rect with it.
+//
+// Its MeasuredSize is the largest of its children's, so that the Deck
+// does not change size as different children are shown.
+type Deck struct {
+	widget.Node
+
+	// Shown is the index of the child shown. If it is out of range,
+	// no child is shown.
+	Shown int
+
+	// Slide is the length of the animation Show runs, sliding the
+	// new child in over the old one. If zero, or if Ticker is nil,
+	// Show switches children at once.
+	Slide  time.Duration
+	Ticker *anim.Ticker
+
+	from  int // the child sliding out, or -1
+	tween anim.Tween
+	f     float64 // progress of the slide
+}
+
+// NewDeck returns a new Deck widget with the given children, showing
+// the first.
+func NewDeck(children ...*widget.Node) *Deck {
+	d := &Deck{from: -1}
+	d.Node.Class = &deckClass{deck: d}
+	for _, c := range children {
+		d.AppendChild(c)
+	}
+	return d
+}
+
+// Show shows the child at index i. If the Deck has a Slide and a
+// Ticker, the child slides in from the side of the one shown before:
+// from the right if it comes after it, and from the left if it comes
+// before it.
+func (d *Deck) Show(i int) {
+	if i == d.Shown {
+		return
+	}
+	from := d.Shown
+	d.Shown = i
+	if d.Slide <= 0 || d.Ticker == nil {
+		d.from = -1
+		return
+	}
+	running := d.from >= 0
+	d.from = from
+	d.tween = anim.Tween{Duration: d.Slide}
+	d.f = 0
+	if !running {
+		d.Ticker.Add(d.step)
+	}
+}
+
+func (d *Deck) step(now time.Time) bool {
+	d.f = d.tween.Progress(now)
+	if d.tween.Done(now) {
+		d.from = -1
+		return false
+	}
+	return true
+}
+
+// Sliding reports whether a slide started by Show is running.
+func (d *Deck) Sliding() bool {
+	return d.from >= 0
+}
+
+// ShownNode returns the child shown, or nil.
+func (d *Deck) ShownNode() *widget.Node {
+	return d.child(d.Shown)
+}
+
+func (d *Deck) child(i int) *widget.Node {
+	j := 0
+	for c := d.FirstChild; c != nil; c = c.NextSibling {
+		if j == i {
+			return c
+		}
+		j++
+	}
+	return nil
+}
+
+type deckClass struct {
+	widget.ContainerClassEmbed
+
+	deck *Deck
+}
+
+func (k *deckClass) Measure(n *widget.Node, t *widget.Theme) {
+	var size image.Point
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		c.Class.Measure(c, t)
+		if c.MeasuredSize.X > size.X {
+			size.X = c.MeasuredSize.X
+		}
+		if c.MeasuredSize.Y > size.Y {
+			size.Y = c.MeasuredSize.Y
+		}
+	}
+	n.MeasuredSize = size
+}
+
+func (k *deckClass) Layout(n *widget.Node, t *widget.Theme) {
+	for _, c := range []*widget.Node{k.deck.ShownNode(), k.from()} {
+		if c != nil {
+			c.Rect = image.Rectangle{Max: n.Rect.Size()}
+			c.Class.Layout(c, t)
+		}
+	}
+}
+
+// from returns the child sliding out, or nil.
+func (k *deckClass) from() *widget.Node {
+	if k.deck.from < 0 || k.deck.from == k.deck.Shown {
+		return nil
+	}
+	return k.deck.child(k.deck.from)
+}
+
+func (k *deckClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	d := k.deck
+	origin = origin.Add(n.Rect.Min)
+	from := k.from()
+	if from == nil {
+		if c := d.ShownNode(); c != nil {
+			c.Class.Paint(c, t, dst, origin)
+		}
+		return
+	}
+
+	// Slide the old child out and the new one in, clipped to n.
+	clip, ok := dst.SubImage(image.Rectangle{Min: origin, Max: origin.Add(n.Rect.Size())}).(*image.RGBA)
+	if !ok || clip.Rect.Empty() {
+		return
+	}
+	w := n.Rect.Dx()
+	dir := 1
+	if d.Shown < d.from {
+		dir = -1
+	}
+	off := anim.LerpInt(0, w, d.f) * dir
+	from.Class.Paint(from, t, clip, origin.Add(image.Pt(-off, 0)))
+	if c := d.ShownNode(); c != nil {
+		c.Class.Paint(c, t, clip, origin.Add(image.Pt(dir*w-off, 0)))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex/anim"
+)
+
+func TestDeckSlide(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	a := widget.NewUniform(red, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(blue, unit.Pixels(10), unit.Pixels(10)).Node
+	d := NewDeck(a, b)
+	tk := &anim.Ticker{}
+	d.Slide = time.Second
+	d.Ticker = tk
+	d.Rect = image.Rect(10, 0, 110, 10)
+	d.Class.Measure(&d.Node, nil)
+	d.Class.Layout(&d.Node, nil)
+
+	paint := func() *image.RGBA {
+		dst := image.NewRGBA(image.Rect(0, 0, 120, 10))
+		d.Class.Paint(&d.Node, nil, dst, image.Point{})
+		return dst
+	}
+
+	d.Show(1)
+	if !d.Sliding() || !tk.Running() {
+		t.Fatal("Show did not start a slide")
+	}
+	t0 := time.Unix(1000, 0)
+	tk.Step(t0)
+	tk.Step(t0.Add(time.Second / 2))
+	d.Class.Layout(&d.Node, nil)
+	dst := paint()
+	// Halfway: a has moved out to the left, b in from the right.
+	for _, test := range []struct {
+		x    int
+		want color.RGBA
+	}{
+		{5, color.RGBA{}},
+		{15, red},
+		{55, red},
+		{65, blue},
+		{105, blue},
+		{115, color.RGBA{}},
+	} {
+		if got := dst.RGBAAt(test.x, 5); got != test.want {
+			t.Errorf("halfway, x=%d is %v, want %v", test.x, got, test.want)
+		}
+	}
+
+	if tk.Step(t0.Add(time.Second)) || d.Sliding() {
+		t.Error("slide still running after Slide")
+	}
+	if got := paint().RGBAAt(15, 5); got != blue {
+		t.Errorf("after the slide, x=15 is %v, want blue", got)
+	}
+
+	// Without a Ticker, Show switches at once.
+	d.Ticker = nil
+	d.Show(0)
+	if d.Sliding() {
+		t.Error("Show without a Ticker slides")
+	}
+	if got := paint().RGBAAt(15, 5); got != red {
+		t.Errorf("x=15 is %v, want red", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Fit is how an Image scales its picture to its Rect, like the CSS
+// object-fit property.
+type Fit int8
+
+// Possible values of Fit.
+const (
+	// FitContain scales the picture to fit inside the Rect, keeping
+	// its aspect ratio, and centers it.
+	FitContain Fit = iota
+
+	// FitCover scales the picture to cover the Rect, keeping its
+	// aspect ratio, and centers it. The overflow is clipped.
+	FitCover
+
+	// FitFill stretches the picture to the Rect.
+	FitFill
+
+	// FitNone centers the picture at its natural size, clipped to the
+	// Rect.
+	FitNone
+)
+
+// Image is a leaf widget that shows a picture.
+//
+// Its MeasuredSize is the size of the picture. In a Flex it is measured
+// with flex.MeasureConstrained, keeping its aspect ratio: an image
+// given a width has the matching height, and the other way around, so
+// that flexed images scale instead of being distorted. Fit says how the
+// picture is painted when the Rect it is given does not match.
+type Image struct {
+	widget.Node
+
+	Src image.Image
+	Fit Fit
+
+	// Alt describes the picture to assistive technology.
+	Alt string
+}
+
+// NewImage returns a new Image widget showing src.
+func NewImage(src image.Image) *Image {
+	im := &Image{Src: src}
+	im.Node.Class = &imageClass{image: im}
+	return im
+}
+
+// size returns the natural size of the picture.
+func (im *Image) size() image.Point {
+	if im.Src == nil {
+		return image.Point{}
+	}
+	return im.Src.Bounds().Size()
+}
+
+// AspectRatio returns the width of the picture divided by its height,
+// or 0 if it is empty.
+func (im *Image) AspectRatio() float64 {
+	s := im.size()
+	if s.X <= 0 || s.Y <= 0 {
+		return 0
+	}
+	return float64(s.X) / float64(s.Y)
+}
+
+// dstRect returns where the picture is painted for a Rect r.
+func (im *Image) dstRect(r image.Rectangle) image.Rectangle {
+	s := im.size()
+	if im.Fit == FitFill || s.X <= 0 || s.Y <= 0 {
+		return r
+	}
+	scale := 1.0
+	sx := float64(r.Dx()) / float64(s.X)
+	sy := float64(r.Dy()) / float64(s.Y)
+	switch im.Fit {
+	case FitContain:
+		scale = math.Min(sx, sy)
+	case FitCover:
+		scale = math.Max(sx, sy)
+	}
+	w := int(math.Floor(float64(s.X)*scale + 0.5))
+	h := int(math.Floor(float64(s.Y)*scale + 0.5))
+	min := image.Point{r.Min.X + (r.Dx()-w)/2, r.Min.Y + (r.Dy()-h)/2}
+	return image.Rectangle{min, min.Add(image.Point{w, h})}
+}
+
+var (
+	_ flex.ConstrainedMeasurer = (*imageClass)(nil)
+	_ flex.Accessible          = (*imageClass)(nil)
+)
+
+type imageClass struct {
+	widget.LeafClassEmbed
+
+	image *Image
+}
+
+func (k *imageClass) Measure(n *widget.Node, t *widget.Theme) {
+	n.MeasuredSize = k.image.size()
+}
+
+// MeasureConstrained implements flex.ConstrainedMeasurer. The width is
+// the natural width within [min.X, max.X], and the height follows it
+// at the picture's aspect ratio, within [min.Y, max.Y]. If the height
+// is out of bounds, the width follows the bounded height instead.
+func (k *imageClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	s := k.image.size()
+	r := k.image.AspectRatio()
+	if r == 0 {
+		return image.Point{clamp(s.X, min.X, max.X), clamp(s.Y, min.Y, max.Y)}
+	}
+	w := clamp(s.X, min.X, max.X)
+	h := int(math.Floor(float64(w)/r + 0.5))
+	if ch := clamp(h, min.Y, max.Y); ch != h {
+		h = ch
+		w = clamp(int(math.Floor(float64(h)*r+0.5)), min.X, max.X)
+	}
+	return image.Point{w, h}
+}
+
+// Role implements flex.Accessible.
+func (k *imageClass) Role(n *widget.Node) flex.Role { return flex.RoleImage }
+
+// Name implements flex.Accessible. It is the Alt text.
+func (k *imageClass) Name(n *widget.Node) string { return k.image.Alt }
+
+func (k *imageClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	im := k.image
+	if im.Src == nil {
+		return
+	}
+	r := n.Rect.Add(origin)
+	clip, ok := dst.SubImage(r).(*image.RGBA)
+	if !ok || clip.Rect.Empty() {
+		return
+	}
+	d := im.dstRect(r)
+	if d.Size() == im.size() {
+		draw.Draw(clip, d, im.Src, im.Src.Bounds().Min, draw.Over)
+		return
+	}
+	xdraw.ApproxBiLinear.Scale(clip, d, im.Src, im.Src.Bounds(), draw.Over, nil)
+}
+
+func clamp(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
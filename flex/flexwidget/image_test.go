@@ -0,0 +1,100 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+func picture(w, h int) *image.RGBA {
+	m := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(m, m.Bounds(), image.NewUniform(color.RGBA{0xff, 0x00, 0x00, 0xff}), image.Point{}, draw.Src)
+	return m
+}
+
+func TestImageMeasure(t *testing.T) {
+	im := NewImage(picture(100, 50))
+	im.Class.Measure(&im.Node, nil)
+	if want := image.Pt(100, 50); im.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", im.MeasuredSize, want)
+	}
+	if r := im.AspectRatio(); r != 2 {
+		t.Errorf("AspectRatio=%v, want 2", r)
+	}
+	for _, test := range []struct {
+		min, max image.Point
+		want     image.Point
+	}{
+		{image.Point{}, image.Pt(flex.Unbounded, flex.Unbounded), image.Pt(100, 50)},
+		{image.Point{}, image.Pt(60, flex.Unbounded), image.Pt(60, 30)},
+		{image.Pt(300, 0), image.Pt(300, flex.Unbounded), image.Pt(300, 150)},
+		{image.Pt(0, 20), image.Pt(flex.Unbounded, 20), image.Pt(40, 20)},
+	} {
+		got := flex.MeasureConstrained(&im.Node, nil, test.min, test.max)
+		if got != test.want {
+			t.Errorf("MeasureConstrained(%v, %v) = %v, want %v", test.min, test.max, got, test.want)
+		}
+	}
+}
+
+func TestImageInRow(t *testing.T) {
+	row := flex.NewFlex()
+	row.Wrap = flex.Wrap
+	row.AlignContent = flex.AlignContentStart
+	im := NewImage(picture(100, 50))
+	row.AppendChild(&im.Node)
+	row.Class.Measure(&row.Node, nil)
+	row.Rect = image.Rect(0, 0, 60, 100)
+	row.Class.Layout(&row.Node, nil)
+
+	// Shrunk to the row's width, the image keeps its shape.
+	if want := image.Rect(0, 0, 60, 30); im.Rect != want {
+		t.Errorf("Rect=%v, want %v", im.Rect, want)
+	}
+}
+
+func TestImageFit(t *testing.T) {
+	r := image.Rect(0, 0, 100, 100)
+	for _, test := range []struct {
+		fit  Fit
+		want image.Rectangle
+	}{
+		{FitContain, image.Rect(0, 25, 100, 75)},
+		{FitCover, image.Rect(-50, 0, 150, 100)},
+		{FitFill, image.Rect(0, 0, 100, 100)},
+		{FitNone, image.Rect(0, 25, 100, 75)},
+	} {
+		im := NewImage(picture(100, 50))
+		im.Fit = test.fit
+		if got := im.dstRect(r); got != test.want {
+			t.Errorf("fit %d: dstRect=%v, want %v", test.fit, got, test.want)
+		}
+	}
+
+	// Painting stays inside the Rect.
+	im := NewImage(picture(100, 50))
+	im.Fit = FitCover
+	im.Rect = image.Rect(10, 10, 30, 30)
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	im.Class.Paint(&im.Node, nil, dst, image.Point{})
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			in := image.Pt(x, y).In(im.Rect)
+			if painted := dst.RGBAAt(x, y).A != 0; painted != in {
+				t.Fatalf("pixel (%d, %d): painted %t, in Rect %t", x, y, painted, in)
+			}
+		}
+	}
+
+	a := flex.Accessibility(&im.Node, image.Point{})
+	if a.Role != flex.RoleImage {
+		t.Errorf("role %v, want image", a.Role)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package flexwidget provides widgets that cooperate with the flex
+// layout: they report how their size depends on the space they are
+// given, and where their text baselines are.
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Label is a leaf widget that shows text, wrapped at spaces to fit the
+// width of its Rect. Newlines in Text always break lines.
+//
+// Its MeasuredSize is its max-content size, with no wrapping other than
+// at newlines. In a Flex it is measured with flex.MeasureConstrained,
+// so that its height follows the width it is given.
+type Label struct {
+	widget.Node
+
+	Text string
+
+	// Face is the font face. If nil, basicfont.Face7x13 is used.
+	Face font.Face
+
+	// Color is the text color. If nil, black is used.
+	Color color.Color
+}
+
+// NewLabel returns a new Label widget.
+func NewLabel(text string) *Label {
+	l := &Label{Text: text}
+	l.Node.Class = &labelClass{label: l}
+	return l
+}
+
+func (l *Label) face() font.Face {
+	if l.Face == nil {
+		return basicfont.Face7x13
+	}
+	return l.Face
+}
+
+// MinContentWidth returns the width of the longest word, the narrowest
+// the label can be without overflowing.
+func (l *Label) MinContentWidth() int {
+	face := l.face()
+	w := 0
+	for _, word := range strings.Fields(l.Text) {
+		if ww := advance(face, word); ww > w {
+			w = ww
+		}
+	}
+	return w
+}
+
+// MaxContentWidth returns the width of the longest line when the text
+// is not wrapped.
+func (l *Label) MaxContentWidth() int {
+	face := l.face()
+	w := 0
+	for _, line := range l.lines(-1) {
+		if lw := advance(face, line); lw > w {
+			w = lw
+		}
+	}
+	return w
+}
+
+// lines breaks the text into lines no wider than width, if possible.
+// A negative width breaks lines only at newlines.
+func (l *Label) lines(width int) []string {
+	face := l.face()
+	var lines []string
+	for _, para := range strings.Split(l.Text, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if width >= 0 && advance(face, line+" "+word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// size returns the size of the text wrapped at width.
+func (l *Label) size(width int) image.Point {
+	face := l.face()
+	lines := l.lines(width)
+	w := 0
+	for _, line := range lines {
+		if lw := advance(face, line); lw > w {
+			w = lw
+		}
+	}
+	return image.Point{w, len(lines) * face.Metrics().Height.Ceil()}
+}
+
+func advance(face font.Face, s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+var (
+	_ flex.ConstrainedMeasurer = (*labelClass)(nil)
+	_ flex.Baseliner           = (*labelClass)(nil)
+	_ flex.Accessible          = (*labelClass)(nil)
+)
+
+type labelClass struct {
+	widget.LeafClassEmbed
+
+	label *Label
+}
+
+func (k *labelClass) Measure(n *widget.Node, t *widget.Theme) {
+	n.MeasuredSize = k.label.size(-1)
+}
+
+// MeasureConstrained implements flex.ConstrainedMeasurer. The text is
+// wrapped at the widest width allowed, and the label is no narrower
+// than min.X.
+func (k *labelClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	width := -1
+	if max.X < flex.Unbounded {
+		width = max.X
+	}
+	p := k.label.size(width)
+	if p.X > max.X {
+		p.X = max.X
+	}
+	if p.Y > max.Y {
+		p.Y = max.Y
+	}
+	if p.X < min.X {
+		p.X = min.X
+	}
+	if p.Y < min.Y {
+		p.Y = min.Y
+	}
+	return p
+}
+
+// FirstBaseline returns the baseline of the first line of text, in
+// pixels from the top of the label.
+func (k *labelClass) FirstBaseline(n *widget.Node, t *widget.Theme) int {
+	return k.label.face().Metrics().Ascent.Ceil()
+}
+
+// LastBaseline returns the baseline of the last line of text, wrapped
+// to the width of size, in pixels from the top of the label.
+func (k *labelClass) LastBaseline(n *widget.Node, t *widget.Theme, size image.Point) int {
+	m := k.label.face().Metrics()
+	lines := len(k.label.lines(size.X))
+	return (lines-1)*m.Height.Ceil() + m.Ascent.Ceil()
+}
+
+// Role implements flex.Accessible.
+func (k *labelClass) Role(n *widget.Node) flex.Role { return flex.RoleText }
+
+// Name implements flex.Accessible. It is the label's text.
+func (k *labelClass) Name(n *widget.Node) string { return k.label.Text }
+
+func (k *labelClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	l := k.label
+	r := n.Rect.Add(origin)
+	clip, ok := dst.SubImage(r).(*image.RGBA)
+	if !ok || clip.Rect.Empty() {
+		return
+	}
+	c := l.Color
+	if c == nil {
+		c = color.Black
+	}
+	face := l.face()
+	m := face.Metrics()
+	d := &font.Drawer{
+		Dst:  clip,
+		Src:  image.NewUniform(c),
+		Face: face,
+	}
+	y := r.Min.Y + m.Ascent.Ceil()
+	for _, line := range l.lines(r.Dx()) {
+		d.Dot = fixed.P(r.Min.X, y)
+		d.DrawString(line)
+		y += m.Height.Ceil()
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// The default face, basicfont.Face7x13, has 7px advances, 13px lines
+// and an 11px ascent.
+
+func TestLabelLines(t *testing.T) {
+	l := NewLabel("the quick brown\nfox")
+	tests := []struct {
+		width int
+		want  []string
+	}{
+		{-1, []string{"the quick brown", "fox"}},
+		{70, []string{"the quick", "brown", "fox"}},
+		{20, []string{"the", "quick", "brown", "fox"}},
+	}
+	for _, test := range tests {
+		if got := l.lines(test.width); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("lines(%d) = %q, want %q", test.width, got, test.want)
+		}
+	}
+	if got, want := l.MinContentWidth(), 35; got != want {
+		t.Errorf("MinContentWidth=%d, want %d", got, want)
+	}
+	if got, want := l.MaxContentWidth(), 105; got != want {
+		t.Errorf("MaxContentWidth=%d, want %d", got, want)
+	}
+}
+
+func TestLabelMeasure(t *testing.T) {
+	l := NewLabel("the quick brown fox")
+	l.Class.Measure(&l.Node, nil)
+	if want := image.Pt(133, 13); l.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", l.MeasuredSize, want)
+	}
+	got := flex.MeasureConstrained(&l.Node, nil, image.Point{}, image.Pt(70, flex.Unbounded))
+	if want := image.Pt(63, 26); got != want {
+		t.Errorf("MeasureConstrained(70) = %v, want %v", got, want)
+	}
+	got = flex.MeasureConstrained(&l.Node, nil, image.Pt(80, 0), image.Pt(80, flex.Unbounded))
+	if want := image.Pt(80, 26); got != want {
+		t.Errorf("MeasureConstrained(80..80) = %v, want %v", got, want)
+	}
+}
+
+func TestLabelInColumn(t *testing.T) {
+	col := flex.NewFlex()
+	col.Direction = flex.Column
+	l := NewLabel("the quick brown fox")
+	below := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	col.AppendChild(&l.Node)
+	col.AppendChild(below)
+
+	col.Class.Measure(&col.Node, nil)
+	col.Rect = image.Rect(0, 0, 70, 100)
+	col.Class.Layout(&col.Node, nil)
+	if want := image.Rect(0, 0, 63, 26); l.Rect != want {
+		t.Errorf("label Rect=%v, want %v", l.Rect, want)
+	}
+	if want := image.Rect(0, 26, 10, 36); below.Rect != want {
+		t.Errorf("below Rect=%v, want %v", below.Rect, want)
+	}
+	k := l.Class.(*labelClass)
+	if got := k.FirstBaseline(&l.Node, nil); got != 11 {
+		t.Errorf("FirstBaseline=%d, want 11", got)
+	}
+	if got := k.LastBaseline(&l.Node, nil, l.Rect.Size()); got != 24 {
+		t.Errorf("LastBaseline=%d, want 24", got)
+	}
+}
+
+func TestLabelBaseline(t *testing.T) {
+	row := flex.NewFlex()
+	row.AlignItem = flex.AlignItemBaseline
+	l := NewLabel("x")
+	box := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(30)).Node
+	row.AppendChild(&l.Node)
+	row.AppendChild(box)
+	row.Class.Measure(&row.Node, nil)
+	row.Rect = image.Rect(0, 0, 100, 40)
+	row.Class.Layout(&row.Node, nil)
+
+	// The box sits on the label's baseline, 11px below its top.
+	if want := image.Rect(0, 19, 7, 32); l.Rect != want {
+		t.Errorf("label Rect=%v, want %v", l.Rect, want)
+	}
+	if want := image.Rect(7, 0, 17, 30); box.Rect != want {
+		t.Errorf("box Rect=%v, want %v", box.Rect, want)
+	}
+}
+
+func TestLabelPaint(t *testing.T) {
+	l := NewLabel("hi")
+	l.Color = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	l.Class.Measure(&l.Node, nil)
+	l.Rect = image.Rectangle{Max: l.MeasuredSize}.Add(image.Pt(5, 5))
+
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	l.Class.Paint(&l.Node, nil, dst, image.Point{})
+	painted := 0
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			if dst.RGBAAt(x, y).R == 0 {
+				continue
+			}
+			painted++
+			if !(image.Point{x, y}.In(l.Rect)) {
+				t.Fatalf("painted (%d, %d) outside %v", x, y, l.Rect)
+			}
+		}
+	}
+	if painted == 0 {
+		t.Error("nothing painted")
+	}
+}
+
+func TestLabelAccessibility(t *testing.T) {
+	l := NewLabel("hello")
+	a := flex.Accessibility(&l.Node, image.Point{})
+	if a.Role != flex.RoleText || a.Name != "hello" {
+		t.Errorf("got role %v, name %q", a.Role, a.Name)
+	}
+}
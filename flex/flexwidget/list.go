@@ -0,0 +1,267 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/dispatch"
+)
+
+// ListWheelStep is the distance, in pixels, a List scrolls for each
+// step of a mouse wheel.
+var ListWheelStep = 20
+
+// List is a vertically scrolling widget showing Count items, only the
+// visible ones of which exist as nodes.
+//
+// A List asks NewItem for nodes and Bind to make a node show an item,
+// and recycles the nodes of items scrolled out of view. Each item is
+// as wide as the List and measured with flex.MeasureConstrained, so an
+// item can be a Flex, a wrapping Label or anything else whose height
+// follows its width. Items are not measured until they are scrolled
+// into view, so a List of millions of items is as cheap as one of a
+// screenful.
+//
+// The children of a List are the nodes of the visible items, in order.
+// They must not be changed other than through the List.
+type List struct {
+	widget.Node
+
+	// Count is the number of items.
+	Count int
+
+	// NewItem returns a new node to show an item.
+	NewItem func() *widget.Node
+
+	// Bind makes n, a node returned by NewItem, show item i. The node
+	// may have shown another item before.
+	Bind func(n *widget.Node, i int)
+
+	// Gap is the space between items, in pixels.
+	Gap int
+
+	// first is the index of the first visible item, offset pixels of
+	// which are scrolled above the top of the List. scroll is pending
+	// scrolling, applied by the next Layout.
+	first, offset, scroll int
+
+	items map[*widget.Node]int // the item each node is bound to
+	pool  []*widget.Node       // nodes not in the tree
+}
+
+// NewList returns a new List widget of count items.
+func NewList(count int, newItem func() *widget.Node, bind func(n *widget.Node, i int)) *List {
+	l := &List{
+		Count:   count,
+		NewItem: newItem,
+		Bind:    bind,
+		items:   make(map[*widget.Node]int),
+	}
+	l.Node.Class = &listClass{list: l}
+	return l
+}
+
+// ScrollBy scrolls the list down by dy pixels, or up if dy is negative,
+// as of the next Layout. Scrolling stops at the ends of the list.
+func (l *List) ScrollBy(dy int) {
+	l.scroll += dy
+}
+
+// ScrollTo scrolls item i to the top of the list, as of the next
+// Layout.
+func (l *List) ScrollTo(i int) {
+	l.first, l.offset, l.scroll = i, 0, 0
+}
+
+// Visible returns the indexes of the items visible after the last
+// Layout, from first up to but not including end.
+func (l *List) Visible() (first, end int) {
+	n := 0
+	for c := l.FirstChild; c != nil; c = c.NextSibling {
+		n++
+	}
+	return l.first, l.first + n
+}
+
+// node returns a node bound to item i, recycled if possible.
+func (l *List) node(i int) *widget.Node {
+	var n *widget.Node
+	if len(l.pool) > 0 {
+		n, l.pool = l.pool[len(l.pool)-1], l.pool[:len(l.pool)-1]
+	} else {
+		n = l.NewItem()
+	}
+	l.Bind(n, i)
+	l.items[n] = i
+	return n
+}
+
+// height returns the height of n, bound to an item, at the given width.
+func height(n *widget.Node, t *widget.Theme, width int) int {
+	n.Class.Measure(n, t)
+	return flex.MeasureConstrained(n, t, image.Point{width, 0}, image.Point{width, flex.Unbounded}).Y
+}
+
+var _ dispatch.Handler = (*listClass)(nil)
+
+type listClass struct {
+	widget.ContainerClassEmbed
+
+	list *List
+}
+
+// Measure gives the List the size of its widest visible item and no
+// height: it is meant to be given its height by its container.
+func (k *listClass) Measure(n *widget.Node, t *widget.Theme) {
+	w := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		c.Class.Measure(c, t)
+		if c.MeasuredSize.X > w {
+			w = c.MeasuredSize.X
+		}
+	}
+	n.MeasuredSize = image.Point{w, 0}
+}
+
+func (k *listClass) Layout(n *widget.Node, t *widget.Theme) {
+	l := k.list
+	width, viewport := n.Rect.Dx(), n.Rect.Dy()
+
+	// Detach the current children. Those still visible are found
+	// again by find, the rest are recycled below.
+	var old []*widget.Node
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		n.RemoveChild(c)
+		old = append(old, c)
+		c = next
+	}
+	find := func(i int) *widget.Node {
+		for j, c := range old {
+			if c != nil && l.items[c] == i {
+				old[j] = nil
+				return c
+			}
+		}
+		return l.node(i)
+	}
+	recycle := func(c *widget.Node) {
+		delete(l.items, c)
+		l.pool = append(l.pool, c)
+	}
+
+	if l.Count <= 0 {
+		l.first, l.offset, l.scroll = 0, 0, 0
+		for _, c := range old {
+			recycle(c)
+		}
+		return
+	}
+	if l.first >= l.Count {
+		l.first, l.offset = l.Count-1, 0
+	}
+	if l.first < 0 {
+		l.first, l.offset = 0, 0
+	}
+	l.offset += l.scroll
+	l.scroll = 0
+
+	// heights caches the heights of the items measured by this Layout.
+	heights := make(map[int]int)
+	nodes := make(map[int]*widget.Node)
+	measure := func(i int) int {
+		if h, ok := heights[i]; ok {
+			return h
+		}
+		c := find(i)
+		nodes[i] = c
+		h := height(c, t, width)
+		heights[i] = h
+		return h
+	}
+
+	for pass := 0; pass < 2; pass++ {
+		// Move the anchor to the item at the top of the viewport.
+		for l.offset < 0 && l.first > 0 {
+			l.first--
+			l.offset += measure(l.first) + l.Gap
+		}
+		if l.offset < 0 {
+			l.offset = 0
+		}
+		for l.first < l.Count-1 && l.offset >= measure(l.first)+l.Gap {
+			l.offset -= measure(l.first) + l.Gap
+			l.first++
+		}
+
+		// Don't scroll past the end of the list.
+		y, i := -l.offset, l.first
+		for ; i < l.Count && y < viewport; i++ {
+			y += measure(i) + l.Gap
+		}
+		bottom := y - l.Gap
+		if pass > 0 || i < l.Count || bottom >= viewport || (l.first == 0 && l.offset == 0) {
+			break
+		}
+		l.offset -= viewport - bottom
+	}
+
+	y := -l.offset
+	for i := l.first; i < l.Count && y < viewport; i++ {
+		h := measure(i)
+		c := nodes[i]
+		delete(nodes, i)
+		c.Rect = image.Rect(0, y, width, y+h)
+		n.AppendChild(c)
+		c.Class.Layout(c, t)
+		y += h + l.Gap
+	}
+
+	// Recycle nodes measured for items out of view, and those of
+	// items scrolled away.
+	for _, c := range nodes {
+		recycle(c)
+	}
+	for _, c := range old {
+		if c != nil {
+			recycle(c)
+		}
+	}
+}
+
+// PointerEvent implements dispatch.Handler, scrolling the list with
+// the mouse wheel.
+func (k *listClass) PointerEvent(n *widget.Node, e *dispatch.Event) {
+	me, ok := e.Event.(mouse.Event)
+	if !ok || me.Direction != mouse.DirStep && me.Direction != mouse.DirPress {
+		return
+	}
+	switch me.Button {
+	case mouse.ButtonWheelUp:
+		k.list.ScrollBy(-ListWheelStep)
+	case mouse.ButtonWheelDown:
+		k.list.ScrollBy(ListWheelStep)
+	default:
+		return
+	}
+	e.StopPropagation()
+}
+
+func (k *listClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	r := n.Rect.Add(origin)
+	clip, ok := dst.SubImage(r).(*image.RGBA)
+	if !ok || clip.Rect.Empty() {
+		return
+	}
+	origin = r.Min
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		c.Class.Paint(c, t, clip, origin)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/dispatch"
+)
+
+// rowClass is a list item whose height depends on the item it shows.
+type rowClass struct {
+	widget.LeafClassEmbed
+	item int
+}
+
+func (k *rowClass) Measure(n *widget.Node, t *widget.Theme) {
+	n.MeasuredSize = image.Point{50, 10 + k.item%3*5} // 10, 15 or 20
+}
+
+func newTestList(count int) (*List, *int) {
+	created := 0
+	l := NewList(count,
+		func() *widget.Node {
+			created++
+			return &widget.Node{Class: new(rowClass)}
+		},
+		func(n *widget.Node, i int) { n.Class.(*rowClass).item = i },
+	)
+	l.Gap = 1
+	l.Rect = image.Rect(0, 0, 100, 50)
+	return l, &created
+}
+
+func items(l *List) []int {
+	var got []int
+	for c := l.FirstChild; c != nil; c = c.NextSibling {
+		got = append(got, c.Class.(*rowClass).item)
+	}
+	return got
+}
+
+func TestList(t *testing.T) {
+	l, created := newTestList(1000000)
+	l.Class.Layout(&l.Node, nil)
+
+	// Items 0 to 3 are 10, 15, 20 and 10 high, 1px apart.
+	if first, end := l.Visible(); first != 0 || end != 4 {
+		t.Errorf("Visible() = %d, %d, want 0, 4", first, end)
+	}
+	want := []image.Rectangle{
+		image.Rect(0, 0, 100, 10),
+		image.Rect(0, 11, 100, 26),
+		image.Rect(0, 27, 100, 47),
+		image.Rect(0, 48, 100, 58),
+	}
+	i := 0
+	for c := l.FirstChild; c != nil; c = c.NextSibling {
+		if c.Rect != want[i] {
+			t.Errorf("item %d: Rect=%v, want %v", i, c.Rect, want[i])
+		}
+		i++
+	}
+
+	// Scrolling recycles nodes.
+	for j := 0; j < 100; j++ {
+		l.ScrollBy(13)
+		l.Class.Layout(&l.Node, nil)
+	}
+	if *created > 10 {
+		t.Errorf("created %d nodes for a list showing at most 6", *created)
+	}
+	got := items(l)
+	for j := 1; j < len(got); j++ {
+		if got[j] != got[j-1]+1 {
+			t.Fatalf("visible items %v are not consecutive", got)
+		}
+	}
+	if first := l.FirstChild; first.Rect.Min.Y > 0 || first.Rect.Max.Y <= 0 {
+		t.Errorf("first visible item at %v, want it across the top", first.Rect)
+	}
+
+	// Scrolling back up to the top stops there.
+	l.ScrollBy(-1000000)
+	l.Class.Layout(&l.Node, nil)
+	if got := items(l); got[0] != 0 || l.FirstChild.Rect.Min.Y != 0 {
+		t.Errorf("after scrolling to the top, items %v start at %v", got, l.FirstChild.Rect)
+	}
+}
+
+func TestListEnd(t *testing.T) {
+	l, _ := newTestList(10)
+	l.ScrollTo(9)
+	l.Class.Layout(&l.Node, nil)
+
+	// The last item ends at the bottom, not the top, of the list.
+	if last := l.LastChild; last.Class.(*rowClass).item != 9 || last.Rect.Max.Y != 50 {
+		t.Errorf("last item %d at %v, want 9 ending at 50", last.Class.(*rowClass).item, last.Rect)
+	}
+
+	// A list shorter than its Rect starts at the top.
+	l, _ = newTestList(2)
+	l.ScrollBy(30)
+	l.Class.Layout(&l.Node, nil)
+	if got := items(l); len(got) != 2 || l.FirstChild.Rect.Min.Y != 0 {
+		t.Errorf("short list: items %v from %v", got, l.FirstChild.Rect)
+	}
+
+	l.Count = 0
+	l.Class.Layout(&l.Node, nil)
+	if l.FirstChild != nil {
+		t.Error("empty list has children")
+	}
+}
+
+func TestListLabels(t *testing.T) {
+	// Labels wrap to the list's width.
+	texts := []string{"short", "a much longer line of text"}
+	l := NewList(len(texts),
+		func() *widget.Node { return &NewLabel("").Node },
+		func(n *widget.Node, i int) { n.Class.(*labelClass).label.Text = texts[i] },
+	)
+	l.Rect = image.Rect(0, 0, 100, 100)
+	l.Class.Layout(&l.Node, nil)
+	if got := l.LastChild.Rect; got != image.Rect(0, 13, 100, 39) {
+		t.Errorf("wrapped label Rect=%v, want 2 lines", got)
+	}
+}
+
+func TestListWheel(t *testing.T) {
+	l, _ := newTestList(100)
+	root := flex.NewFlex()
+	root.AppendChild(&l.Node)
+	root.Rect = image.Rect(0, 0, 100, 50)
+	root.AlignItem = flex.AlignItemStretch
+	l.LayoutData = flex.LayoutData{Grow: 1}
+	root.Class.Layout(&root.Node, nil)
+
+	d := &dispatch.Dispatcher{Root: &root.Node}
+	if !d.Dispatch(mouse.Event{X: 5, Y: 5, Button: mouse.ButtonWheelDown, Direction: mouse.DirStep}) {
+		t.Fatal("wheel not handled")
+	}
+	root.Class.Layout(&root.Node, nil)
+	// Items 0 and 1 take 27px; scrolling 20px leaves 7px of item 1.
+	if first, _ := l.Visible(); first != 1 || l.FirstChild.Rect.Min.Y != -9 {
+		t.Errorf("after the wheel, first=%d at %d, want 1 at -9", first, l.FirstChild.Rect.Min.Y)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Tab colors.
+var (
+	TabBackground         = ButtonBackground
+	SelectedTabBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Tab is a tab of a Tabs widget.
+type Tab struct {
+	Title   string
+	Content *widget.Node
+
+	// Closable is whether the tab has a close button.
+	Closable bool
+
+	node   *flex.Flex // the tab in the strip
+	button *Button
+}
+
+// Tabs is a widget with a strip of tabs above a Deck of their contents,
+// showing the content of the selected tab.
+//
+// Tabs are Buttons laid out in a flex.Row at their natural width. When
+// they are wider than the Tabs, the strip scrolls: ScrollStrip moves
+// it, and SelectTab scrolls the selected tab into view.
+//
+// MoveTab reorders the tabs, for example as a tab is dragged along the
+// strip.
+type Tabs struct {
+	*flex.Flex
+
+	// OnSelect, if non-nil, is called with the index of a newly
+	// selected tab.
+	OnSelect func(i int)
+
+	// OnClose, if non-nil, is called with a tab closed by its close
+	// button, after it is removed.
+	OnClose func(tab *Tab)
+
+	tabs     []*Tab
+	selected int
+	scroll   int // strip scroll offset, in pixels
+
+	strip    *widget.Node
+	tabRow   *flex.Flex
+	deck     *Deck
+	scrollTo bool // scroll the selected tab into view at the next Layout
+}
+
+// NewTabs returns a new Tabs widget with no tabs.
+func NewTabs() *Tabs {
+	ts := &Tabs{
+		Flex:   flex.NewFlex(),
+		tabRow: flex.NewFlex(),
+		deck:   NewDeck(),
+	}
+	ts.Direction = flex.Column
+	ts.AlignItem = flex.AlignItemStretch
+	ts.tabRow.AlignItem = flex.AlignItemStretch
+	ts.strip = &widget.Node{Class: &stripClass{tabs: ts}}
+	ts.strip.AppendChild(&ts.tabRow.Node)
+	ts.AppendChild(ts.strip)
+	ts.deck.LayoutData = flex.LayoutData{Grow: 1}
+	ts.AppendChild(&ts.deck.Node)
+	return ts
+}
+
+// Len returns the number of tabs.
+func (ts *Tabs) Len() int { return len(ts.tabs) }
+
+// Tab returns the i'th tab.
+func (ts *Tabs) Tab(i int) *Tab { return ts.tabs[i] }
+
+// Selected returns the index of the selected tab, or -1 if there are
+// no tabs.
+func (ts *Tabs) Selected() int {
+	if len(ts.tabs) == 0 {
+		return -1
+	}
+	return ts.selected
+}
+
+// AddTab adds a tab at the end of the strip and returns it. The first
+// tab added is selected.
+func (ts *Tabs) AddTab(title string, content *widget.Node, closable bool) *Tab {
+	tab := &Tab{Title: title, Content: content, Closable: closable}
+	tab.node = flex.NewFlex()
+	tab.node.AlignItem = flex.AlignItemStretch
+	tab.node.LayoutData = flex.LayoutData{Shrink: new(float64)}
+	tab.button = NewButton(title, func() { ts.SelectTab(ts.index(tab)) })
+	tab.node.AppendChild(&tab.button.Node)
+	if closable {
+		close := NewButton("x", func() { ts.closeTab(tab) })
+		sp := &flex.DefaultSpacing
+		v, h := sp.Pixels(nil, flex.SpaceS), sp.Pixels(nil, flex.SpaceM)
+		close.Padding = flex.Insets{Top: v, Right: h, Bottom: v}
+		tab.node.AppendChild(&close.Node)
+	}
+	ts.tabs = append(ts.tabs, tab)
+	ts.tabRow.AppendChild(&tab.node.Node)
+	ts.deck.AppendChild(content)
+	ts.update()
+	return tab
+}
+
+func (ts *Tabs) index(tab *Tab) int {
+	for i, t := range ts.tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectTab selects the i'th tab, shows its content and scrolls it into
+// view at the next Layout.
+func (ts *Tabs) SelectTab(i int) {
+	if i < 0 || i >= len(ts.tabs) {
+		return
+	}
+	changed := i != ts.selected
+	ts.selected = i
+	ts.scrollTo = true
+	ts.update()
+	if changed && ts.OnSelect != nil {
+		ts.OnSelect(i)
+	}
+}
+
+// CloseTab removes the i'th tab. If it was selected, the tab after it,
+// or else the one before it, is selected.
+func (ts *Tabs) CloseTab(i int) {
+	if i < 0 || i >= len(ts.tabs) {
+		return
+	}
+	tab := ts.tabs[i]
+	ts.tabRow.RemoveChild(&tab.node.Node)
+	ts.deck.RemoveChild(tab.Content)
+	ts.tabs = append(ts.tabs[:i], ts.tabs[i+1:]...)
+	if i < ts.selected || ts.selected >= len(ts.tabs) {
+		ts.selected--
+	}
+	if ts.selected < 0 {
+		ts.selected = 0
+	}
+	ts.update()
+}
+
+func (ts *Tabs) closeTab(tab *Tab) {
+	ts.CloseTab(ts.index(tab))
+	if ts.OnClose != nil {
+		ts.OnClose(tab)
+	}
+}
+
+// MoveTab moves the tab at index from to index to, keeping the same
+// tab selected.
+func (ts *Tabs) MoveTab(from, to int) {
+	if from < 0 || from >= len(ts.tabs) || to < 0 || to >= len(ts.tabs) || from == to {
+		return
+	}
+	sel := ts.tabs[ts.selected]
+	tab := ts.tabs[from]
+	ts.tabs = append(ts.tabs[:from], ts.tabs[from+1:]...)
+	ts.tabs = append(ts.tabs[:to], append([]*Tab{tab}, ts.tabs[to:]...)...)
+	ts.selected = ts.index(sel)
+
+	// Re-add the nodes in the new order.
+	for _, t := range ts.tabs {
+		ts.tabRow.RemoveChild(&t.node.Node)
+		ts.deck.RemoveChild(t.Content)
+	}
+	for _, t := range ts.tabs {
+		ts.tabRow.AppendChild(&t.node.Node)
+		ts.deck.AppendChild(t.Content)
+	}
+	ts.update()
+}
+
+// ScrollStrip scrolls the tab strip right by dx pixels, or left if dx
+// is negative, as of the next Layout.
+func (ts *Tabs) ScrollStrip(dx int) {
+	ts.scroll += dx
+}
+
+// update shows the selected tab.
+func (ts *Tabs) update() {
+	ts.deck.Show(ts.selected)
+	for i, t := range ts.tabs {
+		t.button.Background = TabBackground
+		if i == ts.selected {
+			t.button.Background = SelectedTabBackground
+		}
+	}
+}
+
+// stripClass is the class of the tab strip, a viewport onto the row of
+// tabs that scrolls horizontally.
+type stripClass struct {
+	widget.ContainerClassEmbed
+
+	tabs *Tabs
+}
+
+func (k *stripClass) Measure(n *widget.Node, t *widget.Theme) {
+	row := &k.tabs.tabRow.Node
+	row.Class.Measure(row, t)
+	n.MeasuredSize = image.Point{0, row.MeasuredSize.Y}
+}
+
+func (k *stripClass) Layout(n *widget.Node, t *widget.Theme) {
+	ts := k.tabs
+	row := &ts.tabRow.Node
+	size := n.Rect.Size()
+	width := row.MeasuredSize.X
+	if width < size.X {
+		width = size.X
+	}
+
+	// Lay out the row at scroll 0, then scroll it.
+	row.Rect = image.Rect(0, 0, width, size.Y)
+	row.Class.Layout(row, t)
+	if ts.scrollTo && len(ts.tabs) > 0 {
+		view := image.Rect(ts.scroll, 0, ts.scroll+size.X, size.Y)
+		ts.scroll += ts.tabRow.ScrollIntoView(&ts.tabs[ts.selected].node.Node, view).X
+	}
+	ts.scrollTo = false
+	if ts.scroll > width-size.X {
+		ts.scroll = width - size.X
+	}
+	if ts.scroll < 0 {
+		ts.scroll = 0
+	}
+	row.Rect = row.Rect.Sub(image.Point{ts.scroll, 0})
+}
+
+func (k *stripClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	r := n.Rect.Add(origin)
+	clip, ok := dst.SubImage(r).(*image.RGBA)
+	if !ok || clip.Rect.Empty() {
+		return
+	}
+	row := &k.tabs.tabRow.Node
+	row.Class.Paint(row, t, clip, r.Min)
+}
@@ -0,0 +1,136 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func content() *widget.Node {
+	return widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+}
+
+func layoutTabs(ts *Tabs, w, h int) {
+	ts.Class.Measure(&ts.Node, nil)
+	ts.Rect = image.Rect(0, 0, w, h)
+	ts.Class.Layout(&ts.Node, nil)
+}
+
+func TestDeck(t *testing.T) {
+	a, b := content(), widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(5)).Node
+	d := NewDeck(a, b)
+	d.Class.Measure(&d.Node, nil)
+	if want := image.Pt(30, 10); d.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", d.MeasuredSize, want)
+	}
+	d.Shown = 1
+	d.Rect = image.Rect(0, 0, 50, 50)
+	d.Class.Layout(&d.Node, nil)
+	if d.ShownNode() != b || b.Rect != image.Rect(0, 0, 50, 50) {
+		t.Errorf("shown %p with Rect %v", d.ShownNode(), b.Rect)
+	}
+	d.Shown = 2
+	if d.ShownNode() != nil {
+		t.Error("out of range Shown shows a child")
+	}
+}
+
+func TestTabs(t *testing.T) {
+	ts := NewTabs()
+	var selected []int
+	ts.OnSelect = func(i int) { selected = append(selected, i) }
+	c0, c1, c2 := content(), content(), content()
+	ts.AddTab("one", c0, false)
+	ts.AddTab("two", c1, true)
+	ts.AddTab("three", c2, true)
+	layoutTabs(ts, 400, 100)
+
+	// "one" is 3 characters of 7px plus 16px of padding.
+	strip := ts.strip.Rect
+	if want := image.Rect(0, 0, 400, 21); strip != want {
+		t.Errorf("strip Rect=%v, want %v", strip, want)
+	}
+	if got := ts.Tab(0).node.Rect; got != image.Rect(0, 0, 37, 21) {
+		t.Errorf("first tab Rect=%v", got)
+	}
+	if ts.Selected() != 0 || c0.Rect != image.Rect(0, 0, 400, 79) {
+		t.Errorf("Selected()=%d, content Rect=%v", ts.Selected(), c0.Rect)
+	}
+
+	// Clicking a tab selects it.
+	b := ts.Tab(2).button
+	b.SetHovered(true)
+	b.Press()
+	b.Release()
+	layoutTabs(ts, 400, 100)
+	if ts.Selected() != 2 || ts.deck.ShownNode() != c2 {
+		t.Errorf("Selected()=%d after clicking the third tab", ts.Selected())
+	}
+
+	ts.MoveTab(2, 0)
+	if ts.Tab(0).Title != "three" || ts.Selected() != 0 || ts.deck.ShownNode() != c2 {
+		t.Errorf("after MoveTab: first tab %q, Selected()=%d", ts.Tab(0).Title, ts.Selected())
+	}
+
+	// The close button closes its tab.
+	var closed *Tab
+	ts.OnClose = func(tab *Tab) { closed = tab }
+	close := ts.Tab(0).node.LastChild
+	cb := close.Class.(*buttonClass).button
+	cb.SetHovered(true)
+	cb.Press()
+	cb.Release()
+	if closed == nil || closed.Title != "three" || ts.Len() != 2 {
+		t.Fatalf("closed %v, %d tabs left", closed, ts.Len())
+	}
+	if ts.Selected() != 0 || ts.deck.ShownNode() != c0 {
+		t.Errorf("after closing the selected tab, Selected()=%d", ts.Selected())
+	}
+	if len(selected) != 1 || selected[0] != 2 {
+		t.Errorf("OnSelect calls %v, want [2]", selected)
+	}
+}
+
+func TestTabsScroll(t *testing.T) {
+	ts := NewTabs()
+	for i := 0; i < 10; i++ {
+		ts.AddTab("tab", content(), false) // 37px wide
+	}
+	layoutTabs(ts, 100, 50)
+	if got := ts.tabRow.Rect; got != image.Rect(0, 0, 370, 21) {
+		t.Errorf("row Rect=%v", got)
+	}
+
+	// Selecting a tab off the end scrolls it just into view.
+	ts.SelectTab(5) // 185 to 222
+	layoutTabs(ts, 100, 50)
+	if got := ts.tabRow.Rect.Min.X; got != -122 {
+		t.Errorf("row scrolled to %d, want -122", got)
+	}
+
+	// Scrolling stops at the ends.
+	ts.ScrollStrip(1000)
+	layoutTabs(ts, 100, 50)
+	if got := ts.tabRow.Rect.Min.X; got != -270 {
+		t.Errorf("row scrolled to %d, want -270", got)
+	}
+	ts.ScrollStrip(-1000)
+	layoutTabs(ts, 100, 50)
+	if got := ts.tabRow.Rect.Min.X; got != 0 {
+		t.Errorf("row scrolled to %d, want 0", got)
+	}
+
+	// Painting is clipped to the strip.
+	dst := image.NewRGBA(image.Rect(0, 0, 200, 50))
+	ts.Class.Paint(&ts.Node, nil, dst, image.Point{})
+	if got := dst.RGBAAt(150, 5); got.A != 0 {
+		t.Errorf("painted outside the strip: %v", got)
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/key"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// DefaultSelectionColor is the background of the selected row of a
+// TreeView.
+var DefaultSelectionColor = color.RGBA{0xc0, 0xd8, 0xf0, 0xff}
+
+// TreeItem is an item of a TreeView, with its subtree.
+type TreeItem struct {
+	Text string
+
+	// Icon, if non-nil, is shown between the expander and the text.
+	Icon image.Image
+
+	Children []*TreeItem
+
+	// Expanded is whether Children are shown.
+	Expanded bool
+}
+
+// TreeView is a widget showing a tree of items, one row per item, with
+// the children of expanded items indented below them.
+//
+// Each row is a flex.Row of the item's indent, its expander ("+" or
+// "-", or blank for a leaf), its icon and its text, which grows to
+// fill the row.
+//
+// After changing the items, call Update to rebuild the rows. The
+// methods that expand, collapse and select items do so themselves.
+type TreeView struct {
+	widget.Node
+
+	Roots []*TreeItem
+
+	// Indent is the indentation of each level, in pixels.
+	Indent int
+
+	// Selected is the selected item, or nil.
+	Selected *TreeItem
+
+	// SelectionColor is the background of the selected row. If nil,
+	// DefaultSelectionColor is used.
+	SelectionColor color.Color
+
+	// OnSelect, if non-nil, is called when the selection changes.
+	OnSelect func(item *TreeItem)
+
+	column *flex.Flex
+	rows   []treeRow
+}
+
+// treeRow is a row of a TreeView: a visible item and the node
+// showing it.
+type treeRow struct {
+	item   *TreeItem
+	parent *TreeItem
+	level  int
+	node   *flex.Flex
+}
+
+// NewTreeView returns a new TreeView widget showing roots.
+func NewTreeView(roots ...*TreeItem) *TreeView {
+	tv := &TreeView{
+		Roots:  roots,
+		Indent: 16,
+		column: flex.NewFlex(),
+	}
+	tv.column.Direction = flex.Column
+	tv.column.AlignItem = flex.AlignItemStretch
+	tv.Node.Class = &treeClass{tree: tv}
+	tv.Node.AppendChild(&tv.column.Node)
+	tv.Update()
+	return tv
+}
+
+// Update rebuilds the rows after a change to the items.
+func (tv *TreeView) Update() {
+	for c := tv.column.FirstChild; c != nil; c = tv.column.FirstChild {
+		tv.column.RemoveChild(c)
+	}
+	tv.rows = tv.rows[:0]
+	var walk func(items []*TreeItem, parent *TreeItem, level int)
+	walk = func(items []*TreeItem, parent *TreeItem, level int) {
+		for _, it := range items {
+			row := tv.newRow(it, level)
+			tv.rows = append(tv.rows, treeRow{item: it, parent: parent, level: level, node: row})
+			tv.column.AppendChild(&row.Node)
+			if it.Expanded {
+				walk(it.Children, it, level+1)
+			}
+		}
+	}
+	walk(tv.Roots, nil, 0)
+	if tv.Selected != nil && tv.row(tv.Selected) < 0 {
+		tv.Selected = nil
+	}
+}
+
+func (tv *TreeView) newRow(it *TreeItem, level int) *flex.Flex {
+	row := flex.NewFlex()
+	row.AlignItem = flex.AlignItemCenter
+	fixed := func(n *widget.Node, px int) {
+		n.LayoutData = flex.LayoutData{Basis: flex.Definite, BasisPx: px, Shrink: new(float64)}
+		row.AppendChild(n)
+	}
+
+	fixed(widget.NewUniform(color.Transparent, unit.Pixels(0), unit.Pixels(0)).Node, level*tv.Indent)
+	expander := " "
+	if len(it.Children) > 0 {
+		expander = "+"
+		if it.Expanded {
+			expander = "-"
+		}
+	}
+	fixed(&NewLabel(expander).Node, 12)
+	if it.Icon != nil {
+		icon := NewImage(it.Icon)
+		icon.Node.LayoutData = flex.LayoutData{
+			Shrink:  new(float64),
+			MaxSize: &image.Point{16, 16},
+		}
+		row.AppendChild(&icon.Node)
+	}
+	label := NewLabel(it.Text)
+	label.Node.LayoutData = flex.LayoutData{Grow: 1}
+	row.AppendChild(&label.Node)
+	return row
+}
+
+// row returns the index of the row showing item, or -1 if it is not
+// visible.
+func (tv *TreeView) row(item *TreeItem) int {
+	for i, r := range tv.rows {
+		if r.item == item {
+			return i
+		}
+	}
+	return -1
+}
+
+// Visible returns the visible items, in the order of their rows.
+func (tv *TreeView) Visible() []*TreeItem {
+	items := make([]*TreeItem, len(tv.rows))
+	for i, r := range tv.rows {
+		items[i] = r.item
+	}
+	return items
+}
+
+// ItemAt returns the item whose row contains p, relative to the
+// TreeView's parent, as of the last Layout. It returns nil if there is
+// none.
+func (tv *TreeView) ItemAt(p image.Point) *TreeItem {
+	p = p.Sub(tv.Rect.Min).Sub(tv.column.Rect.Min)
+	for _, r := range tv.rows {
+		if p.In(r.node.Rect) {
+			return r.item
+		}
+	}
+	return nil
+}
+
+// Select selects item, which may be nil.
+func (tv *TreeView) Select(item *TreeItem) {
+	if item == tv.Selected {
+		return
+	}
+	tv.Selected = item
+	if tv.OnSelect != nil {
+		tv.OnSelect(item)
+	}
+}
+
+// SetExpanded expands or collapses item. A selected descendant of a
+// collapsed item is deselected in favor of the item.
+func (tv *TreeView) SetExpanded(item *TreeItem, expanded bool) {
+	if item.Expanded == expanded {
+		return
+	}
+	item.Expanded = expanded
+	if !expanded && tv.Selected != nil && contains(item.Children, tv.Selected) {
+		tv.Select(item)
+	}
+	tv.Update()
+}
+
+// Toggle expands item if it is collapsed, and collapses it if not.
+func (tv *TreeView) Toggle(item *TreeItem) {
+	tv.SetExpanded(item, !item.Expanded)
+}
+
+func contains(items []*TreeItem, item *TreeItem) bool {
+	for _, it := range items {
+		if it == item || contains(it.Children, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Key handles a key press, and reports whether the tree changed:
+//
+//	Up, Down      select the previous or next row
+//	Home, End     select the first or last row
+//	Right         expand the selected item, or select its first child
+//	Left          collapse the selected item, or select its parent
+//	Enter, Space  toggle the selected item
+//
+// Key releases and other keys are ignored.
+func (tv *TreeView) Key(e key.Event) (changed bool) {
+	if e.Direction == key.DirRelease || len(tv.rows) == 0 {
+		return false
+	}
+	i := tv.row(tv.Selected)
+	sel := tv.Selected
+	switch e.Code {
+	case key.CodeUpArrow:
+		if i < 0 {
+			i = len(tv.rows)
+		}
+		if i > 0 {
+			sel = tv.rows[i-1].item
+		}
+	case key.CodeDownArrow:
+		if i < len(tv.rows)-1 {
+			sel = tv.rows[i+1].item
+		}
+	case key.CodeHome:
+		sel = tv.rows[0].item
+	case key.CodeEnd:
+		sel = tv.rows[len(tv.rows)-1].item
+	case key.CodeRightArrow:
+		if i < 0 || len(sel.Children) == 0 {
+			return false
+		}
+		if !sel.Expanded {
+			tv.SetExpanded(sel, true)
+			return true
+		}
+		sel = sel.Children[0]
+	case key.CodeLeftArrow:
+		if i < 0 {
+			return false
+		}
+		if sel.Expanded && len(sel.Children) > 0 {
+			tv.SetExpanded(sel, false)
+			return true
+		}
+		if p := tv.rows[i].parent; p != nil {
+			sel = p
+		}
+	case key.CodeReturnEnter, key.CodeSpacebar:
+		if i < 0 || len(sel.Children) == 0 {
+			return false
+		}
+		tv.Toggle(sel)
+		return true
+	default:
+		return false
+	}
+	if sel == tv.Selected {
+		return false
+	}
+	tv.Select(sel)
+	return true
+}
+
+type treeClass struct {
+	widget.ContainerClassEmbed
+
+	tree *TreeView
+}
+
+func (k *treeClass) Measure(n *widget.Node, t *widget.Theme) {
+	c := &k.tree.column.Node
+	c.Class.Measure(c, t)
+	n.MeasuredSize = c.MeasuredSize
+}
+
+// MeasureConstrained implements flex.ConstrainedMeasurer, so that the
+// rows of a TreeView wrap their text to its width.
+func (k *treeClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	return flex.MeasureConstrained(&k.tree.column.Node, t, min, max)
+}
+
+func (k *treeClass) Layout(n *widget.Node, t *widget.Theme) {
+	c := &k.tree.column.Node
+	c.Rect = image.Rectangle{Max: n.Rect.Size()}
+	c.Class.Layout(c, t)
+}
+
+func (k *treeClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	tv := k.tree
+	r := n.Rect.Add(origin)
+	c := &tv.column.Node
+	if i := tv.row(tv.Selected); i >= 0 {
+		sc := tv.SelectionColor
+		if sc == nil {
+			sc = DefaultSelectionColor
+		}
+		sr := tv.rows[i].node.Rect.Add(c.Rect.Min).Add(r.Min)
+		draw.Draw(dst, sr.Intersect(r), image.NewUniform(sc), image.Point{}, draw.Over)
+	}
+	c.Class.Paint(c, t, dst, r.Min)
+}
@@ -0,0 +1,129 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flexwidget
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"golang.org/x/mobile/event/key"
+)
+
+func testTree() (*TreeView, map[string]*TreeItem) {
+	items := make(map[string]*TreeItem)
+	item := func(text string, children ...*TreeItem) *TreeItem {
+		it := &TreeItem{Text: text, Children: children}
+		items[text] = it
+		return it
+	}
+	tv := NewTreeView(
+		item("a",
+			item("a1"),
+			item("a2", item("a2x")),
+		),
+		item("b"),
+	)
+	return tv, items
+}
+
+func texts(items []*TreeItem) []string {
+	var s []string
+	for _, it := range items {
+		s = append(s, it.Text)
+	}
+	return s
+}
+
+func TestTreeViewLayout(t *testing.T) {
+	tv, items := testTree()
+	if got, want := texts(tv.Visible()), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Visible() = %q, want %q", got, want)
+	}
+	tv.SetExpanded(items["a"], true)
+	tv.SetExpanded(items["a2"], true)
+	if got, want := texts(tv.Visible()), []string{"a", "a1", "a2", "a2x", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Visible() = %q, want %q", got, want)
+	}
+
+	tv.Class.Measure(&tv.Node, nil)
+	tv.Rect = image.Rect(0, 0, 200, 100)
+	tv.Class.Layout(&tv.Node, nil)
+
+	// Rows are a line of text high, and their labels are indented by
+	// level after a 12px expander.
+	for i, r := range tv.rows {
+		if want := image.Rect(0, 13*i, 200, 13*i+13); r.node.Rect != want {
+			t.Errorf("row %d Rect=%v, want %v", i, r.node.Rect, want)
+		}
+		label := r.node.LastChild
+		if want := 16*r.level + 12; label.Rect.Min.X != want {
+			t.Errorf("row %d label at x=%d, want %d", i, label.Rect.Min.X, want)
+		}
+	}
+	if got := tv.ItemAt(image.Pt(50, 30)); got != items["a2"] {
+		t.Errorf("ItemAt(50, 30) = %v, want a2", got)
+	}
+	if got := tv.ItemAt(image.Pt(50, 90)); got != nil {
+		t.Errorf("ItemAt below the rows = %v, want nil", got)
+	}
+
+	tv.Select(items["a2x"])
+	dst := image.NewRGBA(tv.Rect)
+	tv.Class.Paint(&tv.Node, nil, dst, image.Point{})
+	if got := dst.At(199, 40); got != DefaultSelectionColor {
+		t.Errorf("selected row background %v", got)
+	}
+	if got := dst.At(199, 20); got == DefaultSelectionColor {
+		t.Error("unselected row is highlighted")
+	}
+
+	// Collapsing a2 moves the selection from its child to it.
+	tv.SetExpanded(items["a2"], false)
+	if tv.Selected != items["a2"] {
+		t.Errorf("Selected = %v, want a2", tv.Selected)
+	}
+}
+
+func TestTreeViewKeys(t *testing.T) {
+	tv, items := testTree()
+	var selected []string
+	tv.OnSelect = func(it *TreeItem) { selected = append(selected, it.Text) }
+	press := func(c key.Code) bool { return tv.Key(key.Event{Code: c, Direction: key.DirPress}) }
+
+	for _, step := range []struct {
+		code    key.Code
+		changed bool
+		want    string
+	}{
+		{key.CodeDownArrow, true, "a"},
+		{key.CodeRightArrow, true, "a"}, // expands a
+		{key.CodeRightArrow, true, "a1"},
+		{key.CodeDownArrow, true, "a2"},
+		{key.CodeReturnEnter, true, "a2"}, // expands a2
+		{key.CodeDownArrow, true, "a2x"},
+		{key.CodeRightArrow, false, "a2x"}, // a leaf
+		{key.CodeLeftArrow, true, "a2"},
+		{key.CodeLeftArrow, true, "a2"}, // collapses a2
+		{key.CodeLeftArrow, true, "a"},
+		{key.CodeEnd, true, "b"},
+		{key.CodeDownArrow, false, "b"},
+		{key.CodeHome, true, "a"},
+		{key.CodeA, false, "a"},
+	} {
+		if got := press(step.code); got != step.changed {
+			t.Errorf("%v: changed=%t, want %t", step.code, got, step.changed)
+		}
+		if tv.Selected == nil || tv.Selected.Text != step.want {
+			t.Fatalf("%v: Selected=%v, want %s", step.code, tv.Selected, step.want)
+		}
+	}
+	if items["a2"].Expanded || !items["a"].Expanded {
+		t.Errorf("a.Expanded=%t, a2.Expanded=%t", items["a"].Expanded, items["a2"].Expanded)
+	}
+	if want := []string{"a", "a1", "a2", "a2x", "a2", "a", "b", "a"}; !reflect.DeepEqual(selected, want) {
+		t.Errorf("OnSelect calls %q, want %q", selected, want)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package focus tracks the widget that has keyboard focus.
+//
+// A Manager moves focus with Tab and Shift-Tab through the focusable
+// nodes of a tree in the order they appear on screen, following the
+// VisualOrder of each Flex. Widgets are told when they gain or lose
+// focus, so that they can paint a focus ring.
+//
+// Focus scopes confine traversal to part of the tree, such as a dialog
+// or a popup menu: while focus is in a scope, Tab cycles through its
+// nodes only. Each scope remembers the node last focused in it, and
+// focus returns there when the scope is left or re-entered.
+package focus
+
+import (
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/key"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Focusable is implemented by the Class of a node that can take focus.
+// FocusChanged is called when the node gains or loses focus.
+type Focusable interface {
+	FocusChanged(n *widget.Node, focused bool)
+}
+
+// Manager tracks the focused node of the tree rooted at Root, which is
+// the outermost focus scope.
+type Manager struct {
+	Root *widget.Node
+
+	// OnChange, if non-nil, is called when focus moves from old to
+	// new, either of which may be nil.
+	OnChange func(old, new *widget.Node)
+
+	focused   *widget.Node
+	focusable map[*widget.Node]bool
+	scopes    map[*widget.Node]*widget.Node // scope to the node last focused in it
+}
+
+// Focused returns the focused node, or nil.
+func (m *Manager) Focused() *widget.Node {
+	return m.focused
+}
+
+// SetFocusable sets whether n can take focus, overriding whether its
+// Class implements Focusable.
+func (m *Manager) SetFocusable(n *widget.Node, focusable bool) {
+	if m.focusable == nil {
+		m.focusable = make(map[*widget.Node]bool)
+	}
+	m.focusable[n] = focusable
+}
+
+// IsFocusable reports whether n can take focus.
+func (m *Manager) IsFocusable(n *widget.Node) bool {
+	if f, ok := m.focusable[n]; ok {
+		return f
+	}
+	_, ok := n.Class.(Focusable)
+	return ok
+}
+
+// SetScope makes n a focus scope, or not.
+func (m *Manager) SetScope(n *widget.Node, scope bool) {
+	if !scope {
+		delete(m.scopes, n)
+		return
+	}
+	if m.scopes == nil {
+		m.scopes = make(map[*widget.Node]*widget.Node)
+	}
+	if _, ok := m.scopes[n]; !ok {
+		m.scopes[n] = nil
+	}
+}
+
+// Focus focuses n, or clears the focus if n is nil, and reports
+// whether the focus changed.
+func (m *Manager) Focus(n *widget.Node) (changed bool) {
+	old := m.focused
+	if n == old {
+		return false
+	}
+	m.focused = n
+	if old != nil {
+		if f, ok := old.Class.(Focusable); ok {
+			f.FocusChanged(old, false)
+		}
+	}
+	if n != nil {
+		if s := m.scope(n); s != nil {
+			if m.scopes == nil {
+				m.scopes = make(map[*widget.Node]*widget.Node)
+			}
+			m.scopes[s] = n
+		}
+		if f, ok := n.Class.(Focusable); ok {
+			f.FocusChanged(n, true)
+		}
+	}
+	if m.OnChange != nil {
+		m.OnChange(old, n)
+	}
+	return true
+}
+
+// Next moves focus to the next focusable node of the focused node's
+// scope, wrapping around at its end, and reports whether focus moved.
+// With no focus, it focuses the first node of the tree.
+func (m *Manager) Next() bool {
+	return m.step(1)
+}
+
+// Prev moves focus to the previous focusable node of the focused
+// node's scope, wrapping around at its start, and reports whether
+// focus moved.
+func (m *Manager) Prev() bool {
+	return m.step(-1)
+}
+
+func (m *Manager) step(dir int) bool {
+	s := m.Root
+	if m.focused != nil {
+		if fs := m.scope(m.focused); fs != nil {
+			s = fs
+		}
+	}
+	nodes := m.Order(s)
+	if len(nodes) == 0 {
+		return false
+	}
+	i := -1
+	for j, n := range nodes {
+		if n == m.focused {
+			i = j
+			break
+		}
+	}
+	switch {
+	case i < 0 && dir > 0:
+		i = 0
+	case i < 0:
+		i = len(nodes) - 1
+	default:
+		i = (i + dir + len(nodes)) % len(nodes)
+	}
+	return m.Focus(nodes[i])
+}
+
+// Order returns the focusable nodes of scope in traversal order,
+// omitting those inside nested scopes.
+func (m *Manager) Order(scope *widget.Node) []*widget.Node {
+	var nodes []*widget.Node
+	var walk func(n *widget.Node)
+	walk = func(n *widget.Node) {
+		for _, c := range flex.VisualChildren(n) {
+			if m.IsFocusable(c) {
+				nodes = append(nodes, c)
+			}
+			if _, ok := m.scopes[c]; !ok {
+				walk(c)
+			}
+		}
+	}
+	walk(scope)
+	return nodes
+}
+
+// EnterScope moves focus into scope, to the node last focused in it if
+// it is still there, or else to its first focusable node. It reports
+// whether focus moved.
+func (m *Manager) EnterScope(scope *widget.Node) bool {
+	if last := m.scopes[scope]; last != nil && m.scope(last) == scope {
+		return m.Focus(last)
+	}
+	if nodes := m.Order(scope); len(nodes) > 0 {
+		return m.Focus(nodes[0])
+	}
+	return false
+}
+
+// ExitScope moves focus out of the scope of the focused node, into the
+// scope enclosing it, as when a dialog closes. It reports whether focus
+// moved.
+func (m *Manager) ExitScope() bool {
+	if m.focused == nil {
+		return false
+	}
+	s := m.scope(m.focused)
+	if s == nil || s == m.Root {
+		return false
+	}
+	if m.EnterScope(m.scope(s)) {
+		return true
+	}
+	return m.Focus(nil)
+}
+
+// Key moves focus on a press of Tab or Shift-Tab, and reports whether
+// it moved.
+func (m *Manager) Key(e key.Event) bool {
+	if e.Direction == key.DirRelease || e.Code != key.CodeTab {
+		return false
+	}
+	if e.Modifiers&key.ModShift != 0 {
+		return m.Prev()
+	}
+	return m.Next()
+}
+
+// scope returns the innermost scope enclosing n, other than n itself,
+// or nil if n is not in the tree.
+func (m *Manager) scope(n *widget.Node) *widget.Node {
+	var s *widget.Node
+	for p := n.Parent; p != nil; p = p.Parent {
+		if _, ok := m.scopes[p]; ok && s == nil {
+			s = p
+		}
+		if p == m.Root {
+			if s == nil {
+				s = p
+			}
+			return s
+		}
+	}
+	return nil
+}
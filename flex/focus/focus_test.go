@@ -0,0 +1,120 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package focus
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/key"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// fieldClass is a focusable leaf that records its focus.
+type fieldClass struct {
+	widget.LeafClassEmbed
+	name    string
+	focused bool
+}
+
+func (k *fieldClass) Measure(n *widget.Node, t *widget.Theme) { n.MeasuredSize = image.Pt(10, 10) }
+
+func (k *fieldClass) FocusChanged(n *widget.Node, focused bool) { k.focused = focused }
+
+func field(name string) *widget.Node {
+	return &widget.Node{Class: &fieldClass{name: name}}
+}
+
+func name(n *widget.Node) string {
+	if n == nil {
+		return "<nil>"
+	}
+	return n.Class.(*fieldClass).name
+}
+
+func TestTraversal(t *testing.T) {
+	// A RowReverse shows c, b, a from left to right, followed by a
+	// plain box that cannot take focus and a dialog scope.
+	row := flex.NewFlex()
+	row.Direction = flex.RowReverse
+	a, b, c := field("a"), field("b"), field("c")
+	row.AppendChild(a)
+	row.AppendChild(b)
+	row.AppendChild(c)
+	root := flex.NewFlex()
+	root.Direction = flex.Column
+	root.AppendChild(&row.Node)
+	root.AppendChild(widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node)
+	dialog := flex.NewFlex()
+	d, e := field("d"), field("e")
+	dialog.AppendChild(d)
+	dialog.AppendChild(e)
+	root.AppendChild(&dialog.Node)
+	root.Rect = image.Rect(0, 0, 100, 100)
+	root.Class.Measure(&root.Node, nil)
+	root.Class.Layout(&root.Node, nil)
+
+	var changes []string
+	m := &Manager{
+		Root:     &root.Node,
+		OnChange: func(old, new *widget.Node) { changes = append(changes, name(old)+">"+name(new)) },
+	}
+	m.SetScope(&dialog.Node, true)
+
+	tab := key.Event{Code: key.CodeTab, Direction: key.DirPress}
+	shiftTab := key.Event{Code: key.CodeTab, Modifiers: key.ModShift, Direction: key.DirPress}
+	var got []string
+	for i := 0; i < 4; i++ {
+		m.Key(tab)
+		got = append(got, name(m.Focused()))
+	}
+	m.Key(shiftTab)
+	got = append(got, name(m.Focused()))
+	if want := "c b a c a"; strings.Join(got, " ") != want {
+		t.Errorf("Tab order %q, want %q", strings.Join(got, " "), want)
+	}
+	if !a.Class.(*fieldClass).focused || c.Class.(*fieldClass).focused {
+		t.Error("FocusChanged not called")
+	}
+	if want := "<nil>>c c>b b>a a>c c>a"; strings.Join(changes, " ") != want {
+		t.Errorf("OnChange calls %q, want %q", strings.Join(changes, " "), want)
+	}
+
+	// Within the dialog scope, Tab cycles through its nodes.
+	m.EnterScope(&dialog.Node)
+	got = []string{name(m.Focused())}
+	m.Next()
+	got = append(got, name(m.Focused()))
+	m.Next()
+	got = append(got, name(m.Focused()))
+	if want := "d e d"; strings.Join(got, " ") != want {
+		t.Errorf("scope order %q, want %q", strings.Join(got, " "), want)
+	}
+	m.Next()
+
+	// Leaving the scope returns to a; re-entering returns to e.
+	m.ExitScope()
+	if m.Focused() != a {
+		t.Errorf("after ExitScope, focused %s, want a", name(m.Focused()))
+	}
+	m.EnterScope(&dialog.Node)
+	if m.Focused() != e {
+		t.Errorf("after EnterScope, focused %s, want e", name(m.Focused()))
+	}
+	m.ExitScope()
+
+	// A node made unfocusable is skipped.
+	m.SetFocusable(b, false)
+	m.Focus(c)
+	m.Next()
+	if m.Focused() != a {
+		t.Errorf("focused %s, want b skipped", name(m.Focused()))
+	}
+}
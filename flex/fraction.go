@@ -0,0 +1,37 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import "image"
+
+// resolveFractions returns items with each Fraction turned into a grow
+// factor from a definite basis of zero, and, if any item has a
+// Fraction, the grow factor of the items without one set to zero.
+// Items is copied if any item has a Fraction.
+func (fl *Flex) resolveFractions(size image.Point, items []Item) []Item {
+	mainSize := fl.mainSize(size)
+	found := false
+	for _, it := range items {
+		if it.LayoutData.resolve(mainSize).Fraction > 0 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return items
+	}
+	items = append([]Item(nil), items...)
+	for i := range items {
+		d := items[i].LayoutData.resolve(mainSize)
+		if d.Fraction > 0 {
+			d.Grow = d.Fraction
+			d.Basis, d.BasisPx, d.BasisClamp = Definite, 0, nil
+		} else {
+			d.Grow = 0
+		}
+		items[i].LayoutData = d
+	}
+	return items
+}
@@ -0,0 +1,356 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gesture recognizes taps, long presses, drags and pans in
+// mouse and touch events, and delivers them to the widgets laid out
+// under the pointer.
+//
+// A Recognizer is fed the raw events of a window. It finds the widgets
+// under the pointer with flex.HitTest, and delivers each gesture to
+// the deepest of them that handles it: a node whose Class implements
+// Handler, or one given a handler with Recognizer.Handle. A gesture
+// not handled by a node bubbles up to its parent.
+package gesture
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/touch"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/anim"
+)
+
+// Kind is the kind of a gesture.
+type Kind int8
+
+// Possible values of Kind.
+const (
+	Tap       Kind = iota // pressed and released without moving
+	DoubleTap             // a second Tap soon after the first, in the same place
+	LongPress             // held down without moving
+	DragStart             // pressed and moved; Pos is where it was pressed
+	Drag                  // moved while dragging
+	DragEnd               // released while dragging
+	Pan                   // scrolled, as by a mouse wheel
+)
+
+var kindNames = [...]string{"tap", "double-tap", "long-press", "drag-start", "drag", "drag-end", "pan"}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+// Event is a gesture delivered to a node.
+type Event struct {
+	Kind Kind
+
+	// Node is the node the gesture is delivered to.
+	Node *widget.Node
+
+	// Pos is the position of the pointer, in the coordinates of the
+	// root's Rect, and Local is the same position relative to
+	// Node.Rect.Min.
+	Pos, Local image.Point
+
+	// Delta is the movement since the previous Drag, or the distance
+	// scrolled by a Pan. Total is the movement since DragStart.
+	Delta, Total image.Point
+}
+
+// Handler is implemented by the Class of a node that handles gestures.
+// Gesture reports whether the node handled e. If not, e is delivered
+// to the node's parent.
+//
+// A node that handles DragStart receives the Drag and DragEnd events
+// of that drag, wherever the pointer moves.
+type Handler interface {
+	Gesture(n *widget.Node, e Event) (handled bool)
+}
+
+// Defaults for the thresholds of a Recognizer.
+const (
+	DefaultSlop           = 8
+	DefaultDoubleTapDelay = 300 * time.Millisecond
+	DefaultLongPressDelay = 500 * time.Millisecond
+	DefaultWheelStep      = 20
+)
+
+// Recognizer recognizes gestures in the events of a window showing
+// the tree rooted at Root.
+//
+// Only one pointer is tracked at a time: the mouse, or the first touch.
+// Other touches are ignored until it is released.
+type Recognizer struct {
+	Root *widget.Node
+
+	// Slop is the distance, in pixels, that a pointer may move before
+	// a press becomes a drag. If zero, DefaultSlop is used.
+	Slop int
+
+	// DoubleTapDelay and LongPressDelay are the longest time between
+	// the taps of a DoubleTap and the shortest press that is a
+	// LongPress. If zero, the defaults are used.
+	DoubleTapDelay time.Duration
+	LongPressDelay time.Duration
+
+	// WheelStep is the distance, in pixels, of a Pan for each step of
+	// a mouse wheel. If zero, DefaultWheelStep is used.
+	WheelStep int
+
+	// Ticker, if non-nil, is given Step while a press may become a
+	// LongPress. Without it, an event loop calls Step itself.
+	Ticker *anim.Ticker
+
+	handlers map[*widget.Node]func(Event) bool
+
+	// The pointer being tracked.
+	down     bool
+	touch    touch.Sequence
+	isTouch  bool
+	start    image.Point
+	pos      image.Point
+	pressed  time.Time
+	path     []*widget.Node
+	long     bool // a LongPress was delivered
+	dragging bool
+	target   *widget.Node // the node handling the drag
+
+	lastTap     time.Time
+	lastTapPos  image.Point
+	lastTapNode *widget.Node
+}
+
+// Handle sets f to handle the gestures of n, in place of n's Class.
+// A nil f removes it.
+func (r *Recognizer) Handle(n *widget.Node, f func(e Event) (handled bool)) {
+	if f == nil {
+		delete(r.handlers, n)
+		return
+	}
+	if r.handlers == nil {
+		r.handlers = make(map[*widget.Node]func(Event) bool)
+	}
+	r.handlers[n] = f
+}
+
+// Event recognizes gestures in e, a mouse.Event or touch.Event that
+// happened at time now, and delivers them. Other events are ignored.
+// It reports whether any gesture was handled.
+func (r *Recognizer) Event(e interface{}, now time.Time) bool {
+	switch e := e.(type) {
+	case mouse.Event:
+		p := image.Pt(int(e.X), int(e.Y))
+		if e.Button.IsWheel() {
+			if e.Direction != mouse.DirStep && e.Direction != mouse.DirPress {
+				return false
+			}
+			return r.pan(p, e.Button)
+		}
+		switch e.Direction {
+		case mouse.DirPress:
+			if e.Button != mouse.ButtonLeft || r.down {
+				return false
+			}
+			r.isTouch = false
+			return r.press(p, now)
+		case mouse.DirRelease:
+			if e.Button != mouse.ButtonLeft || r.isTouch {
+				return false
+			}
+			return r.release(p, now)
+		case mouse.DirNone:
+			if r.isTouch {
+				return false
+			}
+			return r.move(p)
+		}
+	case touch.Event:
+		p := image.Pt(int(e.X), int(e.Y))
+		switch e.Type {
+		case touch.TypeBegin:
+			if r.down {
+				return false
+			}
+			r.isTouch, r.touch = true, e.Sequence
+			return r.press(p, now)
+		case touch.TypeMove:
+			if !r.isTouch || e.Sequence != r.touch {
+				return false
+			}
+			return r.move(p)
+		case touch.TypeEnd:
+			if !r.isTouch || e.Sequence != r.touch {
+				return false
+			}
+			return r.release(p, now)
+		}
+	}
+	return false
+}
+
+// Step delivers a LongPress if the pointer has been held down without
+// moving until time now, and reports whether a press is still waiting
+// to become one.
+func (r *Recognizer) Step(now time.Time) bool {
+	if !r.down || r.dragging || r.long {
+		return false
+	}
+	if now.Sub(r.pressed) < r.longPressDelay() {
+		return true
+	}
+	r.long = true
+	r.deliver(r.path, Event{Kind: LongPress, Pos: r.pos})
+	return false
+}
+
+func (r *Recognizer) press(p image.Point, now time.Time) bool {
+	r.down, r.long, r.dragging, r.target = true, false, false, nil
+	r.start, r.pos, r.pressed = p, p, now
+	r.path = flex.HitTest(r.Root, p)
+	if r.Ticker != nil {
+		r.Ticker.Add(r.Step)
+	}
+	return false
+}
+
+func (r *Recognizer) move(p image.Point) bool {
+	if !r.down {
+		return false
+	}
+	delta := p.Sub(r.pos)
+	if delta == (image.Point{}) {
+		return false
+	}
+	r.pos = p
+	if !r.dragging {
+		if r.long || dist2(p, r.start) <= r.slop()*r.slop() {
+			return false
+		}
+		r.dragging = true
+		r.target = r.deliver(r.path, Event{Kind: DragStart, Pos: r.start})
+		delta = p.Sub(r.start)
+	}
+	if r.target == nil {
+		return false
+	}
+	return r.send(r.target, Event{Kind: Drag, Pos: p, Delta: delta, Total: p.Sub(r.start)})
+}
+
+func (r *Recognizer) release(p image.Point, now time.Time) bool {
+	if !r.down {
+		return false
+	}
+	r.move(p)
+	r.down = false
+	path := r.path
+	r.path = nil
+	switch {
+	case r.dragging:
+		if r.target == nil {
+			return false
+		}
+		return r.send(r.target, Event{Kind: DragEnd, Pos: p, Total: p.Sub(r.start)})
+	case r.long:
+		return false
+	}
+
+	handled := r.deliver(path, Event{Kind: Tap, Pos: p}) != nil
+	var n *widget.Node
+	if len(path) > 0 {
+		n = path[len(path)-1]
+	}
+	if !r.lastTap.IsZero() && now.Sub(r.lastTap) <= r.doubleTapDelay() &&
+		n == r.lastTapNode && dist2(p, r.lastTapPos) <= r.slop()*r.slop() {
+		r.lastTap = time.Time{}
+		return r.deliver(path, Event{Kind: DoubleTap, Pos: p}) != nil || handled
+	}
+	r.lastTap, r.lastTapPos, r.lastTapNode = now, p, n
+	return handled
+}
+
+func (r *Recognizer) pan(p image.Point, b mouse.Button) bool {
+	step := r.WheelStep
+	if step == 0 {
+		step = DefaultWheelStep
+	}
+	var d image.Point
+	switch b {
+	case mouse.ButtonWheelUp:
+		d.Y = -step
+	case mouse.ButtonWheelDown:
+		d.Y = step
+	case mouse.ButtonWheelLeft:
+		d.X = -step
+	case mouse.ButtonWheelRight:
+		d.X = step
+	}
+	return r.deliver(flex.HitTest(r.Root, p), Event{Kind: Pan, Pos: p, Delta: d, Total: d}) != nil
+}
+
+// deliver sends e to the nodes of path from the deepest up, until one
+// handles it, and returns that node.
+func (r *Recognizer) deliver(path []*widget.Node, e Event) *widget.Node {
+	for i := len(path) - 1; i >= 0; i-- {
+		if r.send(path[i], e) {
+			return path[i]
+		}
+	}
+	return nil
+}
+
+// send sends e to n, and reports whether n handled it.
+func (r *Recognizer) send(n *widget.Node, e Event) bool {
+	e.Node = n
+	e.Local = e.Pos.Sub(r.origin(n))
+	if f := r.handlers[n]; f != nil {
+		return f(e)
+	}
+	if h, ok := n.Class.(Handler); ok {
+		return h.Gesture(n, e)
+	}
+	return false
+}
+
+// origin returns n's Rect.Min in the coordinates of the root's Rect.
+func (r *Recognizer) origin(n *widget.Node) image.Point {
+	if n == r.Root {
+		return n.Rect.Min
+	}
+	rect, _ := flex.RectIn(n, r.Root)
+	return rect.Min.Add(r.Root.Rect.Min)
+}
+
+func (r *Recognizer) slop() int {
+	if r.Slop == 0 {
+		return DefaultSlop
+	}
+	return r.Slop
+}
+
+func (r *Recognizer) doubleTapDelay() time.Duration {
+	if r.DoubleTapDelay == 0 {
+		return DefaultDoubleTapDelay
+	}
+	return r.DoubleTapDelay
+}
+
+func (r *Recognizer) longPressDelay() time.Duration {
+	if r.LongPressDelay == 0 {
+		return DefaultLongPressDelay
+	}
+	return r.LongPressDelay
+}
+
+func dist2(a, b image.Point) int {
+	d := a.Sub(b)
+	return d.X*d.X + d.Y*d.Y
+}
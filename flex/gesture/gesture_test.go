@@ -0,0 +1,153 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gesture
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/touch"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// fixture is a Row at (10, 10) holding two 40x40 boxes, a and b.
+type fixture struct {
+	r      *Recognizer
+	root   *flex.Flex
+	a, b   *widget.Node
+	events []string
+}
+
+func newFixture() *fixture {
+	f := &fixture{root: flex.NewFlex()}
+	f.a = widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(40)).Node
+	f.b = widget.NewUniform(color.White, unit.Pixels(40), unit.Pixels(40)).Node
+	f.root.AppendChild(f.a)
+	f.root.AppendChild(f.b)
+	f.root.Rect = image.Rect(10, 10, 110, 60)
+	f.root.Class.Measure(&f.root.Node, nil)
+	f.root.Class.Layout(&f.root.Node, nil)
+	f.r = &Recognizer{Root: &f.root.Node}
+	return f
+}
+
+// record makes n handle gestures of the given kinds, recording them.
+func (f *fixture) record(n *widget.Node, name string, kinds ...Kind) {
+	f.r.Handle(n, func(e Event) bool {
+		for _, k := range kinds {
+			if e.Kind == k {
+				f.events = append(f.events, name+" "+e.Kind.String()+" "+e.Local.String()+" "+e.Delta.String())
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func press(x, y float32) mouse.Event {
+	return mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}
+}
+
+func release(x, y float32) mouse.Event {
+	return mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}
+}
+
+func move(x, y float32) mouse.Event {
+	return mouse.Event{X: x, Y: y, Direction: mouse.DirNone}
+}
+
+func (f *fixture) check(t *testing.T, what string, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(f.events, want) {
+		t.Errorf("%s: got %q, want %q", what, f.events, want)
+	}
+	f.events = nil
+}
+
+func TestTap(t *testing.T) {
+	f := newFixture()
+	f.record(f.a, "a", Tap, DoubleTap)
+	f.record(&f.root.Node, "root", Tap)
+	t0 := time.Unix(1000, 0)
+
+	f.r.Event(press(15, 15), t0)
+	if !f.r.Event(release(16, 15), t0.Add(50*time.Millisecond)) {
+		t.Error("tap not handled")
+	}
+	f.check(t, "tap", "a tap (6,5) (0,0)")
+
+	f.r.Event(press(16, 16), t0.Add(200*time.Millisecond))
+	f.r.Event(release(16, 16), t0.Add(250*time.Millisecond))
+	f.check(t, "double tap", "a tap (6,6) (0,0)", "a double-tap (6,6) (0,0)")
+
+	// b does not handle taps: they bubble to root.
+	f.r.Event(press(65, 15), t0.Add(2*time.Second))
+	f.r.Event(release(65, 15), t0.Add(2*time.Second))
+	f.check(t, "bubbled tap", "root tap (55,5) (0,0)")
+
+	// Too slow for a double tap.
+	f.r.Event(press(65, 15), t0.Add(3*time.Second))
+	f.r.Event(release(65, 15), t0.Add(3*time.Second))
+	f.check(t, "slow second tap", "root tap (55,5) (0,0)")
+}
+
+func TestLongPress(t *testing.T) {
+	f := newFixture()
+	f.record(f.a, "a", Tap, LongPress)
+	t0 := time.Unix(1000, 0)
+
+	f.r.Event(press(15, 15), t0)
+	if !f.r.Step(t0.Add(100 * time.Millisecond)) {
+		t.Error("Step not waiting for a long press")
+	}
+	if f.r.Step(t0.Add(time.Second)) {
+		t.Error("Step still waiting after the long press")
+	}
+	f.r.Event(release(15, 15), t0.Add(2*time.Second))
+	f.check(t, "long press", "a long-press (5,5) (0,0)")
+}
+
+func TestDrag(t *testing.T) {
+	f := newFixture()
+	f.record(f.a, "a", DragStart, Drag, DragEnd)
+	t0 := time.Unix(1000, 0)
+
+	f.r.Event(press(15, 15), t0)
+	f.r.Event(move(18, 15), t0) // within the slop
+	f.r.Event(move(30, 15), t0)
+	f.r.Event(move(80, 20), t0) // over b: a keeps the drag
+	f.r.Event(release(80, 20), t0)
+	f.check(t, "drag",
+		"a drag-start (5,5) (0,0)",
+		"a drag (20,5) (15,0)",
+		"a drag (70,10) (50,5)",
+		"a drag-end (70,10) (0,0)",
+	)
+
+	// Touches drag the same way; a second touch is ignored.
+	f.r.Event(touch.Event{X: 15, Y: 15, Sequence: 1, Type: touch.TypeBegin}, t0)
+	f.r.Event(touch.Event{X: 65, Y: 15, Sequence: 2, Type: touch.TypeBegin}, t0)
+	f.r.Event(touch.Event{X: 15, Y: 35, Sequence: 1, Type: touch.TypeMove}, t0)
+	f.r.Event(touch.Event{X: 15, Y: 35, Sequence: 1, Type: touch.TypeEnd}, t0)
+	f.check(t, "touch drag",
+		"a drag-start (5,5) (0,0)",
+		"a drag (5,25) (0,20)",
+		"a drag-end (5,25) (0,0)",
+	)
+}
+
+func TestPan(t *testing.T) {
+	f := newFixture()
+	f.record(&f.root.Node, "root", Pan)
+	f.r.Event(mouse.Event{X: 65, Y: 15, Button: mouse.ButtonWheelDown, Direction: mouse.DirStep}, time.Time{})
+	f.check(t, "pan", "root pan (55,5) (0,20)")
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gioflex lays out Gio widgets using the flex layout algorithm.
+//
+// The container properties come from a *flex.Flex and the item
+// properties from flex.LayoutData, so the same layout definitions,
+// including those built by flex.ParseStyle and flex.ParseItemStyle,
+// can drive both shiny and Gio frontends.
+package gioflex
+
+import (
+	"image"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Child is a Gio widget laid out as a flex item.
+type Child struct {
+	LayoutData flex.LayoutData
+	Widget     layout.Widget
+}
+
+// Rigid returns a Child that is neither grown nor shrunk.
+func Rigid(w layout.Widget) Child {
+	shrink := 0.0
+	return Child{LayoutData: flex.LayoutData{Shrink: &shrink}, Widget: w}
+}
+
+// Flexed returns a Child that grows by the given factor.
+func Flexed(grow float64, w layout.Widget) Child {
+	return Child{LayoutData: flex.LayoutData{Grow: grow}, Widget: w}
+}
+
+// Layout lays out children in a container filling gtx.Constraints.Max,
+// using the container properties of fl.
+//
+// Each child widget is called at least twice. The first call has loose
+// constraints and its operations are discarded; the resulting size
+// plays the role of a shiny widget's MeasuredSize. Once its main size
+// is resolved, the widget is called again with that main size fixed to
+// find its cross size, so that wrapping text gets the right height;
+// those operations are discarded too. The last call has exact
+// constraints of the size assigned by the flex algorithm and is offset
+// to the child's position.
+func Layout(gtx layout.Context, fl *flex.Flex, children ...Child) layout.Dimensions {
+	dims, _ := LayoutErr(gtx, fl, children...)
+	return dims
+}
+
+// LayoutErr is Layout, additionally returning the error of
+// fl.SolveErr, a *flex.LoopError, if the flex loop could not resolve
+// the sizes of some children. The children are laid out all the same.
+func LayoutErr(gtx layout.Context, fl *flex.Flex, children ...Child) (layout.Dimensions, error) {
+	items := make([]flex.Item, len(children))
+	for i, c := range children {
+		mgtx := gtx
+		mgtx.Constraints.Min = image.Point{}
+		macro := op.Record(gtx.Ops)
+		dims := c.Widget(mgtx)
+		macro.Stop()
+		items[i] = flex.Item{
+			MeasuredSize: dims.Size,
+			LayoutData:   c.LayoutData,
+			CrossSizeFor: crossSizeFor(gtx, fl, c.Widget),
+		}
+	}
+
+	size := gtx.Constraints.Max
+	rects, err := fl.SolveErr(size, items)
+	for i, c := range children {
+		cgtx := gtx
+		cgtx.Constraints = layout.Exact(rects[i].Size())
+		trans := op.Offset(rects[i].Min).Push(gtx.Ops)
+		c.Widget(cgtx)
+		trans.Pop()
+	}
+	return layout.Dimensions{Size: size}, err
+}
+
+// crossSizeFor returns a flex.Item.CrossSizeFor that calls w with its
+// main size fixed and discards its operations.
+func crossSizeFor(gtx layout.Context, fl *flex.Flex, w layout.Widget) func(int) int {
+	return func(mainSize int) int {
+		mgtx := gtx
+		switch fl.Direction {
+		case flex.Row, flex.RowReverse:
+			mgtx.Constraints.Min = image.Pt(mainSize, 0)
+			mgtx.Constraints.Max.X = mainSize
+		default:
+			mgtx.Constraints.Min = image.Pt(0, mainSize)
+			mgtx.Constraints.Max.Y = mainSize
+		}
+		macro := op.Record(gtx.Ops)
+		dims := w(mgtx)
+		macro.Stop()
+		switch fl.Direction {
+		case flex.Row, flex.RowReverse:
+			return dims.Size.Y
+		default:
+			return dims.Size.X
+		}
+	}
+}
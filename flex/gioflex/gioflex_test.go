@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gioflex
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// box is a widget with a natural size that records the constraints of
+// its final layout.
+type box struct {
+	natural image.Point
+	got     layout.Constraints
+}
+
+func (b *box) layout(gtx layout.Context) layout.Dimensions {
+	b.got = gtx.Constraints
+	return layout.Dimensions{Size: gtx.Constraints.Constrain(b.natural)}
+}
+
+func TestLayout(t *testing.T) {
+	fl, err := flex.ParseStyle("justify-content: space-between")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &box{natural: image.Pt(50, 20)}
+	b := &box{natural: image.Pt(100, 30)}
+	c := &box{natural: image.Pt(10, 10)}
+
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Pt(300, 40)),
+	}
+	dims := Layout(gtx, fl,
+		Rigid(a.layout),
+		Flexed(1, b.layout),
+		Child{Widget: c.layout},
+	)
+	if want := image.Pt(300, 40); dims.Size != want {
+		t.Errorf("dims.Size=%v, want %v", dims.Size, want)
+	}
+	for _, test := range []struct {
+		name string
+		b    *box
+		want image.Point
+	}{
+		{"a", a, image.Pt(50, 20)},
+		{"b", b, image.Pt(240, 30)},
+		{"c", c, image.Pt(10, 10)},
+	} {
+		if want := layout.Exact(test.want); test.b.got != want {
+			t.Errorf("%s constraints=%v, want %v", test.name, test.b.got, want)
+		}
+	}
+}
+
+// text is a widget with a constant area, like wrapping text.
+func text(area int, got *image.Point) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		w := gtx.Constraints.Max.X
+		sz := gtx.Constraints.Constrain(image.Pt(w, (area+w-1)/w))
+		*got = sz
+		return layout.Dimensions{Size: sz}
+	}
+}
+
+func TestHeightForWidth(t *testing.T) {
+	fl := flex.NewFlex()
+	fl.Direction = flex.Column
+	var got image.Point
+	below := &box{natural: image.Pt(10, 10)}
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Pt(50, 300)),
+	}
+	Layout(gtx, fl, Child{Widget: text(2000, &got)}, Rigid(below.layout))
+	if want := image.Pt(50, 40); got != want {
+		t.Errorf("text size=%v, want %v", got, want)
+	}
+	if want := layout.Exact(image.Pt(10, 10)); below.got != want {
+		t.Errorf("below constraints=%v, want %v", below.got, want)
+	}
+}
+
+func TestLayoutErr(t *testing.T) {
+	fl := flex.NewFlex()
+	a := &box{natural: image.Pt(50, 20)}
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Pt(300, 40)),
+	}
+	_, err := LayoutErr(gtx, fl, Flexed(math.Inf(1), a.layout))
+	if _, ok := err.(*flex.LoopError); !ok {
+		t.Errorf("LayoutErr error = %v, want a *flex.LoopError", err)
+	}
+	if a.got.Max.X != 50 {
+		t.Errorf("constraints=%v, want a width of 50", a.got)
+	}
+}
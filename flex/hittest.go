@@ -0,0 +1,91 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// PointerEvents says whether a flex item can be the target of pointer
+// events. It is the 'pointer-events' property.
+type PointerEvents int8
+
+// Possible values of PointerEvents.
+const (
+	PointerAuto PointerEvents = iota
+	PointerNone
+)
+
+var pointerEventsNames = [...]string{
+	PointerAuto: "auto",
+	PointerNone: "none",
+}
+
+func (p PointerEvents) String() string {
+	return enumName(pointerEventsNames[:], "PointerEvents", int(p))
+}
+
+func (p PointerEvents) MarshalText() ([]byte, error) {
+	return marshalEnum(pointerEventsNames[:], "PointerEvents", int(p))
+}
+
+func (p *PointerEvents) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(pointerEventsNames[:], "PointerEvents", text)
+	*p = PointerEvents(i)
+	return err
+}
+
+// ChildAt returns the topmost child of n whose Rect contains p, or nil.
+// p is relative to n's Rect.Min, like the Rects of its children.
+//
+// Children are visited in the reverse of PaintOrder, and children of a
+// Flex whose painting is clipped are only hit within its ClipRect.
+// Children whose LayoutData has PointerNone are skipped.
+func ChildAt(n *widget.Node, p image.Point) *widget.Node {
+	if clip, ok := ClipRect(n); ok && !p.In(clip) {
+		return nil
+	}
+	fl := FlexOf(n)
+	children := PaintOrder(n)
+	for i := len(children) - 1; i >= 0; i-- {
+		c := children[i]
+		if !p.In(c.Rect) || pointerEvents(fl, c) == PointerNone {
+			continue
+		}
+		return c
+	}
+	return nil
+}
+
+// pointerEvents returns the PointerEvents of c, a child of fl, which is
+// nil if c's parent is not a Flex.
+func pointerEvents(fl *Flex, c *widget.Node) PointerEvents {
+	if fl != nil {
+		return fl.resolvedData(c).PointerEvents
+	}
+	d, _ := c.LayoutData.(LayoutData)
+	return d.PointerEvents
+}
+
+// HitTest returns the path from n to the deepest node whose Rect
+// contains p, starting with n, or nil if p is not in n's Rect. p is in
+// the coordinates of n's Rect.
+func HitTest(n *widget.Node, p image.Point) []*widget.Node {
+	if !p.In(n.Rect) {
+		return nil
+	}
+	path := []*widget.Node{n}
+	for {
+		p = p.Sub(n.Rect.Min)
+		c := ChildAt(n, p)
+		if c == nil {
+			return path
+		}
+		path = append(path, c)
+		n = c
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestHitTest(t *testing.T) {
+	leaf := func(w, h int) *widget.Node {
+		return widget.NewUniform(color.Black, unit.Pixels(float64(w)), unit.Pixels(float64(h))).Node
+	}
+	root := NewFlex()
+	inner := NewFlex()
+	a, b, c := leaf(20, 20), leaf(20, 20), leaf(30, 10)
+	inner.AppendChild(a)
+	inner.AppendChild(b)
+	root.AppendChild(&inner.Node)
+	root.AppendChild(c)
+	root.Rect = image.Rect(100, 100, 200, 200)
+	root.Class.Measure(&root.Node, nil)
+	root.Class.Layout(&root.Node, nil)
+
+	tests := []struct {
+		p    image.Point
+		want []*widget.Node
+	}{
+		{image.Pt(99, 100), nil},
+		{image.Pt(105, 105), []*widget.Node{&root.Node, &inner.Node, a}},
+		{image.Pt(125, 105), []*widget.Node{&root.Node, &inner.Node, b}},
+		{image.Pt(145, 105), []*widget.Node{&root.Node, c}},
+		{image.Pt(145, 115), []*widget.Node{&root.Node}},
+	}
+	for _, test := range tests {
+		got := HitTest(&root.Node, test.p)
+		if !equalNodes(got, test.want) {
+			t.Errorf("HitTest(%v) = %v, want %v", test.p, got, test.want)
+		}
+	}
+
+	// A child raised by ZIndex is hit over an overlapping sibling.
+	a.LayoutData = LayoutData{ZIndex: 1}
+	b.Rect = b.Rect.Sub(image.Pt(10, 0))
+	if got := ChildAt(&inner.Node, image.Pt(15, 5)); got != a {
+		t.Errorf("ChildAt over overlapping children = %p, want a", got)
+	}
+	a.LayoutData = nil
+	if got := ChildAt(&inner.Node, image.Pt(15, 5)); got != b {
+		t.Errorf("ChildAt with equal ZIndex = %p, want the later sibling b", got)
+	}
+}
+
+func TestPointerEventsNone(t *testing.T) {
+	root := NewFlex()
+	root.AlignItem = AlignItemStretch
+	below := widget.NewUniform(color.Black, unit.Pixels(50), unit.Pixels(50)).Node
+	overlay := NewFlex()
+	overlay.AppendChild(widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node)
+	overlay.LayoutData = LayoutData{ZIndex: 1, Offset: image.Pt(-50, 0), PointerEvents: PointerNone}
+	root.AppendChild(below)
+	root.AppendChild(&overlay.Node)
+	root.Rect = image.Rect(0, 0, 60, 50)
+	root.Class.Measure(&root.Node, nil)
+	root.Class.Layout(&root.Node, nil)
+	if !overlay.Rect.Overlaps(below.Rect) {
+		t.Fatalf("overlay %v does not cover %v", overlay.Rect, below.Rect)
+	}
+
+	// The overlay and its child are skipped for the node below.
+	p := image.Pt(5, 5)
+	if got := HitTest(&root.Node, p); !equalNodes(got, []*widget.Node{&root.Node, below}) {
+		t.Errorf("HitTest(%v) = %v, want [root below]", p, got)
+	}
+
+	overlay.LayoutData = LayoutData{ZIndex: 1}
+	if got := ChildAt(&root.Node, p); got != &overlay.Node {
+		t.Errorf("ChildAt with PointerAuto = %p, want the overlay", got)
+	}
+
+	// DefaultLayoutData applies to children without a LayoutData.
+	overlay.LayoutData = nil
+	below.LayoutData = nil
+	root.DefaultLayoutData = &LayoutData{PointerEvents: PointerNone}
+	if got := ChildAt(&root.Node, p); got != nil {
+		t.Errorf("ChildAt with a PointerNone default = %p, want nil", got)
+	}
+
+	// A Breakpoint can set PointerNone.
+	root.DefaultLayoutData = &LayoutData{
+		Breakpoints: &[]Breakpoint{{MinMainSize: 60, LayoutData: LayoutData{PointerEvents: PointerNone}}},
+	}
+	if got := ChildAt(&root.Node, p); got != nil {
+		t.Errorf("ChildAt with a PointerNone Breakpoint = %p, want nil", got)
+	}
+}
+
+func equalNodes(a, b []*widget.Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
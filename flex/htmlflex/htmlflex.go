@@ -0,0 +1,167 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package htmlflex converts a restricted subset of HTML into flex
+// widget trees, so that simple mockups written as HTML can run as shiny
+// UIs.
+//
+// The body of the document must contain a single root element. The
+// supported elements are:
+//
+//	<div>   a flex container if it has child elements, otherwise a
+//	        uniform tile
+//	<img>   a placeholder tile, sized by its width and height
+//	        attributes or style
+//	<span>  a placeholder tile, sized by its style
+//
+// There is no image or text widget yet, so the content of img and span
+// elements is not shown.
+//
+// The style attribute of each element is interpreted as described by
+// flex.Doc, and the id attribute becomes the Doc ID. Declarations of
+// the display property are ignored, as every div with children is a
+// flex container.
+package htmlflex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+// Load parses an HTML document from r and builds its widget tree.
+func Load(r io.Reader) (*flex.Tree, error) {
+	doc, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return flex.Build(doc)
+}
+
+// Parse parses an HTML document from r into a flex.Doc.
+func Parse(r io.Reader) (*flex.Doc, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("htmlflex: %v", err)
+	}
+	body := find(root, atom.Body)
+	if body == nil {
+		return nil, fmt.Errorf("htmlflex: no body")
+	}
+	elems, err := children(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) != 1 {
+		return nil, fmt.Errorf("htmlflex: body has %d elements, want 1", len(elems))
+	}
+	return convert(elems[0])
+}
+
+// find returns the first element of type a in a depth-first walk of n.
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := find(c, a); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// children returns the child elements of n, skipping comments and
+// whitespace.
+func children(n *html.Node) ([]*html.Node, error) {
+	var elems []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.ElementNode:
+			elems = append(elems, c)
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" && n.DataAtom != atom.Span {
+				return nil, fmt.Errorf("htmlflex: unexpected text %q in <%s>", strings.TrimSpace(c.Data), n.Data)
+			}
+		}
+	}
+	return elems, nil
+}
+
+func convert(n *html.Node) (*flex.Doc, error) {
+	doc := &flex.Doc{
+		ID:    attr(n, "id"),
+		Style: stripDisplay(attr(n, "style")),
+	}
+	elems, err := children(n)
+	if err != nil {
+		return nil, err
+	}
+	switch n.DataAtom {
+	case atom.Div:
+		if len(elems) == 0 {
+			doc.Type = "uniform"
+			return doc, nil
+		}
+		doc.Type = "flex"
+		for _, e := range elems {
+			c, err := convert(e)
+			if err != nil {
+				return nil, err
+			}
+			doc.Children = append(doc.Children, c)
+		}
+		return doc, nil
+	case atom.Img, atom.Span:
+		if len(elems) > 0 {
+			return nil, fmt.Errorf("htmlflex: <%s> has child elements", n.Data)
+		}
+		doc.Type = "uniform"
+		var size []string
+		for _, prop := range []string{"width", "height"} {
+			if v := attr(n, prop); v != "" {
+				size = append(size, prop+": "+v+"px")
+			}
+		}
+		if len(size) > 0 {
+			// Put attributes first, so the style attribute overrides them.
+			if doc.Style != "" {
+				size = append(size, doc.Style)
+			}
+			doc.Style = strings.Join(size, "; ")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("htmlflex: unsupported element <%s>", n.Data)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// stripDisplay removes display declarations from a style attribute.
+func stripDisplay(style string) string {
+	var decls []string
+	for _, decl := range strings.Split(style, ";") {
+		prop := decl
+		if i := strings.IndexByte(decl, ':'); i >= 0 {
+			prop = decl[:i]
+		}
+		if strings.EqualFold(strings.TrimSpace(prop), "display") || strings.TrimSpace(decl) == "" {
+			continue
+		}
+		decls = append(decls, strings.TrimSpace(decl))
+	}
+	return strings.Join(decls, "; ")
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlflex
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+const mockup = `<!DOCTYPE html>
+<html>
+<body>
+<div id="root" style="display: flex; flex-direction: column">
+	<!-- header -->
+	<div id="header" style="height: 40px; background-color: #336"></div>
+	<div id="main" style="display: flex; flex: 1; align-self: stretch">
+		<img id="avatar" src="me.png" width="64" height="64">
+		<span id="name" style="width: 100px; height: 20px; flex-grow: 1">Gopher</span>
+	</div>
+</div>
+</body>
+</html>
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(mockup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &flex.Doc{
+		Type:  "flex",
+		ID:    "root",
+		Style: "flex-direction: column",
+		Children: []*flex.Doc{
+			{Type: "uniform", ID: "header", Style: "height: 40px; background-color: #336"},
+			{
+				Type:  "flex",
+				ID:    "main",
+				Style: "flex: 1; align-self: stretch",
+				Children: []*flex.Doc{
+					{Type: "uniform", ID: "avatar", Style: "width: 64px; height: 64px"},
+					{Type: "uniform", ID: "name", Style: "width: 100px; height: 20px; flex-grow: 1"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tree, err := Load(strings.NewReader(mockup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root
+	root.Class.Measure(root, nil)
+	root.Rect = image.Rect(0, 0, 200, 100)
+	root.Class.Layout(root, nil)
+	if got, want := tree.ByID["name"].Rect, image.Rect(64, 0, 200, 20); got != want {
+		t.Errorf("name Rect=%v, want %v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{
+		`<p>hello</p>`,
+		`<div></div><div></div>`,
+		`<div>text</div>`,
+		`<span><div></div></span>`,
+		``,
+	} {
+		if _, err := Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", doc)
+		}
+	}
+}
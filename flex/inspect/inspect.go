@@ -0,0 +1,317 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package inspect provides a panel that shows a widget tree as it is
+// laid out, a minimal set of developer tools for flex layouts.
+//
+// The panel lists every node of the tree with its names and Rect. The
+// node under the pointer in the list, or picked from the screen with
+// Pick, is highlighted where it is painted; a node clicked in the list
+// is selected, and its LayoutData, container style and flex.Explain
+// are shown below the tree.
+//
+// A Panel is placed beside the tree it inspects, after it, so that the
+// highlight is painted on top:
+//
+//	root := build.Row(
+//		build.Of(app, build.Grow(1)),
+//		build.Of(inspect.NewPanel(app)),
+//	)
+//
+// Route pointer events to it with a dispatch.Dispatcher.
+package inspect
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/dispatch"
+)
+
+// Default colors of a Panel.
+var (
+	Background = color.RGBA{0xf4, 0xf4, 0xf4, 0xff}
+	Text       = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	RowHover   = color.RGBA{0xd8, 0xe8, 0xff, 0xff}
+	RowSelect  = color.RGBA{0xb0, 0xd0, 0xff, 0xff}
+	Highlight  = color.RGBA{0x20, 0x60, 0xc0, 0x40} // premultiplied
+	Outline    = color.RGBA{0x20, 0x60, 0xc0, 0xff}
+)
+
+// Panel is a leaf widget listing the nodes of the tree rooted at
+// Target.
+type Panel struct {
+	widget.Node
+
+	Target *widget.Node
+
+	// Names gives the names shown for each node. If nil,
+	// flex.DefaultNodeNames is used; a flex.Tree's Names method shows
+	// the types, IDs and classes of its Doc.
+	Names func(n *widget.Node) flex.NodeNames
+
+	// Face is the font face. If nil, basicfont.Face7x13 is used.
+	Face font.Face
+
+	hovered, selected *widget.Node
+}
+
+// NewPanel returns a Panel inspecting the tree rooted at target.
+func NewPanel(target *widget.Node) *Panel {
+	p := &Panel{Target: target}
+	p.Node.Class = &panelClass{panel: p}
+	return p
+}
+
+// Hovered returns the highlighted node, or nil.
+func (p *Panel) Hovered() *widget.Node { return p.hovered }
+
+// Selected returns the node whose details are shown, or nil.
+func (p *Panel) Selected() *widget.Node { return p.selected }
+
+// Hover highlights n, which may be nil.
+func (p *Panel) Hover(n *widget.Node) { p.hovered = n }
+
+// Select shows the details of n, which may be nil.
+func (p *Panel) Select(n *widget.Node) { p.selected = n }
+
+// Pick highlights the deepest node of the target tree at pos, in the
+// coordinates of Target.Rect, as for flex.HitTest. It returns the
+// node, or nil if there is none.
+func (p *Panel) Pick(pos image.Point) *widget.Node {
+	path := flex.HitTest(p.Target, pos)
+	p.hovered = nil
+	if len(path) > 0 {
+		p.hovered = path[len(path)-1]
+	}
+	return p.hovered
+}
+
+type row struct {
+	n     *widget.Node
+	depth int
+}
+
+// rows returns the nodes of the target tree in depth-first order.
+func (p *Panel) rows() []row {
+	var rows []row
+	var walk func(n *widget.Node, depth int)
+	walk = func(n *widget.Node, depth int) {
+		rows = append(rows, row{n, depth})
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, depth+1)
+		}
+	}
+	if p.Target != nil {
+		walk(p.Target, 0)
+	}
+	return rows
+}
+
+// Lines returns the text of the panel: a line for each node of the
+// target tree, and then the details of the selected node.
+func (p *Panel) Lines() []string {
+	var lines []string
+	for _, r := range p.rows() {
+		lines = append(lines, strings.Repeat("  ", r.depth)+p.label(r.n)+" "+r.n.Rect.String())
+	}
+	return append(lines, p.details()...)
+}
+
+// label names n by its type, ID and classes, or by its Class's Go type
+// if it has no names.
+func (p *Panel) label(n *widget.Node) string {
+	names := p.Names
+	if names == nil {
+		names = flex.DefaultNodeNames
+	}
+	nn := names(n)
+	s := nn.Type
+	if nn.ID != "" {
+		s += "#" + nn.ID
+	}
+	for _, c := range nn.Classes {
+		s += "." + c
+	}
+	if s == "" {
+		s = fmt.Sprintf("%T", n.Class)
+		s = strings.TrimPrefix(s[strings.LastIndexByte(s, '.')+1:], "*")
+	}
+	return s
+}
+
+func (p *Panel) details() []string {
+	n := p.selected
+	if n == nil {
+		return nil
+	}
+	lines := []string{"", p.label(n) + " " + n.Rect.String()}
+	if fl := flex.FlexOf(n); fl != nil {
+		if s := flex.FormatStyle(fl); s != "" {
+			lines = append(lines, "style: "+s)
+		}
+	}
+	if n.Parent == nil {
+		return lines
+	}
+	fl := flex.FlexOf(n.Parent)
+	if fl == nil {
+		return lines
+	}
+	if d, ok := n.LayoutData.(flex.LayoutData); ok {
+		if s := flex.FormatItemStyle(d); s != "" {
+			lines = append(lines, "item: "+s)
+		}
+	}
+	if e := flex.Explain(fl, n); e != nil {
+		lines = append(lines, strings.Split(strings.TrimSuffix(e.String(), "\n"), "\n")...)
+	}
+	return lines
+}
+
+func (p *Panel) face() font.Face {
+	if p.Face == nil {
+		return basicfont.Face7x13
+	}
+	return p.Face
+}
+
+var _ dispatch.Handler = (*panelClass)(nil)
+
+type panelClass struct {
+	widget.LeafClassEmbed
+
+	panel *Panel
+}
+
+func (k *panelClass) lineHeight() int {
+	return k.panel.face().Metrics().Height.Ceil()
+}
+
+func (k *panelClass) Measure(n *widget.Node, t *widget.Theme) {
+	lines := k.panel.Lines()
+	w := 0
+	for _, line := range lines {
+		if lw := font.MeasureString(k.panel.face(), line).Ceil(); lw > w {
+			w = lw
+		}
+	}
+	n.MeasuredSize = image.Point{w + 8, len(lines)*k.lineHeight() + 8}
+}
+
+// rowAt returns the node listed at local position y, or nil.
+func (k *panelClass) rowAt(y int) *widget.Node {
+	i := (y - 4) / k.lineHeight()
+	if rows := k.panel.rows(); y >= 4 && i < len(rows) {
+		return rows[i].n
+	}
+	return nil
+}
+
+// PointerEvent implements dispatch.Handler: moving the mouse over a
+// row highlights its node, and pressing a button selects it.
+func (k *panelClass) PointerEvent(n *widget.Node, e *dispatch.Event) {
+	me, ok := e.Event.(mouse.Event)
+	if !ok {
+		return
+	}
+	switch me.Direction {
+	case mouse.DirNone:
+		k.panel.hovered = k.rowAt(e.Local.Y)
+	case mouse.DirPress:
+		if me.Button > 0 {
+			k.panel.selected = k.rowAt(e.Local.Y)
+		}
+	default:
+		return
+	}
+	e.StopPropagation()
+}
+
+func (k *panelClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	p := k.panel
+	r := n.Rect.Add(origin)
+	if clip, ok := dst.SubImage(r).(*image.RGBA); ok {
+		k.paintList(clip, r)
+	}
+	target := p.hovered
+	if target == nil {
+		target = p.selected
+	}
+	if target != nil {
+		if sr, ok := k.screenRect(target, origin); ok {
+			draw.Draw(dst, sr, image.NewUniform(Highlight), image.Point{}, draw.Over)
+			drawOutline(dst, sr, Outline)
+		}
+	}
+}
+
+func (k *panelClass) paintList(dst *image.RGBA, r image.Rectangle) {
+	p := k.panel
+	draw.Draw(dst, r, image.NewUniform(Background), image.Point{}, draw.Src)
+	face := p.face()
+	lh := k.lineHeight()
+	rows := p.rows()
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(Text), Face: face}
+	y := r.Min.Y + 4
+	for i, line := range p.Lines() {
+		if i < len(rows) {
+			var bg color.Color
+			switch rows[i].n {
+			case p.selected:
+				bg = RowSelect
+			case p.hovered:
+				bg = RowHover
+			}
+			if bg != nil {
+				draw.Draw(dst, image.Rect(r.Min.X, y, r.Max.X, y+lh), image.NewUniform(bg), image.Point{}, draw.Src)
+			}
+		}
+		d.Dot = fixed.P(r.Min.X+4, y+face.Metrics().Ascent.Ceil())
+		d.DrawString(line)
+		y += lh
+	}
+}
+
+// screenRect returns the Rect of target in the coordinates of dst,
+// where origin is the position in dst of the panel's parent. It reports
+// false if target is not in the same tree as the panel.
+func (k *panelClass) screenRect(target *widget.Node, origin image.Point) (image.Rectangle, bool) {
+	// Find the position of the top of the tree in dst.
+	top := origin
+	panelRoot := &k.panel.Node
+	for a := k.panel.Parent; a != nil; a = a.Parent {
+		top = top.Sub(a.Rect.Min)
+		panelRoot = a
+	}
+	r := target.Rect.Add(top)
+	root := target
+	for a := target.Parent; a != nil; a = a.Parent {
+		r = r.Add(a.Rect.Min)
+		root = a
+	}
+	return r, root == panelRoot
+}
+
+func drawOutline(dst *image.RGBA, r image.Rectangle, c color.Color) {
+	src := image.NewUniform(c)
+	for _, e := range []image.Rectangle{
+		{r.Min, image.Pt(r.Max.X, r.Min.Y+1)},
+		{image.Pt(r.Min.X, r.Max.Y-1), r.Max},
+		{r.Min, image.Pt(r.Min.X+1, r.Max.Y)},
+		{image.Pt(r.Max.X-1, r.Min.Y), r.Max},
+	} {
+		draw.Draw(dst, e.Intersect(r), src, image.Point{}, draw.Src)
+	}
+}
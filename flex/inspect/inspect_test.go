@@ -0,0 +1,81 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inspect
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/mouse"
+
+	"github.com/crawshaw/exp/flex"
+	"github.com/crawshaw/exp/flex/dispatch"
+)
+
+func TestPanel(t *testing.T) {
+	app := flex.NewFlex()
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(10)).Node
+	b.LayoutData = flex.LayoutData{Grow: 1}
+	app.AppendChild(a)
+	app.AppendChild(b)
+
+	panel := NewPanel(&app.Node)
+	root := flex.NewFlex()
+	root.AppendChild(&app.Node)
+	root.AppendChild(&panel.Node)
+	app.Node.LayoutData = flex.LayoutData{Grow: 1}
+	root.Class.Measure(&root.Node, nil)
+	root.Rect = image.Rect(0, 0, 400, 100)
+	root.Class.Layout(&root.Node, nil)
+
+	lines := panel.Lines()
+	want := []string{
+		"flex (0,0)-(" + strconv.Itoa(app.Rect.Max.X) + ",10)",
+		"  uniformClass (0,0)-(10,10)",
+		"  uniformClass (10,0)-(" + strconv.Itoa(app.Rect.Max.X) + ",10)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lines:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := panel.Pick(image.Pt(5, 5)); got != a {
+		t.Errorf("Pick = %p, want a", got)
+	}
+
+	// Hover the third row, then click it.
+	d := &dispatch.Dispatcher{Root: &root.Node}
+	lh := panel.Class.(*panelClass).lineHeight()
+	pos := panel.Rect.Min.Add(image.Pt(10, 4+2*lh+1))
+	d.Dispatch(mouse.Event{X: float32(pos.X), Y: float32(pos.Y)})
+	if panel.Hovered() != b {
+		t.Errorf("Hovered = %p, want b", panel.Hovered())
+	}
+	d.Dispatch(mouse.Event{X: float32(pos.X), Y: float32(pos.Y), Button: mouse.ButtonLeft, Direction: mouse.DirPress})
+	if panel.Selected() != b {
+		t.Fatalf("Selected = %p, want b", panel.Selected())
+	}
+	details := strings.Join(panel.Lines()[3:], "\n")
+	for _, s := range []string{"item: flex: 1 1 auto", "main: grow by"} {
+		if !strings.Contains(details, s) {
+			t.Errorf("details missing %q:\n%s", s, details)
+		}
+	}
+
+	// The hovered node is highlighted where it is painted.
+	dst := image.NewRGBA(root.Rect)
+	root.Class.Paint(&root.Node, nil, dst, image.Point{})
+	if got := dst.RGBAAt(b.Rect.Min.X, 0); got != Outline {
+		t.Errorf("outline pixel = %v, want %v", got, Outline)
+	}
+	if got := dst.RGBAAt(a.Rect.Min.X+5, 5); got != (color.RGBA{0, 0, 0, 0xff}) {
+		t.Errorf("unhighlighted pixel = %v", got)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+)
+
+// The enum types marshal to their CSS keyword, as used by ParseStyle,
+// so that they appear in JSON and other text encodings as readable
+// strings that do not change if the constants are renumbered.
+
+func (d Direction) MarshalText() ([]byte, error) {
+	return marshalEnum(directionNames[:], "Direction", int(d))
+}
+func (w FlexWrap) MarshalText() ([]byte, error) {
+	return marshalEnum(flexWrapNames[:], "FlexWrap", int(w))
+}
+func (j Justify) MarshalText() ([]byte, error) {
+	return marshalEnum(justifyNames[:], "Justify", int(j))
+}
+func (a AlignItem) MarshalText() ([]byte, error) {
+	return marshalEnum(alignItemNames[:], "AlignItem", int(a))
+}
+func (a AlignContent) MarshalText() ([]byte, error) {
+	return marshalEnum(alignContentNames[:], "AlignContent", int(a))
+}
+func (b Basis) MarshalText() ([]byte, error) {
+	return marshalEnum(basisNames[:], "Basis", int(b))
+}
+func (c Compat) MarshalText() ([]byte, error) {
+	return marshalEnum(compatNames[:], "Compat", int(c))
+}
+
+func (d *Direction) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(directionNames[:], "Direction", text)
+	*d = Direction(i)
+	return err
+}
+func (w *FlexWrap) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(flexWrapNames[:], "FlexWrap", text)
+	*w = FlexWrap(i)
+	return err
+}
+func (j *Justify) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(justifyNames[:], "Justify", text)
+	*j = Justify(i)
+	return err
+}
+func (a *AlignItem) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(alignItemNames[:], "AlignItem", text)
+	*a = AlignItem(i)
+	return err
+}
+func (a *AlignContent) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(alignContentNames[:], "AlignContent", text)
+	*a = AlignContent(i)
+	return err
+}
+func (b *Basis) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(basisNames[:], "Basis", text)
+	*b = Basis(i)
+	return err
+}
+func (c *Compat) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(compatNames[:], "Compat", text)
+	*c = Compat(i)
+	return err
+}
+
+func marshalEnum(names []string, typ string, v int) ([]byte, error) {
+	if v < 0 || v >= len(names) {
+		return nil, fmt.Errorf("flex: invalid %s %d", typ, v)
+	}
+	return []byte(names[v]), nil
+}
+
+// unmarshalEnum returns the index of text in names. On error it
+// returns 0, the zero value of the enum.
+func unmarshalEnum(names []string, typ string, text []byte) (int, error) {
+	i := keyword(names, string(text))
+	if i < 0 {
+		return 0, fmt.Errorf("flex: unknown %s %q", typ, text)
+	}
+	return i, nil
+}
+
+// layoutDataJSON is the JSON form of LayoutData. Bases and sizes are
+// written as their CSS values, such as "120px", "auto" or "50%", and
+// fields with their zero value are omitted.
+type layoutDataJSON struct {
+	Grow          float64       `json:"grow,omitempty"`
+	Fraction      float64       `json:"fraction,omitempty"`
+	Shrink        *float64      `json:"shrink,omitempty"`
+	Basis         string        `json:"basis,omitempty"`
+	BasisClamp    string        `json:"basis-clamp,omitempty"`
+	CrossBasis    string        `json:"cross-basis,omitempty"`
+	Align         AlignItem     `json:"align,omitempty"`
+	MinWidth      string        `json:"min-width,omitempty"`
+	MinHeight     string        `json:"min-height,omitempty"`
+	MaxWidth      string        `json:"max-width,omitempty"`
+	MaxHeight     string        `json:"max-height,omitempty"`
+	BreakAfter    bool          `json:"break-after,omitempty"`
+	FullBleed     bool          `json:"full-bleed,omitempty"`
+	Margin        string        `json:"margin,omitempty"`
+	ZIndex        int           `json:"z-index,omitempty"`
+	Pin           Pin           `json:"pin,omitempty"`
+	PointerEvents PointerEvents `json:"pointer-events,omitempty"`
+	Offset        *[2]int       `json:"offset,omitempty"`
+	Breakpoints   *[]Breakpoint `json:"breakpoints,omitempty"`
+}
+
+// MarshalJSON encodes d as an object whose keys are named after the
+// equivalent CSS properties, for example
+//
+//	{"grow":1,"basis":"120px","align":"center","min-width":"2em"}
+func (d LayoutData) MarshalJSON() ([]byte, error) {
+	j := layoutDataJSON{
+		Grow:          d.Grow,
+		Fraction:      d.Fraction,
+		Shrink:        d.Shrink,
+		Align:         d.Align,
+		BreakAfter:    d.BreakAfter,
+		FullBleed:     d.FullBleed,
+		ZIndex:        d.ZIndex,
+		Pin:           d.Pin,
+		PointerEvents: d.PointerEvents,
+		Breakpoints:   d.Breakpoints,
+	}
+	if d.Offset != (image.Point{}) {
+		j.Offset = &[2]int{d.Offset.X, d.Offset.Y}
+	}
+	if d.Basis != Auto {
+		j.Basis = formatBasis(d.Basis, d.BasisPx)
+	}
+	if d.BasisClamp != nil {
+		j.BasisClamp = formatBasisClamp(*d.BasisClamp)
+	}
+	if d.CrossBasis != Auto {
+		j.CrossBasis = formatBasis(d.CrossBasis, d.CrossSize)
+	}
+	var minLen, maxLen Size
+	if d.MinLength != nil {
+		minLen = *d.MinLength
+	}
+	if d.MaxLength != nil {
+		maxLen = *d.MaxLength
+	}
+	if minLen.Width.isSet() {
+		j.MinWidth = formatSizeLength(minLen.Width)
+	} else if d.MinSize.X != 0 {
+		j.MinWidth = formatLength(d.MinSize.X)
+	}
+	if minLen.Height.isSet() {
+		j.MinHeight = formatSizeLength(minLen.Height)
+	} else if d.MinSize.Y != 0 {
+		j.MinHeight = formatLength(d.MinSize.Y)
+	}
+	if maxLen.Width.isSet() {
+		j.MaxWidth = formatSizeLength(maxLen.Width)
+	} else if d.MaxSize != nil && d.MaxSize.X != noMaxSize {
+		j.MaxWidth = formatLength(d.MaxSize.X)
+	}
+	if maxLen.Height.isSet() {
+		j.MaxHeight = formatSizeLength(maxLen.Height)
+	} else if d.MaxSize != nil && d.MaxSize.Y != noMaxSize {
+		j.MaxHeight = formatLength(d.MaxSize.Y)
+	}
+	if d.Margin != (Insets{}) {
+		j.Margin = formatMargin(d.Margin)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON.
+func (d *LayoutData) UnmarshalJSON(data []byte) error {
+	var j layoutDataJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	r := LayoutData{
+		Grow:          j.Grow,
+		Fraction:      j.Fraction,
+		Shrink:        j.Shrink,
+		Align:         j.Align,
+		BreakAfter:    j.BreakAfter,
+		FullBleed:     j.FullBleed,
+		ZIndex:        j.ZIndex,
+		Pin:           j.Pin,
+		PointerEvents: j.PointerEvents,
+		Breakpoints:   j.Breakpoints,
+	}
+	if j.Offset != nil {
+		r.Offset = image.Pt(j.Offset[0], j.Offset[1])
+	}
+	if j.Grow < 0 {
+		return fmt.Errorf("flex: invalid grow %v", j.Grow)
+	}
+	if j.Fraction < 0 {
+		return fmt.Errorf("flex: invalid fraction %v", j.Fraction)
+	}
+	if j.Shrink != nil && *j.Shrink < 0 {
+		return fmt.Errorf("flex: invalid shrink %v", *j.Shrink)
+	}
+	var err error
+	if j.Basis != "" {
+		if r.Basis, r.BasisPx, err = parseBasis(j.Basis); err != nil {
+			return fmt.Errorf("flex: invalid basis %q: %v", j.Basis, err)
+		}
+	}
+	if j.BasisClamp != "" {
+		if !isBasisClamp(j.BasisClamp) {
+			return fmt.Errorf("flex: invalid basis-clamp %q", j.BasisClamp)
+		}
+		if r.BasisClamp, err = parseBasisClamp(j.BasisClamp); err != nil {
+			return fmt.Errorf("flex: invalid basis-clamp %q: %v", j.BasisClamp, err)
+		}
+	}
+	if j.CrossBasis != "" {
+		if r.CrossBasis, r.CrossSize, err = parseBasis(j.CrossBasis); err != nil {
+			return fmt.Errorf("flex: invalid cross-basis %q: %v", j.CrossBasis, err)
+		}
+	}
+	for _, p := range [...]struct{ prop, val string }{
+		{"min-width", j.MinWidth},
+		{"min-height", j.MinHeight},
+		{"max-width", j.MaxWidth},
+		{"max-height", j.MaxHeight},
+		{"margin", j.Margin},
+	} {
+		if p.val == "" {
+			continue
+		}
+		if _, err := r.setProperty(p.prop, p.val); err != nil {
+			return err
+		}
+	}
+	*d = r
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"encoding/json"
+	"image"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+)
+
+func TestEnumJSON(t *testing.T) {
+	type container struct {
+		Direction    Direction    `json:"direction"`
+		Wrap         FlexWrap     `json:"wrap"`
+		Justify      Justify      `json:"justify"`
+		AlignItem    AlignItem    `json:"align-items"`
+		AlignContent AlignContent `json:"align-content"`
+		Basis        Basis        `json:"basis"`
+		Compat       Compat       `json:"compat"`
+	}
+	c := container{ColumnReverse, WrapReverse, JustifySpaceAround, AlignItemBaseline, AlignContentCenter, Definite, CompatYoga}
+	const want = `{"direction":"column-reverse","wrap":"wrap-reverse","justify":"space-around","align-items":"baseline","align-content":"center","basis":"definite","compat":"yoga"}`
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("Marshal:\n got %s\nwant %s", b, want)
+	}
+	var got container
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != c {
+		t.Errorf("Unmarshal: got %+v, want %+v", got, c)
+	}
+
+	if err := json.Unmarshal([]byte(`{"direction":"sideways"}`), &got); err == nil {
+		t.Error("Unmarshal of unknown Direction succeeded")
+	}
+	if _, err := json.Marshal(container{Direction: 9}); err == nil {
+		t.Error("Marshal of invalid Direction succeeded")
+	}
+}
+
+func TestLayoutDataJSON(t *testing.T) {
+	shrink := 0.5
+	tests := []struct {
+		d    LayoutData
+		want string
+	}{
+		{LayoutData{}, `{}`},
+		{
+			LayoutData{Grow: 1, Shrink: &shrink, Basis: Definite, BasisPx: 120, Align: AlignItemCenter},
+			`{"grow":1,"shrink":0.5,"basis":"120px","align":"center"}`,
+		},
+		{
+			LayoutData{
+				MinSize:    image.Pt(10, 0),
+				MaxSize:    &image.Point{noMaxSize, 80},
+				MinLength:  &Size{Height: Length{Kind: LengthValue, Value: unit.Ems(2)}},
+				MaxLength:  &Size{Width: Length{Kind: LengthPercent, Percent: 50}},
+				CrossBasis: Definite,
+				CrossSize:  30,
+				BreakAfter: true,
+				FullBleed:  true,
+				ZIndex:     -2,
+				Pin:        PinEnd,
+			},
+			`{"cross-basis":"30px","min-width":"10px","min-height":"2em","max-width":"50%","max-height":"80px","break-after":true,"full-bleed":true,"z-index":-2,"pin":"end"}`,
+		},
+		{
+			LayoutData{BasisClamp: &BasisClamp{Min: Length{Kind: LengthValue, Value: unit.Pixels(100)}, Preferred: Length{Kind: LengthPercent, Percent: 30}}},
+			`{"basis-clamp":"clamp(100px, 30%, none)"}`,
+		},
+		{LayoutData{Fraction: 2}, `{"fraction":2}`},
+		{LayoutData{PointerEvents: PointerNone}, `{"pointer-events":"none"}`},
+		{LayoutData{Margin: Insets{Left: -10}}, `{"margin":"0px 0px 0px -10px"}`},
+		{LayoutData{Offset: image.Pt(-3, 4)}, `{"offset":[-3,4]}`},
+		{
+			LayoutData{
+				Basis: Content,
+				Breakpoints: &[]Breakpoint{
+					{MinMainSize: 600, LayoutData: LayoutData{Grow: 2}},
+				},
+			},
+			`{"basis":"content","breakpoints":[{"min-main-size":600,"layout-data":{"grow":2}}]}`,
+		},
+	}
+	for _, test := range tests {
+		b, err := json.Marshal(test.d)
+		if err != nil {
+			t.Errorf("Marshal(%+v): %v", test.d, err)
+			continue
+		}
+		if string(b) != test.want {
+			t.Errorf("Marshal:\n got %s\nwant %s", b, test.want)
+		}
+		var got LayoutData
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("Unmarshal(%s): %v", b, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.d) {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", b, got, test.d)
+		}
+	}
+
+	for _, bad := range []string{
+		`{"basis":"wide"}`,
+		`{"grow":-1}`,
+		`{"fraction":-1}`,
+		`{"min-width":"tall"}`,
+		`{"align":"sideways"}`,
+	} {
+		var d LayoutData
+		if err := json.Unmarshal([]byte(bad), &d); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded: %+v", bad, d)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// A Length is a distance along one axis: a unit.Value converted to
+// pixels by the Theme, or a percentage of the container's size along
+// the same axis. Kind says which.
+//
+// The zero Length is unset. A Length of zero pixels or zero percent
+// is set, and has the Kind of its unit.
+type Length struct {
+	Kind    LengthKind
+	Value   unit.Value
+	Percent float64
+}
+
+// LengthKind says which field of a Length holds its distance.
+type LengthKind int8
+
+// Possible values of LengthKind.
+const (
+	LengthUnset   LengthKind = iota // no distance
+	LengthValue                     // Value
+	LengthPercent                   // Percent of the container
+)
+
+var lengthKindNames = [...]string{
+	LengthUnset:   "unset",
+	LengthValue:   "value",
+	LengthPercent: "percent",
+}
+
+func (k LengthKind) String() string {
+	return enumName(lengthKindNames[:], "LengthKind", int(k))
+}
+
+// Size is a width and height given as Lengths.
+type Size struct {
+	Width, Height Length
+}
+
+func (l Length) isSet() bool {
+	return l.Kind != LengthUnset
+}
+
+// pixels resolves l in a container whose size along the same axis is
+// container pixels. As in CSS, a percentage of an indefinite container,
+// one that is Unbounded or negative, is zero.
+// An unset Length is zero.
+func (l Length) pixels(t *widget.Theme, container int) int {
+	switch l.Kind {
+	case LengthValue:
+		return t.Pixels(l.Value).Round()
+	case LengthPercent:
+		if !definite(container) {
+			return 0
+		}
+		return int(math.Floor(l.Percent*float64(container)/100 + 0.5))
+	}
+	return 0
+}
+
+// maxPixels is pixels for a maximum size: a percentage of an
+// indefinite container is no maximum.
+func (l Length) maxPixels(t *widget.Theme, container int) int {
+	if l.Kind == LengthPercent && !definite(container) {
+		return noMaxSize
+	}
+	return l.pixels(t, container)
+}
+
+// definite reports whether a container size is known, and so can
+// resolve percentages.
+func definite(container int) bool {
+	return container >= 0 && container < Unbounded
+}
+
+// resolveLengths returns d with MinLength and MaxLength converted to
+// pixels and folded into MinSize and MaxSize.
+func (d LayoutData) resolveLengths(t *widget.Theme, container image.Point) LayoutData {
+	if min := d.MinLength; min != nil {
+		if min.Width.isSet() {
+			d.MinSize.X = min.Width.pixels(t, container.X)
+		}
+		if min.Height.isSet() {
+			d.MinSize.Y = min.Height.pixels(t, container.Y)
+		}
+	}
+	if max := d.MaxLength; max != nil && (max.Width.isSet() || max.Height.isSet()) {
+		maxSize := image.Point{noMaxSize, noMaxSize}
+		if d.MaxSize != nil {
+			maxSize = *d.MaxSize
+		}
+		if max.Width.isSet() {
+			maxSize.X = max.Width.maxPixels(t, container.X)
+		}
+		if max.Height.isSet() {
+			maxSize.Y = max.Height.maxPixels(t, container.Y)
+		}
+		d.MaxSize = &maxSize
+	}
+	d.MinLength, d.MaxLength = nil, nil
+	return d
+}
+
+// A BasisClamp sets the flex basis of an item to Preferred, clamped to
+// the range from Min to Max, like CSS's clamp(Min, Preferred, Max).
+// Lengths given as percentages are of the container's main size, so
+// that, for example, a column can take a third of a wide container but
+// stay readable in a narrow one without Breakpoints.
+//
+// An unset Min or Preferred is zero, and an unset Max is no maximum.
+// As in CSS, Min wins if it is larger than Max.
+type BasisClamp struct {
+	Min, Preferred, Max Length
+}
+
+// pixels resolves c in a container whose main size is container pixels.
+func (c BasisClamp) pixels(t *widget.Theme, container int) int {
+	px := c.Preferred.pixels(t, container)
+	if c.Max.isSet() {
+		if max := c.Max.maxPixels(t, container); px > max {
+			px = max
+		}
+	}
+	if min := c.Min.pixels(t, container); px < min {
+		px = min
+	}
+	return px
+}
+
+// resolveBasisClamps returns items with the BasisClamp of each item in
+// a container of the given size resolved to a Definite Basis. Items is
+// copied if any item has a BasisClamp.
+func (fl *Flex) resolveBasisClamps(size image.Point, items []Item, t *widget.Theme) []Item {
+	mainSize := fl.mainSize(size)
+	copied := false
+	for i, it := range items {
+		d := it.LayoutData.resolve(mainSize)
+		if d.BasisClamp == nil {
+			continue
+		}
+		if !copied {
+			items = append([]Item(nil), items...)
+			copied = true
+		}
+		d.Basis, d.BasisPx = Definite, d.BasisClamp.pixels(t, mainSize)
+		d.BasisClamp = nil
+		items[i].LayoutData = d
+	}
+	return items
+}
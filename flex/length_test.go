@@ -0,0 +1,71 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestLengths(t *testing.T) {
+	fl := NewFlex()
+	c := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(80)).Node
+	c.LayoutData = LayoutData{
+		MinLength: &Size{Width: Length{Kind: LengthValue, Value: unit.Points(30)}},
+		MaxLength: &Size{Height: Length{Kind: LengthPercent, Percent: 50}},
+	}
+	fl.AppendChild(c)
+	fl.Rect = image.Rect(0, 0, 200, 100)
+
+	for _, test := range []struct {
+		dpi  float64
+		want image.Rectangle
+	}{
+		{72, image.Rect(0, 0, 30, 50)},
+		{144, image.Rect(0, 0, 60, 50)},
+	} {
+		theme := &widget.Theme{DPI: test.dpi}
+		fl.Class.Measure(&fl.Node, theme)
+		fl.Class.Layout(&fl.Node, theme)
+		if c.Rect != test.want {
+			t.Errorf("at %v DPI: Rect=%v, want %v", test.dpi, c.Rect, test.want)
+		}
+	}
+}
+
+// TestZeroLength checks that a Length of zero is set, unlike the zero
+// Length.
+func TestZeroLength(t *testing.T) {
+	fl := NewFlex()
+	c := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(80)).Node
+	c.LayoutData = LayoutData{
+		MaxLength: &Size{
+			Width:  Length{Kind: LengthPercent},
+			Height: Length{Kind: LengthValue, Value: unit.Pixels(0)},
+		},
+	}
+	fl.AppendChild(c)
+	fl.Rect = image.Rect(0, 0, 200, 100)
+	theme := new(widget.Theme)
+	fl.Class.Measure(&fl.Node, theme)
+	fl.Class.Layout(&fl.Node, theme)
+	if c.Rect != (image.Rectangle{}) {
+		t.Errorf("Rect=%v, want empty", c.Rect)
+	}
+
+	c.LayoutData = LayoutData{BasisClamp: &BasisClamp{
+		Preferred: Length{Kind: LengthPercent, Percent: 50},
+		Max:       Length{Kind: LengthValue},
+	}}
+	fl.Class.Measure(&fl.Node, theme)
+	fl.Class.Layout(&fl.Node, theme)
+	if w := c.Rect.Dx(); w != 0 {
+		t.Errorf("clamp(0, 50%%, 0px) width=%d, want 0", w)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Doc is a declarative description of a widget tree.
+//
+// A Doc can be decoded from JSON with Load. The field tags also
+// describe the equivalent YAML document, so a YAML decoder can fill
+// in a Doc to be passed to Build.
+type Doc struct {
+	// Type is the widget type, "flex" or "uniform".
+	Type string `json:"type" yaml:"type"`
+
+	// ID optionally names the node in the Tree.
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Class is a space-separated list of names that StyleSheet
+	// selectors match the node by, as with the HTML class attribute.
+	Class string `json:"class,omitempty" yaml:"class,omitempty"`
+
+	// Style is a block of CSS declarations.
+	//
+	// A flex node accepts the container properties of ParseStyle.
+	// A uniform node accepts width and height for its natural size,
+	// and background-color. Any node accepts the item properties of
+	// ParseItemStyle, which become its LayoutData.
+	Style string `json:"style,omitempty" yaml:"style,omitempty"`
+
+	// LayoutData, if set, is the node's LayoutData in its JSON form,
+	// before the item properties of Style are applied. It is not
+	// available in YAML.
+	LayoutData *LayoutData `json:"layout-data,omitempty" yaml:"-"`
+
+	// Children are the child nodes of a flex node.
+	Children []*Doc `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// Tree is a widget tree built from a Doc.
+type Tree struct {
+	Root *widget.Node
+
+	// ByID maps Doc IDs to their nodes.
+	ByID map[string]*widget.Node
+
+	names map[*widget.Node]NodeNames
+}
+
+// Names returns the names of n from its Doc: its type, ID and classes.
+// It can be passed to StyleSheet.Apply.
+func (t *Tree) Names(n *widget.Node) NodeNames {
+	return t.names[n]
+}
+
+// Load decodes a JSON Doc from r and builds its widget tree.
+func Load(r io.Reader) (*Tree, error) {
+	doc := new(Doc)
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("flex: decoding document: %v", err)
+	}
+	return Build(doc)
+}
+
+// Build builds the widget tree described by doc.
+func Build(doc *Doc) (*Tree, error) {
+	t := &Tree{
+		ByID:  make(map[string]*widget.Node),
+		names: make(map[*widget.Node]NodeNames),
+	}
+	root, err := t.build(doc)
+	if err != nil {
+		return nil, err
+	}
+	t.Root = root
+	return t, nil
+}
+
+func (t *Tree) build(doc *Doc) (*widget.Node, error) {
+	var (
+		n     *widget.Node
+		d     LayoutData
+		haveD bool
+	)
+	if doc.LayoutData != nil {
+		d, haveD = *doc.LayoutData, true
+	}
+	item := func(prop, val string) (bool, error) {
+		ok, err := d.setProperty(prop, val)
+		haveD = haveD || ok
+		return ok, err
+	}
+
+	switch doc.Type {
+	case "flex":
+		fl := NewFlex()
+		if err := parseDocStyle(doc, fl.setProperty, item); err != nil {
+			return nil, err
+		}
+		n = &fl.Node
+	case "uniform":
+		if len(doc.Children) > 0 {
+			return nil, fmt.Errorf("flex: uniform node %q has children", doc.ID)
+		}
+		u := uniformStyle{color: color.Transparent}
+		if err := parseDocStyle(doc, u.setProperty, item); err != nil {
+			return nil, err
+		}
+		n = widget.NewUniform(u.color, u.width, u.height).Node
+	default:
+		return nil, fmt.Errorf("flex: unknown node type %q", doc.Type)
+	}
+
+	if haveD {
+		n.LayoutData = d
+	}
+	t.names[n] = NodeNames{Type: doc.Type, ID: doc.ID, Classes: strings.Fields(doc.Class)}
+	if doc.ID != "" {
+		if t.ByID[doc.ID] != nil {
+			return nil, fmt.Errorf("flex: duplicate id %q", doc.ID)
+		}
+		t.ByID[doc.ID] = n
+	}
+	for _, c := range doc.Children {
+		cn, err := t.build(c)
+		if err != nil {
+			return nil, err
+		}
+		n.AppendChild(cn)
+	}
+	return n, nil
+}
+
+// parseDocStyle parses doc.Style, offering each declaration to the
+// setters in turn.
+func parseDocStyle(doc *Doc, setters ...func(prop, val string) (bool, error)) error {
+	err := parseDecls(doc.Style, func(prop, val string) (bool, error) {
+		for _, set := range setters {
+			if ok, err := set(prop, val); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	})
+	if err != nil && doc.ID != "" {
+		err = fmt.Errorf("%v (in %q)", err, doc.ID)
+	}
+	return err
+}
+
+// uniformStyle holds the style properties of a uniform node.
+type uniformStyle struct {
+	color         color.Color
+	width, height unit.Value
+}
+
+func (u *uniformStyle) setProperty(prop, val string) (ok bool, err error) {
+	switch prop {
+	case "width":
+		u.width, err = parseValue(val)
+	case "height":
+		u.height, err = parseValue(val)
+	case "background-color":
+		u.color, err = parseColor(val)
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("flex: invalid value %q for %s: %v", val, prop, err)
+	}
+	return true, nil
+}
+
+var unitSuffixes = []struct {
+	suffix string
+	unit   unit.Unit
+}{
+	{"px", unit.Px},
+	{"dp", unit.Dp},
+	{"pt", unit.Pt},
+	{"mm", unit.Mm},
+	{"in", unit.In},
+	{"em", unit.Em},
+	{"ex", unit.Ex},
+	{"ch", unit.Ch},
+}
+
+// parseValue parses a CSS length into a unit.Value.
+func parseValue(val string) (unit.Value, error) {
+	s := strings.ToLower(val)
+	for _, u := range unitSuffixes {
+		if strings.HasSuffix(s, u.suffix) {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil || f < 0 {
+				break
+			}
+			return unit.Value{F: f, U: u.unit}, nil
+		}
+	}
+	if s == "0" {
+		return unit.Pixels(0), nil
+	}
+	return unit.Value{}, fmt.Errorf("%q is not a length", val)
+}
+
+// parseColor parses a CSS color of the form #rgb, #rrggbb,
+// rgb(r, g, b) or rgba(r, g, b, a).
+func parseColor(val string) (color.Color, error) {
+	s := strings.ToLower(strings.TrimSpace(val))
+	bad := fmt.Errorf("%q is not a color", val)
+	switch {
+	case strings.HasPrefix(s, "#"):
+		s = s[1:]
+		if len(s) == 3 {
+			s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+		}
+		if len(s) != 6 {
+			return nil, bad
+		}
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return nil, bad
+		}
+		return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba("):
+		if !strings.HasSuffix(s, ")") {
+			return nil, bad
+		}
+		args := strings.Split(s[strings.IndexByte(s, '(')+1:len(s)-1], ",")
+		if len(args) != 3 && len(args) != 4 {
+			return nil, bad
+		}
+		var c [4]uint8
+		c[3] = 0xff
+		for i, arg := range args {
+			arg = strings.TrimSpace(arg)
+			if i == 3 {
+				a, err := strconv.ParseFloat(arg, 64)
+				if err != nil || a < 0 || a > 1 {
+					return nil, bad
+				}
+				c[3] = uint8(a*0xff + 0.5)
+				continue
+			}
+			v, err := strconv.ParseUint(arg, 10, 8)
+			if err != nil {
+				return nil, bad
+			}
+			c[i] = uint8(v)
+		}
+		// CSS alpha is not premultiplied.
+		return color.NRGBA{c[0], c[1], c[2], c[3]}, nil
+	}
+	return nil, bad
+}
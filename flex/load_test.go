@@ -0,0 +1,117 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+const loadTestDoc = `{
+	"type": "flex",
+	"children": [
+		{"type": "uniform", "id": "a", "style": "width: 100px; height: 100px; background-color: #f00"},
+		{
+			"type": "flex",
+			"id": "b",
+			"style": "flex-direction: column; flex: 1; align-self: stretch",
+			"children": [
+				{"type": "uniform", "id": "c", "style": "width: 10px; flex: 1"},
+				{"type": "uniform", "id": "d", "style": "width: 20px; flex: 1; background-color: rgb(0, 0, 255)"}
+			]
+		}
+	]
+}`
+
+func TestLoad(t *testing.T) {
+	tree, err := Load(strings.NewReader(loadTestDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root
+	root.Class.Measure(root, nil)
+	root.Rect = image.Rectangle{Max: image.Pt(300, 100)}
+	root.Class.Layout(root, nil)
+
+	want := map[string]image.Rectangle{
+		"a": {size(0, 0), size(100, 100)},
+		"b": {size(100, 0), size(300, 100)},
+		"c": {size(0, 0), size(10, 50)},
+		"d": {size(0, 50), size(20, 100)},
+	}
+	for id, r := range want {
+		n := tree.ByID[id]
+		if n == nil {
+			t.Errorf("missing node %q", id)
+			continue
+		}
+		if n.Rect != r {
+			t.Errorf("%s.Rect=%v, want %v", id, n.Rect, r)
+		}
+	}
+	if _, ok := tree.ByID["a"].LayoutData.(LayoutData); ok {
+		t.Errorf("a has LayoutData, want none")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, doc := range []string{
+		`{"type": "button"}`,
+		`{"type": "flex", "style": "flex-direction: up"}`,
+		`{"type": "flex", "style": "width: 10px"}`,
+		`{"type": "uniform", "style": "width: 10"}`,
+		`{"type": "uniform", "children": [{"type": "uniform"}]}`,
+		`{"type": "flex", "children": [{"type": "uniform", "id": "x"}, {"type": "uniform", "id": "x"}]}`,
+		`{"type": "flex"`,
+	} {
+		if _, err := Load(strings.NewReader(doc)); err == nil {
+			t.Errorf("Load(%s) succeeded, want error", doc)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want color.Color
+	}{
+		{"#f80", color.RGBA{0xff, 0x88, 0x00, 0xff}},
+		{"#0A0b0C", color.RGBA{0x0a, 0x0b, 0x0c, 0xff}},
+		{"rgb(1, 2, 3)", color.NRGBA{1, 2, 3, 0xff}},
+		{"rgba(1,2,3,0.5)", color.NRGBA{1, 2, 3, 0x80}},
+	}
+	for _, test := range tests {
+		got, err := parseColor(test.in)
+		if err != nil {
+			t.Errorf("parseColor(%q): %v", test.in, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseColor(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestLoadLayoutData(t *testing.T) {
+	const doc = `{
+	"type": "flex",
+	"children": [
+		{"type": "uniform", "id": "a", "layout-data": {"grow": 1, "basis": "0px", "cross-basis": "30px"}, "style": "flex-grow: 3"}
+	]
+}`
+	tree, err := Load(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := tree.ByID["a"].LayoutData.(LayoutData)
+	if !ok {
+		t.Fatal("a has no LayoutData")
+	}
+	if d.Grow != 3 || d.Basis != Definite || d.CrossBasis != Definite || d.CrossSize != 30 {
+		t.Errorf("LayoutData = %+v, want style applied over layout-data", d)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// A LoopError reports the children of a Flex whose main sizes the flex
+// loop (§9.7.4) could not resolve, at a Layout, Plan or SolveErr. The
+// loop freezes at least one child on every pass, so it can only fail
+// on NaN or infinite inputs, such as a Grow of math.NaN(). Rather than
+// spin, the loop is stopped after one pass per child, and the children
+// left are given their hypothetical main sizes, or 0 if those are NaN.
+type LoopError struct {
+	Flex *Flex
+
+	// Line is the index of the first flex line that did not resolve,
+	// and Iterations the number of passes of the loop over it.
+	Line       int
+	Iterations int
+
+	// Items are the children of the line that never froze.
+	Items []LoopItem
+}
+
+// LoopItem is a child reported by a LoopError.
+type LoopItem struct {
+	// Node is the child, or nil if the error is from SolveErr.
+	Node *widget.Node
+
+	// Index is the position of the child among its siblings, or in
+	// the items passed to SolveErr.
+	Index int
+
+	// FlexBaseSize and MainSize are the sizes of the child when the
+	// loop was stopped, before MainSize was replaced.
+	FlexBaseSize float64
+	MainSize     float64
+
+	// Reason says why the child never froze, such as "flex-grow is
+	// NaN". A child with sound inputs is often reported only because
+	// another child's NaN spoilt the free space they share.
+	Reason string
+}
+
+func (e *LoopError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "flex: flex loop stopped after %d iterations on line %d:", e.Iterations, e.Line)
+	for i, it := range e.Items {
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		fmt.Fprintf(&buf, " child %d: %s", it.Index, it.Reason)
+	}
+	return buf.String()
+}
+
+// Err returns the error of the last Layout of fl, if any. It is a
+// *LoopError.
+func (fl *Flex) Err() error {
+	k, ok := fl.Class.(*flexClass)
+	if !ok || k.err == nil {
+		return nil
+	}
+	return k.err
+}
+
+// CheckLayout returns the first error, in a depth-first walk of the
+// tree rooted at n, of the last Layout of the Flex nodes in the tree.
+// An app calls it after laying out the tree, as RunWindow does.
+func CheckLayout(n *widget.Node) error {
+	if k, ok := n.Class.(*flexClass); ok && k.err != nil {
+		return k.err
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := CheckLayout(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unstick ends the flex loop over line after it failed to freeze every
+// child, freezing the rest at their hypothetical main sizes and
+// recording why they were left.
+func (fl *Flex) unstick(line *flexLine, grow bool) {
+	for _, child := range line.child {
+		if child.frozen {
+			continue
+		}
+		child.stuck = fl.stuckReason(child, grow)
+		child.unclamped = child.mainSize
+		child.mainSize = child.hypoMainSize
+		if math.IsNaN(child.mainSize) {
+			child.mainSize = 0
+		}
+		child.mainClamp = ClampNone
+		child.frozen = true
+	}
+}
+
+func (fl *Flex) stuckReason(child *element, grow bool) string {
+	prop, factor := "flex-shrink", fl.shrinkFactor(child.LayoutData)
+	if grow {
+		prop, factor = "flex-grow", growFactor(child.LayoutData)
+	}
+	switch {
+	case math.IsNaN(factor):
+		return prop + " is NaN"
+	case math.IsInf(factor, 0):
+		return fmt.Sprintf("%s is %v", prop, factor)
+	case math.IsNaN(child.flexBaseSize):
+		return "flex base size is NaN"
+	case math.IsNaN(child.unclamped):
+		return "main size is NaN"
+	}
+	return "main size did not converge"
+}
+
+// newLoopError returns the LoopError of fl for the items laid out in
+// lines, or nil if the flex loop resolved them all. The items are the
+// children of n, if n is not nil.
+func newLoopError(fl *Flex, n *widget.Node, lines []flexLine) *LoopError {
+	for i, line := range lines {
+		var items []LoopItem
+		for _, child := range line.child {
+			if child.stuck == "" {
+				continue
+			}
+			it := LoopItem{
+				Index:        child.index,
+				FlexBaseSize: child.flexBaseSize,
+				MainSize:     child.unclamped,
+				Reason:       child.stuck,
+			}
+			if n != nil {
+				it.Node = nthChild(n, child.index)
+			}
+			items = append(items, it)
+		}
+		if items != nil {
+			return &LoopError{Flex: fl, Line: i, Iterations: len(line.child), Items: items}
+		}
+	}
+	return nil
+}
+
+func nthChild(n *widget.Node, i int) *widget.Node {
+	c := n.FirstChild
+	for ; c != nil && i > 0; i-- {
+		c = c.NextSibling
+	}
+	return c
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestLoopError(t *testing.T) {
+	shrink := math.Inf(1)
+	for _, tc := range []struct {
+		name   string
+		width  int
+		data   LayoutData
+		reason string
+	}{
+		{"grow NaN", 100, LayoutData{Grow: math.NaN()}, "flex-grow is NaN"},
+		{"grow Inf", 100, LayoutData{Grow: math.Inf(1)}, "flex-grow is +Inf"},
+		{"shrink Inf", 30, LayoutData{Shrink: &shrink}, "flex-shrink is +Inf"},
+	} {
+		fl := NewFlex()
+		var children []*widget.Node
+		for i := 0; i < 3; i++ {
+			c := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(20)).Node
+			children = append(children, c)
+			fl.AppendChild(c)
+		}
+		children[1].LayoutData = tc.data
+		fl.Class.Measure(&fl.Node, nil)
+		fl.Rect = image.Rect(0, 0, tc.width, 20)
+		fl.Class.Layout(&fl.Node, nil)
+
+		err, ok := fl.Err().(*LoopError)
+		if !ok {
+			t.Errorf("%s: Err() = %v, want a *LoopError", tc.name, fl.Err())
+			continue
+		}
+		if CheckLayout(&fl.Node) != err {
+			t.Errorf("%s: CheckLayout = %v", tc.name, CheckLayout(&fl.Node))
+		}
+		var culprit *LoopItem
+		for i, it := range err.Items {
+			if it.Node == children[1] {
+				culprit = &err.Items[i]
+			}
+		}
+		if culprit == nil || culprit.Index != 1 || culprit.Reason != tc.reason {
+			t.Errorf("%s: Items = %+v, want child 1 with %q", tc.name, err.Items, tc.reason)
+		}
+		if !strings.Contains(err.Error(), "child 1: "+tc.reason) {
+			t.Errorf("%s: Error() = %q", tc.name, err.Error())
+		}
+		if r := children[1].Rect; r.Dx() != 20 {
+			t.Errorf("%s: stuck child Rect = %v, want its hypothetical width 20", tc.name, r)
+		}
+
+		children[1].LayoutData = LayoutData{}
+		fl.Class.Layout(&fl.Node, nil)
+		if err := fl.Err(); err != nil {
+			t.Errorf("%s: Err() = %v after fixing the child", tc.name, err)
+		}
+	}
+}
+
+func TestCheckLayoutNested(t *testing.T) {
+	outer := NewFlex()
+	inner := NewFlex()
+	inner.LayoutData = LayoutData{Grow: 1}
+	c := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(20)).Node
+	c.LayoutData = LayoutData{Grow: math.NaN()}
+	inner.AppendChild(c)
+	outer.AppendChild(&inner.Node)
+	outer.Class.Measure(&outer.Node, nil)
+	outer.Rect = image.Rect(0, 0, 100, 20)
+	outer.Class.Layout(&outer.Node, nil)
+
+	if outer.Err() != nil {
+		t.Errorf("outer Err() = %v", outer.Err())
+	}
+	err, ok := CheckLayout(&outer.Node).(*LoopError)
+	if !ok || err.Flex != inner || err.Items[0].Node != c {
+		t.Errorf("CheckLayout = %v, want the error of inner", err)
+	}
+}
+
+func TestSolveErr(t *testing.T) {
+	fl := NewFlex()
+	items := []Item{
+		{MeasuredSize: size(20, 20), LayoutData: LayoutData{Grow: 1}},
+		{MeasuredSize: size(20, 20), LayoutData: LayoutData{Grow: math.NaN()}},
+	}
+	rects, err := fl.SolveErr(size(100, 20), items)
+	lerr, ok := err.(*LoopError)
+	if !ok || lerr.Flex != fl || lerr.Items[len(lerr.Items)-1].Index != 1 || lerr.Items[0].Node != nil {
+		t.Errorf("SolveErr error = %#v, want a *LoopError for item 1", err)
+	}
+	if len(rects) != 2 || rects[1].Dx() != 20 {
+		t.Errorf("SolveErr Rects = %v", rects)
+	}
+
+	items[1].LayoutData.Grow = 1
+	if _, err := fl.SolveErr(size(100, 20), items); err != nil {
+		t.Errorf("SolveErr error = %v for sound items", err)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+// margins returns the margins of d at the start and end of the main
+// and cross axes of fl, in the direction the items flow: a RowReverse
+// starts at the right, and a WrapReverse Row at the bottom.
+func (fl *Flex) margins(d LayoutData) (main, cross [2]float64) {
+	m := d.Margin
+	switch fl.Direction {
+	case Row:
+		main = [2]float64{float64(m.Left), float64(m.Right)}
+		cross = [2]float64{float64(m.Top), float64(m.Bottom)}
+	case RowReverse:
+		main = [2]float64{float64(m.Right), float64(m.Left)}
+		cross = [2]float64{float64(m.Top), float64(m.Bottom)}
+	case Column:
+		main = [2]float64{float64(m.Top), float64(m.Bottom)}
+		cross = [2]float64{float64(m.Left), float64(m.Right)}
+	case ColumnReverse:
+		main = [2]float64{float64(m.Bottom), float64(m.Top)}
+		cross = [2]float64{float64(m.Left), float64(m.Right)}
+	}
+	if fl.Wrap == WrapReverse {
+		cross[0], cross[1] = cross[1], cross[0]
+	}
+	return main, cross
+}
+
+// mainMargin returns the sum of the main margins of e, which may be
+// negative.
+func (e *element) mainMargin() float64 { return e.marginMain[0] + e.marginMain[1] }
+
+// crossMargin returns the sum of the cross margins of e, which may be
+// negative.
+func (e *element) crossMargin() float64 { return e.marginCross[0] + e.marginCross[1] }
@@ -0,0 +1,145 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// marginFlex lays out a Flex of size with one 40x20 child per
+// LayoutData, and returns the children.
+func marginFlex(fl *Flex, size image.Point, data ...LayoutData) []*widget.Node {
+	var children []*widget.Node
+	for _, d := range data {
+		c := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(20)).Node
+		c.LayoutData = d
+		children = append(children, c)
+		fl.AppendChild(c)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rectangle{Max: size}
+	fl.Class.Layout(&fl.Node, nil)
+	return children
+}
+
+func TestMargin(t *testing.T) {
+	grow := LayoutData{Grow: 1}
+	shrink := LayoutData{}
+	tests := []struct {
+		name string
+		fl   Flex
+		data []LayoutData
+		want []image.Rectangle
+	}{{
+		name: "margins take free space",
+		data: []LayoutData{{Grow: 1, Margin: Insets{Right: 10}}, grow},
+		want: []image.Rectangle{image.Rect(0, 0, 45, 20), image.Rect(55, 0, 100, 20)},
+	}, {
+		name: "negative margins add free space",
+		data: []LayoutData{shrink, {Margin: Insets{Left: -20}}, shrink},
+		want: []image.Rectangle{image.Rect(0, 0, 40, 20), image.Rect(20, 0, 60, 20), image.Rect(60, 0, 100, 20)},
+	}, {
+		name: "limits clamp the item, not its margin",
+		data: []LayoutData{{Grow: 1, MaxSize: &image.Point{30, 100}, Margin: Insets{Left: 5, Right: 5}}, grow},
+		want: []image.Rectangle{image.Rect(5, 0, 35, 20), image.Rect(40, 0, 100, 20)},
+	}, {
+		name: "justify end",
+		fl:   Flex{Justify: JustifyEnd},
+		data: []LayoutData{{Margin: Insets{Right: 10}}},
+		want: []image.Rectangle{image.Rect(50, 0, 90, 20)},
+	}, {
+		name: "row reverse",
+		fl:   Flex{Direction: RowReverse},
+		data: []LayoutData{{Margin: Insets{Right: 10}}, {Margin: Insets{Right: -30}}},
+		want: []image.Rectangle{image.Rect(50, 0, 90, 20), image.Rect(40, 0, 80, 20)},
+	}, {
+		name: "stretch inside cross margins",
+		fl:   Flex{AlignItem: AlignItemStretch},
+		data: []LayoutData{{Margin: Insets{Top: 5, Bottom: 10}}},
+		want: []image.Rectangle{image.Rect(0, 5, 40, 40)},
+	}, {
+		name: "center",
+		fl:   Flex{AlignItem: AlignItemCenter},
+		data: []LayoutData{{Margin: Insets{Top: 10}}, {Margin: Insets{Top: -10}}},
+		want: []image.Rectangle{image.Rect(0, 20, 40, 40), image.Rect(40, 10, 80, 30)},
+	}, {
+		name: "column",
+		fl:   Flex{Direction: Column},
+		data: []LayoutData{{Margin: Insets{Bottom: -5, Left: 3}}, shrink},
+		want: []image.Rectangle{image.Rect(3, 0, 43, 20), image.Rect(0, 15, 40, 35)},
+	}}
+	for _, test := range tests {
+		fl := NewFlex()
+		fl.Direction = test.fl.Direction
+		fl.Justify = test.fl.Justify
+		fl.AlignItem = test.fl.AlignItem
+		if fl.AlignItem == AlignItemAuto {
+			fl.AlignItem = AlignItemStart
+		}
+		children := marginFlex(fl, image.Pt(100, 50), test.data...)
+		for i, c := range children {
+			if c.Rect != test.want[i] {
+				t.Errorf("%s: child %d Rect = %v, want %v", test.name, i, c.Rect, test.want[i])
+			}
+		}
+	}
+}
+
+func TestMarginWrap(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignItem = AlignItemStart
+	fl.AlignContent = AlignContentStart
+	children := marginFlex(fl, image.Pt(100, 100),
+		LayoutData{Margin: Insets{Bottom: 10}},
+		LayoutData{Margin: Insets{Left: 10}},
+		LayoutData{})
+	want := []image.Rectangle{
+		image.Rect(0, 0, 40, 20),
+		image.Rect(50, 0, 90, 20),
+		image.Rect(0, 30, 40, 50), // below the first child's margin
+	}
+	for i, c := range children {
+		if c.Rect != want[i] {
+			t.Errorf("child %d Rect = %v, want %v", i, c.Rect, want[i])
+		}
+	}
+}
+
+// TestAvatarPile overlaps children with negative margins, with the
+// first on top.
+func TestAvatarPile(t *testing.T) {
+	fl := NewFlex()
+	fl.AlignItem = AlignItemStart
+	var data []LayoutData
+	for i := 0; i < 4; i++ {
+		d := LayoutData{ZIndex: -i}
+		if i > 0 {
+			d.Margin.Left = -15
+		}
+		data = append(data, d)
+	}
+	children := marginFlex(fl, image.Pt(200, 20), data...)
+
+	if got, want := fl.MeasuredSize, image.Pt(115, 20); got != want {
+		t.Errorf("MeasuredSize = %v, want %v", got, want)
+	}
+	for i, c := range children {
+		if want := image.Rect(25*i, 0, 25*i+40, 20); c.Rect != want {
+			t.Errorf("child %d Rect = %v, want %v", i, c.Rect, want)
+		}
+	}
+	if got := ChildAt(&fl.Node, image.Pt(30, 10)); got != children[0] {
+		t.Errorf("ChildAt the overlap of children 0 and 1 = %v, want child 0", got)
+	}
+	if order := PaintOrder(&fl.Node); order[len(order)-1] != children[0] {
+		t.Error("first child is not painted on top")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Unbounded is a maximum size dimension with no limit.
+const Unbounded = math.MaxInt32
+
+// A ConstrainedMeasurer is a widget.Class whose size depends on the
+// space it is given, such as wrapping text or a wrapping Flex.
+//
+// Measure reports a node's natural size with no constraints. A
+// container that needs to know how big a child would be at a given
+// width or height asks MeasureConstrained instead.
+type ConstrainedMeasurer interface {
+	// MeasureConstrained returns the size n would take if it had to
+	// fit between min and max, inclusive. A dimension of max may be
+	// Unbounded. It is called after Measure, and must not modify n.
+	MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point
+}
+
+// MeasureConstrained returns the size n would take between min and
+// max. If n's Class is a ConstrainedMeasurer it is asked; otherwise
+// n.MeasuredSize is clamped to the bounds.
+func MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	if m, ok := n.Class.(ConstrainedMeasurer); ok {
+		return m.MeasureConstrained(n, t, min, max)
+	}
+	return clampPoint(n.MeasuredSize, min, max)
+}
+
+func clampPoint(p, min, max image.Point) image.Point {
+	if p.X > max.X {
+		p.X = max.X
+	}
+	if p.Y > max.Y {
+		p.Y = max.Y
+	}
+	if p.X < min.X {
+		p.X = min.X
+	}
+	if p.Y < min.Y {
+		p.Y = min.Y
+	}
+	return p
+}
+
+// items returns the flex items of the children of n in a container of
+// the given size. Children that are ConstrainedMeasurers are measured
+// to fit the container, and asked for their cross size once their main
+// size is known.
+func (k *flexClass) items(n *widget.Node, t *widget.Theme, size image.Point) []Item {
+	var items []Item
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		d := k.flex.itemData(c).resolve(k.flex.mainSize(size)).resolveLengths(t, size)
+		it := Item{MeasuredSize: c.MeasuredSize, LayoutData: d}
+		if cm, ok := c.Class.(ConstrainedMeasurer); ok {
+			it.MeasuredSize = cm.MeasureConstrained(c, t, image.Point{}, size)
+			it.CrossSizeFor = k.crossSizeFor(cm, c, t, size)
+		}
+		if b, ok := c.Class.(Baseliner); ok {
+			c := c
+			it.Baseline = func() int { return b.FirstBaseline(c, t) }
+			it.LastBaseline = func(size image.Point) int { return b.LastBaseline(c, t, size) }
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+// crossSizeFor returns an Item.CrossSizeFor that measures c with its
+// main size fixed and its cross size bounded by the container's.
+func (k *flexClass) crossSizeFor(cm ConstrainedMeasurer, c *widget.Node, t *widget.Theme, size image.Point) func(int) int {
+	fl := k.flex
+	return func(mainSize int) int {
+		var min, max image.Point
+		switch fl.Direction {
+		case Row, RowReverse:
+			min, max = image.Point{mainSize, 0}, image.Point{mainSize, size.Y}
+		default:
+			min, max = image.Point{0, mainSize}, image.Point{size.X, mainSize}
+		}
+		return fl.crossSize(cm.MeasureConstrained(c, t, min, max))
+	}
+}
+
+// MeasureConstrained lays out the items of a Flex with the main size
+// of max, or their natural main size if that is Unbounded, and no
+// definite cross size. The result is the extent of the flex lines.
+func (k *flexClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	fl := k.flex
+	// Measure the content inside the SafeArea.
+	in := image.Point{fl.SafeArea.Left + fl.SafeArea.Right, fl.SafeArea.Top + fl.SafeArea.Bottom}
+	outerMax := max
+	max = image.Point{insetMax(max.X, in.X), insetMax(max.Y, in.Y)}
+	items := k.items(n, t, max)
+	if len(items) == 0 {
+		return clampPoint(in, min, outerMax)
+	}
+
+	mainSize := fl.mainSize(max)
+	if mainSize >= Unbounded {
+		// Use the natural main size, leaving no free space. Percentages
+		// of the unbounded main size are taken as zero.
+		mainGap, _ := fl.gaps(t)
+		natural := mainGap * float64(len(items)-1)
+		for _, it := range fl.resolveBasisClamps(image.Point{}, items, t) {
+			margin, _ := fl.margins(it.LayoutData)
+			natural += fl.clampMain(it.LayoutData, float64(fl.flexBaseSize(it))) + margin[0] + margin[1]
+		}
+		mainSize = int(math.Ceil(math.Max(natural, 0)))
+	}
+	var size image.Point
+	switch fl.Direction {
+	case Row, RowReverse:
+		size = image.Point{mainSize, -1}
+	default:
+		size = image.Point{-1, mainSize}
+	}
+	_, lines := fl.solve(size, items, t)
+
+	mainGap, crossGap := fl.gaps(t)
+	var used, cross float64
+	if fl.MaxLines > 0 && len(lines) > fl.MaxLines {
+		lines = lines[:fl.MaxLines]
+	}
+	for i, line := range lines {
+		lineMain := mainGap * float64(len(line.child)-1)
+		for _, child := range line.child {
+			lineMain += child.mainSize + child.mainMargin()
+		}
+		if lineMain > used {
+			used = lineMain
+		}
+		if i > 0 {
+			cross += crossGap
+		}
+		cross += line.crossSize
+	}
+	pinMain, pinCross := fl.pinnedExtent(items, fl.mainSize(max), t)
+	used += pinMain
+	if pinCross > cross {
+		cross = pinCross
+	}
+	var p image.Point
+	switch fl.Direction {
+	case Row, RowReverse:
+		p = image.Point{int(math.Ceil(used)), int(math.Ceil(cross))}
+	default:
+		p = image.Point{int(math.Ceil(cross)), int(math.Ceil(used))}
+	}
+	return clampPoint(p.Add(in), min, outerMax)
+}
+
+// insetMax reduces a maximum size dimension by inset.
+func insetMax(max, inset int) int {
+	if max >= Unbounded {
+		return max
+	}
+	if max -= inset; max < 0 {
+		max = 0
+	}
+	return max
+}
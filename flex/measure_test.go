@@ -0,0 +1,156 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func wrappingRow(n int) *Flex {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	for i := 0; i < n; i++ {
+		fl.AppendChild(widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(10)).Node)
+	}
+	return fl
+}
+
+func TestMeasureConstrained(t *testing.T) {
+	fl := wrappingRow(4)
+	fl.Class.Measure(&fl.Node, nil)
+	if want := image.Pt(160, 10); fl.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", fl.MeasuredSize, want)
+	}
+
+	tests := []struct {
+		min, max image.Point
+		want     image.Point
+	}{
+		{image.Point{}, image.Pt(Unbounded, Unbounded), image.Pt(160, 10)},
+		{image.Point{}, image.Pt(100, Unbounded), image.Pt(80, 20)},
+		{image.Point{}, image.Pt(50, Unbounded), image.Pt(40, 40)},
+		{image.Pt(60, 0), image.Pt(100, 15), image.Pt(80, 15)},
+		{image.Pt(90, 30), image.Pt(100, Unbounded), image.Pt(90, 30)},
+	}
+	for _, test := range tests {
+		got := MeasureConstrained(&fl.Node, nil, test.min, test.max)
+		if got != test.want {
+			t.Errorf("MeasureConstrained(%v, %v) = %v, want %v", test.min, test.max, got, test.want)
+		}
+	}
+
+	// Leaves are clamped.
+	u := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(10)).Node
+	u.Class.Measure(u, nil)
+	if got, want := MeasureConstrained(u, nil, image.Pt(0, 20), image.Pt(30, 30)), image.Pt(30, 20); got != want {
+		t.Errorf("MeasureConstrained(uniform) = %v, want %v", got, want)
+	}
+}
+
+// TestMeasurePercentLengths checks that percentages of the unbounded
+// space Measure lays out in are zero, or no maximum, as in CSS.
+func TestMeasurePercentLengths(t *testing.T) {
+	fl := wrappingRow(2)
+	fl.FirstChild.LayoutData = LayoutData{
+		MinLength: &Size{Width: Length{Kind: LengthPercent, Percent: 50}},
+		MaxLength: &Size{Height: Length{Kind: LengthPercent, Percent: 50}},
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	if want := image.Pt(80, 10); fl.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", fl.MeasuredSize, want)
+	}
+
+	fl.Rect = image.Rect(0, 0, 200, 10)
+	fl.Class.Layout(&fl.Node, nil)
+	if want := image.Rect(0, 0, 100, 5); fl.FirstChild.Rect != want {
+		t.Errorf("laid out Rect=%v, want %v", fl.FirstChild.Rect, want)
+	}
+}
+
+func TestNestedWrapping(t *testing.T) {
+	col := NewFlex()
+	col.Direction = Column
+	row := wrappingRow(4)
+	after := widget.NewUniform(color.Black, unit.Pixels(100), unit.Pixels(10)).Node
+	col.AppendChild(&row.Node)
+	col.AppendChild(after)
+
+	col.Class.Measure(&col.Node, nil)
+	col.Rect = image.Rect(0, 0, 100, 200)
+	col.Class.Layout(&col.Node, nil)
+
+	if want := image.Rect(0, 0, 80, 20); row.Rect != want {
+		t.Errorf("row.Rect=%v, want %v", row.Rect, want)
+	}
+	if want := image.Rect(0, 20, 100, 30); after.Rect != want {
+		t.Errorf("after.Rect=%v, want %v", after.Rect, want)
+	}
+}
+
+// areaClass is a leaf that keeps a constant area, like wrapping text.
+type areaClass struct {
+	widget.LeafClassEmbed
+	area, width int
+}
+
+func (k *areaClass) Measure(n *widget.Node, t *widget.Theme) {
+	n.MeasuredSize = image.Pt(k.width, k.area/k.width)
+}
+
+func (k *areaClass) MeasureConstrained(n *widget.Node, t *widget.Theme, min, max image.Point) image.Point {
+	w := k.width
+	if w > max.X {
+		w = max.X
+	}
+	if w < min.X {
+		w = min.X
+	}
+	return clampPoint(image.Pt(w, (k.area+w-1)/w), min, max)
+}
+
+func TestHeightForWidth(t *testing.T) {
+	fl := NewFlex()
+	fl.Direction = Column
+	text := &widget.Node{Class: &areaClass{area: 2000, width: 200}}
+	below := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	fl.AppendChild(text)
+	fl.AppendChild(below)
+
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 50, 300)
+	fl.Class.Layout(&fl.Node, nil)
+	if want := image.Rect(0, 0, 50, 40); text.Rect != want {
+		t.Errorf("text.Rect=%v, want %v", text.Rect, want)
+	}
+	if want := image.Rect(0, 40, 10, 50); below.Rect != want {
+		t.Errorf("below.Rect=%v, want %v", below.Rect, want)
+	}
+
+	// In a Row, the text shrinks from 110px to make room for its
+	// sibling and grows taller.
+	fl.Direction = Row
+	fl.Rect = image.Rect(0, 0, 110, 300)
+	fl.Class.Layout(&fl.Node, nil)
+	if want := image.Rect(0, 0, 101, 20); text.Rect != want {
+		t.Errorf("row text.Rect=%v, want %v", text.Rect, want)
+	}
+}
+
+func TestCrossSizeFor(t *testing.T) {
+	fl := NewFlex()
+	items := []Item{{
+		MeasuredSize: image.Pt(200, 10),
+		CrossSizeFor: func(main int) int { return 2000 / main },
+	}}
+	got := fl.Solve(image.Pt(100, 100), items)
+	if want := image.Rect(0, 0, 100, 20); got[0] != want {
+		t.Errorf("Solve = %v, want %v", got[0], want)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// ChildChange describes a child of a Flex whose Rect was changed by a
+// layout.
+type ChildChange struct {
+	Node     *widget.Node
+	Old, New image.Rectangle
+}
+
+type layoutObserver struct {
+	f func(changes []ChildChange)
+}
+
+// NotifyLayout arranges for f to be called at the end of every Layout
+// of fl that changed the Rect of any of its children, with those
+// children in sibling order. Children are laid out before f is called,
+// so f may inspect their descendants too.
+//
+// NotifyLayout returns a function that cancels the subscription.
+func (fl *Flex) NotifyLayout(f func(changes []ChildChange)) (cancel func()) {
+	k := fl.Class.(*flexClass)
+	o := &layoutObserver{f: f}
+	k.observers = append(k.observers, o)
+	return func() {
+		for i, x := range k.observers {
+			if x == o {
+				k.observers = append(k.observers[:i:i], k.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (k *flexClass) notify(changes []ChildChange) {
+	// Copy the observers, which may cancel themselves.
+	for _, o := range append([]*layoutObserver(nil), k.observers...) {
+		o.f(changes)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestNotifyLayout(t *testing.T) {
+	fl := NewFlex()
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 100, 10)
+
+	var got [][]ChildChange
+	cancel := fl.NotifyLayout(func(changes []ChildChange) {
+		got = append(got, changes)
+	})
+
+	fl.Class.Layout(&fl.Node, nil)
+	fl.Class.Layout(&fl.Node, nil) // no changes
+	fl.Justify = JustifyEnd
+	fl.Class.Layout(&fl.Node, nil)
+
+	want := [][]ChildChange{
+		{
+			{Node: a, New: image.Rect(0, 0, 10, 10)},
+			{Node: b, New: image.Rect(10, 0, 20, 10)},
+		},
+		{
+			{Node: a, Old: image.Rect(0, 0, 10, 10), New: image.Rect(80, 0, 90, 10)},
+			{Node: b, Old: image.Rect(10, 0, 20, 10), New: image.Rect(90, 0, 100, 10)},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes:\n%v\nwant:\n%v", got, want)
+	}
+
+	cancel()
+	fl.Justify = JustifyStart
+	fl.Class.Layout(&fl.Node, nil)
+	if len(got) != 2 {
+		t.Errorf("observer called after cancel")
+	}
+}
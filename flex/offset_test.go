@@ -0,0 +1,78 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestOffset(t *testing.T) {
+	fl := NewFlex()
+	fl.Justify = JustifyCenter
+	var nodes []*widget.Node
+	for i := 0; i < 3; i++ {
+		n := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+		fl.AppendChild(n)
+		nodes = append(nodes, n)
+	}
+	nodes[1].LayoutData = LayoutData{Offset: image.Pt(3, -2)}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 50, 10)
+	fl.Class.Layout(&fl.Node, nil)
+
+	want := []image.Rectangle{
+		image.Rect(10, 0, 20, 10),
+		image.Rect(23, -2, 33, 8),
+		image.Rect(30, 0, 40, 10),
+	}
+	for i, n := range nodes {
+		if n.Rect != want[i] {
+			t.Errorf("child %d: Rect = %v, want %v", i, n.Rect, want[i])
+		}
+	}
+	if got := Explain(fl, nodes[1]).Rect; got != want[1] {
+		t.Errorf("Explain Rect = %v, want %v", got, want[1])
+	}
+	if got := fl.MeasuredSize; got != size(30, 10) {
+		t.Errorf("MeasuredSize = %v, want (30,10) regardless of Offset", got)
+	}
+}
+
+// TestOffsetBreakpoint sets Offset and FullBleed by Breakpoints.
+func TestOffsetBreakpoint(t *testing.T) {
+	fl := NewFlex()
+	fl.SafeArea = Insets{Top: 5, Left: 5}
+	n := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	n.LayoutData = LayoutData{
+		Breakpoints: &[]Breakpoint{
+			{MinMainSize: 40, LayoutData: LayoutData{Offset: image.Pt(2, 3)}},
+			{MinMainSize: 80, LayoutData: LayoutData{FullBleed: true}},
+		},
+	}
+	fl.AppendChild(n)
+	fl.Class.Measure(&fl.Node, nil)
+	for _, test := range []struct {
+		width int
+		want  image.Rectangle
+	}{
+		{30, image.Rect(5, 5, 15, 15)},
+		{50, image.Rect(7, 8, 17, 18)},
+		{100, image.Rect(0, 0, 15, 15)},
+	} {
+		fl.Rect = image.Rect(0, 0, test.width, 20)
+		fl.Class.Layout(&fl.Node, nil)
+		if n.Rect != test.want {
+			t.Errorf("at width %d: Rect = %v, want %v", test.width, n.Rect, test.want)
+		}
+		if got := Explain(fl, n).Rect; got != test.want {
+			t.Errorf("at width %d: Explain Rect = %v, want %v", test.width, got, test.want)
+		}
+	}
+}
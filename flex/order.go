@@ -0,0 +1,121 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import "golang.org/x/exp/shiny/widget"
+
+// LogicalOrder returns the children of fl in sibling order, the order
+// in which they were added.
+func (fl *Flex) LogicalOrder() []*widget.Node {
+	var children []*widget.Node
+	for c := fl.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
+
+// VisualOrder returns the children of fl in the order they appear on
+// screen after the last Layout: line by line from the top (for a Row)
+// or left (for a Column) of the container, and within each line from
+// left to right or top to bottom.
+//
+// Reversed directions and WrapReverse make the visual order differ
+// from the LogicalOrder. Focus traversal and screen readers should
+// follow the visual order.
+//
+// Pinned children are ordered by where they are placed too: before
+// the lines if pinned to the left or top edge, and after them if pinned
+// to the right or bottom one.
+//
+// Children hidden by MaxLines are omitted. If fl has not been laid
+// out since its children changed, its children are treated as a single
+// line.
+func (fl *Flex) VisualOrder() []*widget.Node {
+	children := fl.LogicalOrder()
+	var lines []lineInfo
+	var hidden []*widget.Node
+	if k, ok := fl.Class.(*flexClass); ok {
+		lines = k.lines
+		if fl.LineOverflow == LineOverflowHide {
+			hidden = k.overflowed
+		}
+	}
+	reverseMain := fl.Direction == RowReverse || fl.Direction == ColumnReverse
+
+	// Split the children into those pinned to the start and end, and
+	// count those that are laid out in lines or hidden.
+	var start, end []*widget.Node
+	placed, stale := len(hidden), false
+	for _, c := range children {
+		switch fl.resolvedData(c).Pin {
+		case PinStart:
+			start = append(start, c)
+		case PinEnd:
+			end = append(end, c)
+		}
+	}
+	for _, line := range lines {
+		placed += len(line.children)
+		for _, i := range line.children {
+			if i >= len(children) {
+				stale = true
+			}
+		}
+	}
+	if len(lines) == 0 || stale || placed+len(start)+len(end) != len(children) {
+		start, end = nil, nil
+		all := make([]int, len(children))
+		for i := range all {
+			all[i] = i
+		}
+		lines = []lineInfo{{children: all}}
+	}
+
+	order := make([]*widget.Node, 0, len(children))
+	if reverseMain {
+		order = appendReversed(order, end)
+	} else {
+		order = append(order, start...)
+	}
+	for i := range lines {
+		line := lines[i]
+		if fl.Wrap == WrapReverse {
+			line = lines[len(lines)-1-i]
+		}
+		for j := range line.children {
+			if reverseMain {
+				j = len(line.children) - 1 - j
+			}
+			order = append(order, children[line.children[j]])
+		}
+	}
+	if reverseMain {
+		order = appendReversed(order, start)
+	} else {
+		order = append(order, end...)
+	}
+	return order
+}
+
+func appendReversed(dst, src []*widget.Node) []*widget.Node {
+	for i := len(src) - 1; i >= 0; i-- {
+		dst = append(dst, src[i])
+	}
+	return dst
+}
+
+// VisualChildren returns the children of n in the order they appear on
+// screen: the VisualOrder of a Flex, and the sibling order of any
+// other node.
+func VisualChildren(n *widget.Node) []*widget.Node {
+	if k, ok := n.Class.(*flexClass); ok {
+		return k.flex.VisualOrder()
+	}
+	var children []*widget.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
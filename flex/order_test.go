@@ -0,0 +1,57 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestVisualOrder(t *testing.T) {
+	tests := []struct {
+		direction Direction
+		wrap      FlexWrap
+		want      string
+	}{
+		{Row, NoWrap, "abcde"},
+		{RowReverse, NoWrap, "edcba"},
+		{Row, Wrap, "abcde"},
+		{Row, WrapReverse, "deabc"},
+		{RowReverse, Wrap, "cbaed"},
+		{ColumnReverse, WrapReverse, "edcba"},
+	}
+	for _, test := range tests {
+		fl := NewFlex()
+		fl.Direction = test.direction
+		fl.Wrap = test.wrap
+		names := make(map[*widget.Node]string)
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			n := widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(30)).Node
+			names[n] = name
+			fl.AppendChild(n)
+		}
+		fl.Class.Measure(&fl.Node, nil)
+		fl.Rect = image.Rectangle{Max: image.Pt(100, 100)}
+		fl.Class.Layout(&fl.Node, nil)
+
+		got, logical := "", ""
+		for _, n := range fl.VisualOrder() {
+			got += names[n]
+		}
+		for _, n := range fl.LogicalOrder() {
+			logical += names[n]
+		}
+		if got != test.want {
+			t.Errorf("%v %v: VisualOrder=%s, want %s", test.direction, test.wrap, got, test.want)
+		}
+		if logical != "abcde" {
+			t.Errorf("%v %v: LogicalOrder=%s", test.direction, test.wrap, logical)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// LineOverflow is what a Flex does with the children beyond its
+// MaxLines.
+type LineOverflow int8
+
+// Possible values of LineOverflow.
+const (
+	// LineOverflowHide leaves the overflowed children out of the
+	// layout. They are given an empty Rect and are not painted.
+	LineOverflowHide LineOverflow = iota
+
+	// LineOverflowClip lays out the overflowed children on further
+	// lines as usual, and clips the painting of all children to the
+	// first MaxLines lines.
+	LineOverflowClip
+)
+
+// Overflow returns the children of fl that did not fit in its MaxLines
+// at the last Layout, in sibling order. An app can use them to offer
+// the rest of the children elsewhere, such as behind a "+7 more"
+// button. It returns nil if all the children fit.
+func (fl *Flex) Overflow() []*widget.Node {
+	k, ok := fl.Class.(*flexClass)
+	if !ok || len(k.overflowed) == 0 {
+		return nil
+	}
+	return append([]*widget.Node(nil), k.overflowed...)
+}
+
+// ClipRect reports whether the painting of the children of n is
+// clipped, as by a Flex with LineOverflowClip, and if so returns the
+// rectangle they are clipped to, relative to n's Rect.Min.
+func ClipRect(n *widget.Node) (r image.Rectangle, ok bool) {
+	k, ok := n.Class.(*flexClass)
+	if !ok || !k.clipped {
+		return image.Rectangle{}, false
+	}
+	return k.clip, true
+}
+
+// overflow records the children of n beyond MaxLines after a Layout
+// that placed count children, inside the content box. Pinned children
+// are in no line, and are never overflowed.
+func (k *flexClass) overflow(n *widget.Node, content image.Rectangle, count int) {
+	fl := k.flex
+	k.overflowed, k.clipped = k.overflowed[:0], false
+	if fl.MaxLines <= 0 || len(k.lines) == 0 {
+		return
+	}
+	visible := k.lines
+	if len(visible) > fl.MaxLines {
+		visible = visible[:fl.MaxLines]
+	}
+	shown := make([]bool, count)
+	for _, line := range visible {
+		for _, i := range line.children {
+			shown[i] = true
+		}
+	}
+	i := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !shown[i] && fl.resolvedData(c).Pin == PinNone {
+			k.overflowed = append(k.overflowed, c)
+		}
+		i++
+	}
+	if len(k.overflowed) == 0 {
+		return
+	}
+	if fl.LineOverflow != LineOverflowClip {
+		return
+	}
+
+	// Clip the cross axis to the visible lines.
+	lo, hi := visible[0].crossOffset, visible[0].crossOffset+visible[0].crossSize
+	for _, line := range visible[1:] {
+		if line.crossOffset < lo {
+			lo = line.crossOffset
+		}
+		if end := line.crossOffset + line.crossSize; end > hi {
+			hi = end
+		}
+	}
+	k.clip, k.clipped = content, true
+	switch fl.Direction {
+	case Row, RowReverse:
+		k.clip.Min.Y, k.clip.Max.Y = int(math.Ceil(lo-roundingSlop)), int(math.Ceil(hi-roundingSlop))
+	default:
+		k.clip.Min.X, k.clip.Max.X = int(math.Ceil(lo-roundingSlop)), int(math.Ceil(hi-roundingSlop))
+	}
+}
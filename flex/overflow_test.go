@@ -0,0 +1,139 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// chips returns a wrapping Row of 7 children, 30x20, in a container 100
+// wide: 3 children to a line.
+func chips(overflow LineOverflow) (*Flex, []*widget.Node) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignContent = AlignContentStart
+	fl.MaxLines = 2
+	fl.LineOverflow = overflow
+	var children []*widget.Node
+	for i := 0; i < 7; i++ {
+		c := widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(20)).Node
+		children = append(children, c)
+		fl.AppendChild(c)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 100, 100)
+	fl.Class.Layout(&fl.Node, nil)
+	return fl, children
+}
+
+func TestMaxLinesHide(t *testing.T) {
+	fl, children := chips(LineOverflowHide)
+
+	if got := fl.Overflow(); len(got) != 1 || got[0] != children[6] {
+		t.Errorf("Overflow() = %v, want the last child", got)
+	}
+	if r := children[5].Rect; r != image.Rect(60, 20, 90, 40) {
+		t.Errorf("children[5].Rect = %v", r)
+	}
+	if r := children[6].Rect; !r.Empty() {
+		t.Errorf("hidden child Rect = %v, want empty", r)
+	}
+	for _, c := range PaintOrder(&fl.Node) {
+		if c == children[6] {
+			t.Error("PaintOrder includes the hidden child")
+		}
+	}
+	if got := len(fl.VisualOrder()); got != 6 {
+		t.Errorf("VisualOrder has %d children, want 6", got)
+	}
+	if _, ok := ClipRect(&fl.Node); ok {
+		t.Error("ClipRect reports clipping for LineOverflowHide")
+	}
+
+	fl.Class.Measure(&fl.Node, nil)
+	if m := MeasureConstrained(&fl.Node, nil, image.Point{}, image.Point{100, Unbounded}).Y; m != 40 {
+		t.Errorf("measured height %d, want 40", m)
+	}
+}
+
+func TestMaxLinesClip(t *testing.T) {
+	fl, children := chips(LineOverflowClip)
+
+	if got := fl.Overflow(); len(got) != 1 || got[0] != children[6] {
+		t.Errorf("Overflow() = %v, want the last child", got)
+	}
+	if r := children[6].Rect; r != image.Rect(0, 40, 30, 60) {
+		t.Errorf("clipped child Rect = %v", r)
+	}
+	r, ok := ClipRect(&fl.Node)
+	if !ok || r != image.Rect(0, 0, 100, 40) {
+		t.Errorf("ClipRect = %v, %t", r, ok)
+	}
+	if m := MeasureConstrained(&fl.Node, nil, image.Point{}, image.Point{100, Unbounded}).Y; m != 40 {
+		t.Errorf("measured height %d, want 40", m)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	fl.Class.Paint(&fl.Node, nil, dst, image.Point{})
+	if got := dst.RGBAAt(5, 45); got.A != 0 {
+		t.Errorf("pixel below the clip is %v, want transparent", got)
+	}
+	if got := dst.RGBAAt(5, 25); got.A == 0 {
+		t.Errorf("pixel of the second line is transparent")
+	}
+
+	fl.MaxLines = 0
+	fl.Class.Layout(&fl.Node, nil)
+	if got := fl.Overflow(); got != nil {
+		t.Errorf("Overflow() = %v after removing MaxLines", got)
+	}
+	if _, ok := ClipRect(&fl.Node); ok {
+		t.Error("ClipRect reports clipping after removing MaxLines")
+	}
+}
+
+// TestMaxLinesPin checks that a pinned child, which is in no line, is
+// not hidden by MaxLines.
+func TestMaxLinesPin(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.MaxLines = 1
+	var children []*widget.Node
+	for i := 0; i < 4; i++ {
+		c := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(10)).Node
+		children = append(children, c)
+		fl.AppendChild(c)
+	}
+	children[3].LayoutData = LayoutData{Pin: PinEnd}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 100, 100)
+	fl.Class.Layout(&fl.Node, nil)
+
+	if r := children[3].Rect; r != image.Rect(60, 0, 100, 10) {
+		t.Errorf("pinned child Rect = %v", r)
+	}
+	if got := fl.Overflow(); len(got) != 2 || got[0] != children[1] || got[1] != children[2] {
+		t.Errorf("Overflow() = %v, want the second and third children", got)
+	}
+	if got := PaintOrder(&fl.Node); len(got) != 2 || got[0] != children[0] || got[1] != children[3] {
+		t.Errorf("PaintOrder = %v, want the first and pinned children", got)
+	}
+	if got := fl.VisualOrder(); len(got) != 2 || got[0] != children[0] || got[1] != children[3] {
+		t.Errorf("VisualOrder = %v, want the first and pinned children", got)
+	}
+
+	fl.Direction, fl.Wrap = RowReverse, NoWrap
+	fl.MaxLines = 0
+	fl.Class.Layout(&fl.Node, nil)
+	want := []*widget.Node{children[3], children[2], children[1], children[0]}
+	if got := fl.VisualOrder(); len(got) != 4 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Errorf("RowReverse VisualOrder = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Placement is the side of its anchor that a Popup is placed on.
+type Placement int8
+
+// Possible values of Placement.
+const (
+	PlaceBelow Placement = iota
+	PlaceAbove
+	PlaceRight
+	PlaceLeft
+)
+
+// PopupAlign aligns a Popup with its anchor along the side it is
+// placed on.
+type PopupAlign int8
+
+// Possible values of PopupAlign.
+const (
+	PopupAlignStart  PopupAlign = iota // left or top edges aligned
+	PopupAlignCenter                   // centered on the anchor
+	PopupAlignEnd                      // right or bottom edges aligned
+)
+
+// A Popup is a node shown above the content of an Overlay, such as a
+// menu, tooltip or dropdown, placed next to its Anchor.
+type Popup struct {
+	Node *widget.Node
+
+	// Anchor is a node of the Overlay's content that the popup is
+	// placed next to, after the content is laid out. If Anchor is
+	// nil or not in the content, the popup is placed at the top-left
+	// of the Overlay.
+	Anchor *widget.Node
+
+	Placement Placement
+	Align     PopupAlign
+
+	// Gap is the space between the anchor and the popup, in pixels.
+	Gap int
+}
+
+// Overlay is a container widget for the root of a window. Its first
+// child, the content, fills it; its other children are the nodes of
+// Popups, laid out above the content next to their anchors.
+//
+// A popup is given its MeasuredSize, limited to the Overlay. If it does
+// not fit on the side of its anchor given by its Placement, it is
+// flipped to the opposite side if there is more room there, and then
+// shifted along that side to stay within the Overlay.
+type Overlay struct {
+	widget.Node
+
+	popups []*Popup
+}
+
+// NewOverlay returns a new Overlay showing content.
+func NewOverlay(content *widget.Node) *Overlay {
+	o := &Overlay{}
+	o.Node.Class = &overlayClass{overlay: o}
+	o.AppendChild(content)
+	return o
+}
+
+// Show adds p above the content, and above any other popups.
+func (o *Overlay) Show(p *Popup) {
+	o.popups = append(o.popups, p)
+	o.AppendChild(p.Node)
+}
+
+// Hide removes the popup whose node is n.
+func (o *Overlay) Hide(n *widget.Node) {
+	for i, p := range o.popups {
+		if p.Node == n {
+			o.popups = append(o.popups[:i], o.popups[i+1:]...)
+			o.RemoveChild(n)
+			return
+		}
+	}
+}
+
+// Popups returns the popups shown, from bottom to top.
+func (o *Overlay) Popups() []*Popup {
+	return append([]*Popup(nil), o.popups...)
+}
+
+// RectIn returns the Rect of n in the coordinates of the Rect of its
+// ancestor a, that is, relative to the origin a's children are laid
+// out in. It reports false if a is not an ancestor of n.
+func RectIn(n, a *widget.Node) (image.Rectangle, bool) {
+	r := n.Rect
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == a {
+			return r, true
+		}
+		r = r.Add(p.Rect.Min)
+	}
+	return image.Rectangle{}, false
+}
+
+type overlayClass struct {
+	widget.ContainerClassEmbed
+
+	overlay *Overlay
+}
+
+func (k *overlayClass) Measure(n *widget.Node, t *widget.Theme) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		c.Class.Measure(c, t)
+	}
+	if c := n.FirstChild; c != nil {
+		n.MeasuredSize = c.MeasuredSize
+	}
+}
+
+func (k *overlayClass) Layout(n *widget.Node, t *widget.Theme) {
+	bounds := image.Rectangle{Max: n.Rect.Size()}
+	content := n.FirstChild
+	if content == nil {
+		return
+	}
+	content.Rect = bounds
+	content.Class.Layout(content, t)
+
+	for _, p := range k.overlay.popups {
+		var anchor image.Rectangle
+		if p.Anchor != nil {
+			anchor, _ = RectIn(p.Anchor, n)
+		}
+		p.Node.Rect = place(anchor, p.Node.MeasuredSize, bounds, p.Placement, p.Align, p.Gap)
+		p.Node.Class.Layout(p.Node, t)
+	}
+}
+
+func (k *overlayClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	origin = origin.Add(n.Rect.Min)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		c.Class.Paint(c, t, dst, origin)
+	}
+}
+
+// place returns the Rect of a popup of the given size next to anchor,
+// within bounds.
+func place(anchor image.Rectangle, size image.Point, bounds image.Rectangle, pl Placement, align PopupAlign, gap int) image.Rectangle {
+	if size.X > bounds.Dx() {
+		size.X = bounds.Dx()
+	}
+	if size.Y > bounds.Dy() {
+		size.Y = bounds.Dy()
+	}
+
+	// Work along the axis the popup is placed on, as if PlaceBelow
+	// or PlaceAbove, transposing for PlaceRight and PlaceLeft.
+	vertical := pl == PlaceBelow || pl == PlaceAbove
+	if !vertical {
+		anchor, bounds, size = transpose(anchor), transpose(bounds), image.Point{size.Y, size.X}
+	}
+	after := pl == PlaceBelow || pl == PlaceRight
+
+	// Flip to the other side if there is more room there.
+	roomAfter := bounds.Max.Y - anchor.Max.Y - gap
+	roomBefore := anchor.Min.Y - gap - bounds.Min.Y
+	if after && size.Y > roomAfter && roomBefore > roomAfter {
+		after = false
+	} else if !after && size.Y > roomBefore && roomAfter > roomBefore {
+		after = true
+	}
+	var r image.Rectangle
+	if after {
+		r.Min.Y = anchor.Max.Y + gap
+	} else {
+		r.Min.Y = anchor.Min.Y - gap - size.Y
+	}
+
+	switch align {
+	case PopupAlignStart:
+		r.Min.X = anchor.Min.X
+	case PopupAlignCenter:
+		r.Min.X = anchor.Min.X + (anchor.Dx()-size.X)/2
+	case PopupAlignEnd:
+		r.Min.X = anchor.Max.X - size.X
+	}
+	r.Max = r.Min.Add(size)
+
+	// Shift to stay within bounds.
+	r = r.Add(shift(r, bounds))
+	if !vertical {
+		r = transpose(r)
+	}
+	return r
+}
+
+// shift returns the smallest move of r into bounds, which must be at
+// least as large as r.
+func shift(r, bounds image.Rectangle) image.Point {
+	var d image.Point
+	if r.Min.X < bounds.Min.X {
+		d.X = bounds.Min.X - r.Min.X
+	} else if r.Max.X > bounds.Max.X {
+		d.X = bounds.Max.X - r.Max.X
+	}
+	if r.Min.Y < bounds.Min.Y {
+		d.Y = bounds.Min.Y - r.Min.Y
+	} else if r.Max.Y > bounds.Max.Y {
+		d.Y = bounds.Max.Y - r.Max.Y
+	}
+	return d
+}
+
+func transpose(r image.Rectangle) image.Rectangle {
+	return image.Rect(r.Min.Y, r.Min.X, r.Max.Y, r.Max.X)
+}
@@ -0,0 +1,88 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestPlace(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 100)
+	size := image.Pt(30, 20)
+	tests := []struct {
+		anchor image.Rectangle
+		pl     Placement
+		align  PopupAlign
+		want   image.Rectangle
+	}{
+		{image.Rect(10, 10, 50, 20), PlaceBelow, PopupAlignStart, image.Rect(10, 22, 40, 42)},
+		{image.Rect(10, 10, 50, 20), PlaceBelow, PopupAlignCenter, image.Rect(15, 22, 45, 42)},
+		{image.Rect(10, 10, 50, 20), PlaceBelow, PopupAlignEnd, image.Rect(20, 22, 50, 42)},
+		{image.Rect(10, 50, 50, 60), PlaceAbove, PopupAlignStart, image.Rect(10, 28, 40, 48)},
+		// No room above: flip below.
+		{image.Rect(10, 10, 50, 20), PlaceAbove, PopupAlignStart, image.Rect(10, 22, 40, 42)},
+		// No room below: flip above.
+		{image.Rect(10, 85, 50, 95), PlaceBelow, PopupAlignStart, image.Rect(10, 63, 40, 83)},
+		// Off the right edge: shift left.
+		{image.Rect(90, 10, 100, 20), PlaceBelow, PopupAlignStart, image.Rect(70, 22, 100, 42)},
+		{image.Rect(10, 10, 20, 20), PlaceRight, PopupAlignStart, image.Rect(22, 10, 52, 30)},
+		// No room on the right: flip left.
+		{image.Rect(80, 10, 90, 20), PlaceRight, PopupAlignStart, image.Rect(48, 10, 78, 30)},
+		// Left of an anchor at the bottom: shift up.
+		{image.Rect(50, 90, 60, 100), PlaceLeft, PopupAlignStart, image.Rect(18, 80, 48, 100)},
+	}
+	for _, test := range tests {
+		got := place(test.anchor, size, bounds, test.pl, test.align, 2)
+		if got != test.want {
+			t.Errorf("place(%v, %d, %d) = %v, want %v", test.anchor, test.pl, test.align, got, test.want)
+		}
+	}
+
+	// Too big for the bounds: limited to them.
+	got := place(image.Rect(10, 10, 20, 20), image.Pt(200, 20), bounds, PlaceBelow, PopupAlignStart, 0)
+	if want := image.Rect(0, 20, 100, 40); got != want {
+		t.Errorf("oversized popup at %v, want %v", got, want)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	content := NewFlex()
+	content.SafeArea = Insets{Top: 10, Left: 10}
+	button := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(10)).Node
+	content.AppendChild(button)
+
+	o := NewOverlay(&content.Node)
+	menu := widget.NewUniform(color.White, unit.Pixels(40), unit.Pixels(30)).Node
+	o.Show(&Popup{Node: menu, Anchor: button, Placement: PlaceBelow})
+
+	o.Class.Measure(&o.Node, nil)
+	o.Rect = image.Rect(0, 0, 200, 200)
+	o.Class.Layout(&o.Node, nil)
+
+	if r, ok := RectIn(button, &o.Node); !ok || r != image.Rect(10, 10, 30, 20) {
+		t.Errorf("RectIn(button) = %v, %t", r, ok)
+	}
+	if want := image.Rect(10, 20, 50, 50); menu.Rect != want {
+		t.Errorf("menu Rect=%v, want %v", menu.Rect, want)
+	}
+	dst := image.NewRGBA(o.Rect)
+	o.Class.Paint(&o.Node, nil, dst, image.Point{})
+	if got := dst.RGBAAt(15, 25); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("menu not painted: %v", got)
+	}
+
+	o.Hide(menu)
+	if len(o.Popups()) != 0 || menu.Parent != nil {
+		t.Error("Hide left the popup in the overlay")
+	}
+	if _, ok := RectIn(button, menu); ok {
+		t.Error("RectIn reports a non-ancestor")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"sort"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// PaintOrder returns the children of n in the order they are painted,
+// from bottom to top: sorted by the ZIndex of their LayoutData, with
+// pinned children above unpinned ones of equal ZIndex, and otherwise
+// in sibling order. Children of a Flex hidden by its MaxLines are
+// omitted.
+//
+// Layout order is unaffected by ZIndex. Hit testing should visit
+// children in the reverse of PaintOrder, so that the topmost child
+// wins.
+func PaintOrder(n *widget.Node) []*widget.Node {
+	mainSize := 0
+	data := func(c *widget.Node) LayoutData {
+		d, _ := c.LayoutData.(LayoutData)
+		return d
+	}
+	var hidden []*widget.Node
+	if k, ok := n.Class.(*flexClass); ok {
+		mainSize = k.flex.mainSize(n.Rect.Size())
+		data = k.flex.itemData
+		if k.flex.LineOverflow == LineOverflowHide {
+			hidden = k.overflowed
+		}
+	}
+	var children []*widget.Node
+	var z []int
+	var pinned []bool
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if len(hidden) > 0 && c == hidden[0] {
+			hidden = hidden[1:]
+			continue
+		}
+		children = append(children, c)
+		d := data(c).resolve(mainSize)
+		z = append(z, d.ZIndex)
+		pinned = append(pinned, d.Pin != PinNone)
+	}
+	sort.Stable(byZIndex{children, z, pinned})
+	return children
+}
+
+type byZIndex struct {
+	n      []*widget.Node
+	z      []int
+	pinned []bool
+}
+
+func (b byZIndex) Len() int { return len(b.n) }
+func (b byZIndex) Less(i, j int) bool {
+	if b.z[i] != b.z[j] {
+		return b.z[i] < b.z[j]
+	}
+	return !b.pinned[i] && b.pinned[j]
+}
+func (b byZIndex) Swap(i, j int) {
+	b.n[i], b.n[j] = b.n[j], b.n[i]
+	b.z[i], b.z[j] = b.z[j], b.z[i]
+	b.pinned[i], b.pinned[j] = b.pinned[j], b.pinned[i]
+}
+
+func (k *flexClass) Paint(n *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	origin = origin.Add(n.Rect.Min)
+	if r, ok := ClipRect(n); ok {
+		if dst, ok = dst.SubImage(r.Add(origin)).(*image.RGBA); !ok {
+			return
+		}
+	}
+	for _, c := range PaintOrder(n) {
+		c.Class.Paint(c, t, dst, origin)
+	}
+}
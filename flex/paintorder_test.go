@@ -0,0 +1,43 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestPaintOrder(t *testing.T) {
+	fl := NewFlex()
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	a := widget.NewUniform(red, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(blue, unit.Pixels(10), unit.Pixels(10)).Node
+	c := widget.NewUniform(blue, unit.Pixels(10), unit.Pixels(10)).Node
+	a.LayoutData = LayoutData{ZIndex: 1}
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.AppendChild(c)
+
+	got := PaintOrder(&fl.Node)
+	if want := []*widget.Node{b, c, a}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("PaintOrder = %v, want %v", got, want)
+	}
+
+	// Overlap a and b; a is painted on top although it comes first.
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 30, 10)
+	fl.Class.Layout(&fl.Node, nil)
+	b.Rect = a.Rect
+	dst := image.NewRGBA(image.Rect(0, 0, 30, 10))
+	fl.Class.Paint(&fl.Node, nil, dst, image.Point{})
+	if got := dst.At(5, 5); got != red {
+		t.Errorf("overlap painted %v, want %v", got, red)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Pin pins a flex item to an edge of its container's main axis.
+type Pin int8
+
+// Possible values of Pin.
+const (
+	PinNone Pin = iota
+
+	// PinStart places the item at the main-start edge of the content
+	// box, and PinEnd at the main-end edge. Pinned items are taken out
+	// of the flow: the other items are laid out in the space between
+	// them, and Justify and Scroll do not move them.
+	PinStart
+	PinEnd
+)
+
+var pinNames = [...]string{
+	PinNone:  "none",
+	PinStart: "start",
+	PinEnd:   "end",
+}
+
+func (p Pin) String() string {
+	return enumName(pinNames[:], "Pin", int(p))
+}
+
+func (p Pin) MarshalText() ([]byte, error) {
+	return marshalEnum(pinNames[:], "Pin", int(p))
+}
+
+func (p *Pin) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(pinNames[:], "Pin", text)
+	*p = Pin(i)
+	return err
+}
+
+// solvePinned implements solve for a container with pinned items or a
+// Scroll. The pinned items are placed at their edges, in sibling order
+// from the edge inwards for PinStart and outwards for PinEnd, each
+// followed or preceded by the main gap. The remaining items are laid
+// out by solveFlow in the space left between them, and shifted by
+// Scroll.
+//
+// The returned lines hold only the flow items, with their indices into
+// items.
+func (fl *Flex) solvePinned(size image.Point, items []Item, t *widget.Theme) ([]image.Rectangle, []flexLine) {
+	rects := make([]image.Rectangle, len(items))
+	containerMainSize := float64(fl.mainSize(size))
+	containerCrossSize := float64(fl.crossSize(size))
+	mainGap, _ := fl.gaps(t)
+
+	// Breakpoints follow the size of the whole container, not of the
+	// space left to the flow.
+	resolved := make([]Item, len(items))
+	var flow []Item
+	var flowIndex, start, end []int
+	for i, it := range items {
+		it.LayoutData = it.LayoutData.resolve(fl.mainSize(size))
+		resolved[i] = it
+		switch it.LayoutData.Pin {
+		case PinStart:
+			start = append(start, i)
+		case PinEnd:
+			end = append(end, i)
+		default:
+			flow = append(flow, it)
+			flowIndex = append(flowIndex, i)
+		}
+	}
+
+	var startSize, endSize float64
+	for _, i := range start {
+		startSize += fl.placePin(&rects[i], resolved[i], startSize, containerMainSize, containerCrossSize) + mainGap
+	}
+	for j := len(end) - 1; j >= 0; j-- {
+		i := end[j]
+		endSize += fl.placePin(&rects[i], resolved[i], endSize, containerMainSize, containerCrossSize) + mainGap
+	}
+
+	flowSize := containerMainSize - startSize - endSize
+	if flowSize < 0 {
+		flowSize = 0
+	}
+	// The flow box starts after the start pins, which are at the right
+	// or bottom edge of a reversed container.
+	shift := startSize - float64(fl.Scroll)
+	if fl.Direction == RowReverse || fl.Direction == ColumnReverse {
+		shift = endSize + float64(fl.Scroll)
+	}
+	var inner image.Point
+	switch fl.Direction {
+	case Row, RowReverse:
+		inner = image.Point{int(flowSize), size.Y}
+	default:
+		inner = image.Point{size.X, int(flowSize)}
+	}
+	flowRects, lines := fl.solveFlow(inner, flow, t)
+
+	var d image.Point
+	switch fl.Direction {
+	case Row, RowReverse:
+		d.X = int(math.Floor(shift + 0.5))
+	default:
+		d.Y = int(math.Floor(shift + 0.5))
+	}
+	for j, r := range flowRects {
+		if r != (image.Rectangle{}) {
+			r = r.Add(d)
+		}
+		rects[flowIndex[j]] = r
+	}
+	for lineNum := range lines {
+		for _, child := range lines[lineNum].child {
+			child.index = flowIndex[child.index]
+			child.mainOffset += shift
+		}
+	}
+	return rects, lines
+}
+
+// placePin sets r to the Rect of pinned item it, offset pixels from
+// the edge it is pinned to, and returns its main size.
+func (fl *Flex) placePin(r *image.Rectangle, it Item, offset, containerMainSize, containerCrossSize float64) float64 {
+	d := it.LayoutData
+	mainSize := fl.clampMain(d, float64(fl.flexBaseSize(it)))
+
+	var crossSize float64
+	switch {
+	case d.CrossBasis == Definite:
+		crossSize = float64(d.CrossSize)
+	case it.CrossSizeFor != nil:
+		crossSize = float64(it.CrossSizeFor(int(math.Ceil(mainSize))))
+	default:
+		crossSize = float64(fl.crossSize(it.MeasuredSize))
+	}
+	align := fl.alignItem(d)
+	if align == AlignItemStretch && d.CrossBasis != Definite && containerCrossSize >= 0 {
+		crossSize = containerCrossSize
+	}
+	crossSize = fl.clampCross(d, crossSize)
+
+	var crossOffset float64
+	if fl.Safe && crossSize > containerCrossSize {
+		align = AlignItemStart
+	}
+	if containerCrossSize >= 0 {
+		switch align {
+		case AlignItemCenter:
+			crossOffset = (containerCrossSize - crossSize) / 2
+		case AlignItemEnd:
+			crossOffset = containerCrossSize - crossSize
+		}
+		if fl.Wrap == WrapReverse {
+			crossOffset = containerCrossSize - crossOffset - crossSize
+		}
+	}
+
+	mainOffset := offset
+	if d.Pin == PinEnd {
+		mainOffset = containerMainSize - offset - mainSize
+	}
+	if fl.Direction == RowReverse || fl.Direction == ColumnReverse {
+		mainOffset = containerMainSize - mainOffset - mainSize
+	}
+
+	round := func(x float64) int { return int(math.Ceil(x - roundingSlop)) }
+	switch fl.Direction {
+	case Row, RowReverse:
+		*r = image.Rect(round(mainOffset), round(crossOffset), round(mainOffset+mainSize), round(crossOffset+crossSize))
+	default:
+		*r = image.Rect(round(crossOffset), round(mainOffset), round(crossOffset+crossSize), round(mainOffset+mainSize))
+	}
+	return mainSize
+}
+
+// pinnedExtent returns the main size taken by the pinned items, with
+// their gaps, and the largest cross size of any of them, for
+// MeasureConstrained.
+func (fl *Flex) pinnedExtent(items []Item, mainSize int, t *widget.Theme) (main, cross float64) {
+	mainGap, _ := fl.gaps(t)
+	for _, it := range items {
+		d := it.LayoutData.resolve(mainSize)
+		if d.Pin == PinNone {
+			continue
+		}
+		it.LayoutData = d
+		var r image.Rectangle
+		main += fl.placePin(&r, it, 0, 0, -1) + mainGap
+		if c := float64(fl.crossSize(r.Size())); c > cross {
+			cross = c
+		}
+	}
+	return main, cross
+}
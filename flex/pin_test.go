@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestPin(t *testing.T) {
+	noShrink := 0.0
+	setting := Item{MeasuredSize: size(10, 40), LayoutData: LayoutData{Shrink: &noShrink}}
+	apply := Item{MeasuredSize: size(20, 20), LayoutData: LayoutData{Pin: PinEnd}}
+
+	tests := []struct {
+		name string
+		fl   Flex
+		size image.Point
+		in   []Item
+		want []image.Rectangle
+	}{{
+		name: "end of overflowing column",
+		fl:   Flex{Direction: Column, Justify: JustifyCenter},
+		size: size(50, 100),
+		in:   []Item{setting, setting, apply, setting},
+		want: []image.Rectangle{
+			image.Rect(0, -20, 10, 20),
+			image.Rect(0, 20, 10, 60),
+			image.Rect(0, 80, 20, 100),
+			image.Rect(0, 60, 10, 100),
+		},
+	}, {
+		name: "scrolled",
+		fl:   Flex{Direction: Column, Scroll: 30},
+		size: size(50, 100),
+		in:   []Item{setting, setting, setting, apply},
+		want: []image.Rectangle{
+			image.Rect(0, -30, 10, 10),
+			image.Rect(0, 10, 10, 50),
+			image.Rect(0, 50, 10, 90),
+			image.Rect(0, 80, 20, 100),
+		},
+	}, {
+		name: "start and end with gap",
+		fl:   Flex{Direction: Row, ColumnGap: 5, Justify: JustifyCenter},
+		size: size(100, 10),
+		in: []Item{
+			{MeasuredSize: size(10, 10)},
+			{MeasuredSize: size(20, 4), LayoutData: LayoutData{Pin: PinEnd, Align: AlignItemEnd}},
+			{MeasuredSize: size(15, 10), LayoutData: LayoutData{Pin: PinStart}},
+			{MeasuredSize: size(20, 4), LayoutData: LayoutData{Pin: PinEnd}},
+		},
+		want: []image.Rectangle{
+			image.Rect(30, 0, 40, 10),
+			image.Rect(55, 6, 75, 10),
+			image.Rect(0, 0, 15, 10),
+			image.Rect(80, 0, 100, 4),
+		},
+	}, {
+		name: "reversed",
+		fl:   Flex{Direction: RowReverse, AlignItem: AlignItemStretch},
+		size: size(100, 10),
+		in: []Item{
+			{MeasuredSize: size(20, 4), LayoutData: LayoutData{Pin: PinStart}},
+			{MeasuredSize: size(10, 4)},
+		},
+		want: []image.Rectangle{
+			image.Rect(80, 0, 100, 10),
+			image.Rect(70, 0, 80, 10),
+		},
+	}}
+	for _, test := range tests {
+		got := test.fl.Solve(test.size, test.in)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s:\n got %v\nwant %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPinMeasure(t *testing.T) {
+	fl := NewFlex()
+	fl.ColumnGap = 4
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(15)).Node
+	b.LayoutData = LayoutData{Pin: PinEnd}
+	fl.AppendChild(b)
+	fl.AppendChild(a)
+	fl.Class.Measure(&fl.Node, nil)
+	if got, want := fl.MeasuredSize, size(34, 15); got != want {
+		t.Errorf("MeasuredSize = %v, want %v", got, want)
+	}
+
+	fl.Rect = image.Rect(0, 0, 50, 15)
+	fl.Class.Layout(&fl.Node, nil)
+	if got, want := PaintOrder(&fl.Node), []*widget.Node{a, b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PaintOrder = %v, want pinned child last", got)
+	}
+	if e := Explain(fl, b); !e.Pinned || e.Rect != image.Rect(30, 0, 50, 15) {
+		t.Errorf("Explain: Pinned=%t Rect=%v", e.Pinned, e.Rect)
+	}
+}
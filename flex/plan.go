@@ -0,0 +1,62 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Plan returns the layout that the tree rooted at n would have if n
+// were the given size, without changing the tree: no Rect is written
+// and no observer is notified. It answers questions such as how tall a
+// tooltip would be at a given width, or where the children of a
+// container will end up, to animate them there.
+//
+// The Rects of the result are relative to their parents, as after
+// Layout, with n keeping its Rect.Min. The tree must have been
+// measured. The children of a node that is not a Flex keep their
+// current Rects, as only a Flex can be laid out without writing them.
+//
+// Errors of the flex loop, such as from a NaN Grow, are reported by
+// the result's Err method.
+//
+// Plan reads the tree, so it must not run concurrently with changes
+// to it, such as Measure and Layout.
+func Plan(n *widget.Node, t *widget.Theme, size image.Point) *LayoutResult {
+	r := &LayoutResult{
+		Root:  n,
+		nodes: make(map[*widget.Node]*nodeRecord),
+	}
+	r.plan(n, t, image.Rectangle{Min: n.Rect.Min, Max: n.Rect.Min.Add(size)})
+	return r
+}
+
+func (r *LayoutResult) plan(n *widget.Node, t *widget.Theme, rect image.Rectangle) {
+	rec := &nodeRecord{rect: rect}
+	rec.data, rec.hasData = n.LayoutData.(LayoutData)
+	r.nodes[n] = rec
+
+	var rects []image.Rectangle
+	if k, ok := n.Class.(*flexClass); ok {
+		var lines []flexLine
+		rects, lines = k.arrange(n, t, rect.Size())
+		fl := k.flex
+		infos := appendLineInfo(nil, lines, fl.crossSize(fl.contentBox(rect.Size()).Min))
+		rec.lines = fl.makeLines(infos, rect.Size())
+		rec.err = newLoopError(fl, n, lines)
+	}
+	i := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		rec.children = append(rec.children, c)
+		cr := c.Rect
+		if rects != nil {
+			cr = rects[i]
+		}
+		r.plan(c, t, cr)
+		i++
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func planTree() (*Flex, []*widget.Node) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.SafeArea = Insets{Top: 4, Left: 2}
+	inner := NewFlex()
+	inner.Direction = Column
+	inner.LayoutData = LayoutData{Grow: 1}
+	nodes := []*widget.Node{&inner.Node}
+	for i := 0; i < 3; i++ {
+		c := widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(20)).Node
+		c.LayoutData = LayoutData{Grow: 1}
+		inner.AppendChild(c)
+		nodes = append(nodes, c)
+	}
+	fl.AppendChild(&inner.Node)
+	for i := 0; i < 3; i++ {
+		c := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(20)).Node
+		fl.AppendChild(c)
+		nodes = append(nodes, c)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(5, 5, 205, 105)
+	fl.Class.Layout(&fl.Node, nil)
+	return fl, nodes
+}
+
+func TestPlan(t *testing.T) {
+	fl, nodes := planTree()
+	before := Snapshot(&fl.Node)
+	notified := false
+	fl.NotifyLayout(func([]ChildChange) { notified = true })
+
+	p := Plan(&fl.Node, nil, image.Pt(100, 150))
+	if notified {
+		t.Error("Plan notified a layout observer")
+	}
+	for _, n := range append(nodes, &fl.Node) {
+		if got, _ := before.Rect(n); n.Rect != got {
+			t.Errorf("Plan changed a Rect from %v to %v", got, n.Rect)
+		}
+	}
+	if got, want := fl.Lines(), before.Lines(&fl.Node); !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan changed Lines from %v to %v", want, got)
+	}
+
+	// The plan is what Layout does at that size.
+	fl.Rect = image.Rect(5, 5, 105, 155)
+	fl.Class.Layout(&fl.Node, nil)
+	after := Snapshot(&fl.Node)
+	if p.Len() != after.Len() {
+		t.Errorf("plan has %d nodes, want %d", p.Len(), after.Len())
+	}
+	for _, n := range append(nodes, &fl.Node) {
+		got, _ := p.Rect(n)
+		want, _ := after.Rect(n)
+		if got != want {
+			t.Errorf("planned Rect %v, Layout gave %v", got, want)
+		}
+		if !reflect.DeepEqual(p.Lines(n), after.Lines(n)) {
+			t.Errorf("planned Lines %v, Layout gave %v", p.Lines(n), after.Lines(n))
+		}
+	}
+	if got, _ := p.Rect(nodes[3]); got == (image.Rectangle{}) {
+		t.Error("nested child not planned")
+	}
+}
+
+func TestPlanErr(t *testing.T) {
+	fl, nodes := planTree()
+	if err := Plan(&fl.Node, nil, image.Pt(100, 150)).Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	nodes[4].LayoutData = LayoutData{Grow: math.NaN()}
+	err, ok := Plan(&fl.Node, nil, image.Pt(100, 150)).Err().(*LoopError)
+	if !ok || err.Flex != fl || err.Items[len(err.Items)-1].Node != nodes[4] {
+		t.Errorf("Err() = %v, want a LoopError for nodes[4]", err)
+	}
+	if fl.Err() != nil || CheckLayout(&fl.Node) != nil {
+		t.Error("Plan set the error of the tree")
+	}
+
+	fl.Class.Layout(&fl.Node, nil)
+	if err := Snapshot(&fl.Node).Err(); err != fl.Err() || err == nil {
+		t.Errorf("Snapshot Err() = %v, want %v", err, fl.Err())
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// LoadFile decodes a JSON Doc from the named file and builds its widget
+// tree.
+func LoadFile(name string) (*Tree, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Reloader is a widget showing the tree of a layout document file,
+// which it rebuilds when the file changes, so that a layout can be
+// edited while the program showing it runs.
+//
+// Its only child is the root of the current tree, laid out to fill
+// the Reloader. Pass it, or a tree containing it, to RunWindow with
+// WindowOptions.Reloader set to have the window follow the file.
+type Reloader struct {
+	widget.Node
+
+	// Name is the name of the file.
+	Name string
+
+	// Interval is how often Watch looks at the file. If zero, it is
+	// half a second.
+	Interval time.Duration
+
+	tree *Tree
+
+	mu      sync.Mutex // guards modTime and size, used by Watch
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader returns a Reloader showing the tree of the named file.
+func NewReloader(name string) (*Reloader, error) {
+	r := &Reloader{Name: name}
+	r.Node.Class = reloaderClass{}
+	if _, err := r.Check(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Tree returns the current tree.
+func (r *Reloader) Tree() *Tree {
+	return r.tree
+}
+
+// Check rebuilds the tree if the file has changed since it was last
+// built, and reports whether it did. If the file cannot be read or
+// built, the current tree is kept and the error returned, and the
+// file is read again once it next changes.
+//
+// Check changes the widget tree, so it must be called where the tree
+// is laid out and painted.
+func (r *Reloader) Check() (changed bool, err error) {
+	fi, err := os.Stat(r.Name)
+	if err != nil {
+		return false, err
+	}
+	if !r.record(fi) {
+		return false, nil
+	}
+	t, err := LoadFile(r.Name)
+	if err != nil {
+		return false, err
+	}
+	if old := r.FirstChild; old != nil {
+		r.RemoveChild(old)
+	}
+	r.AppendChild(t.Root)
+	r.tree = t
+	return true, nil
+}
+
+// record records the modification time and size of the file and
+// reports whether they changed.
+func (r *Reloader) record(fi os.FileInfo) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fi.ModTime().Equal(r.modTime) && fi.Size() == r.size {
+		return false
+	}
+	r.modTime, r.size = fi.ModTime(), fi.Size()
+	return true
+}
+
+// modified reports whether the file has changed since Check last
+// recorded it.
+func (r *Reloader) modified() bool {
+	fi, err := os.Stat(r.Name)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !fi.ModTime().Equal(r.modTime) || fi.Size() != r.size
+}
+
+// Watch polls the file on a new goroutine and calls changed, on that
+// goroutine, whenever the file has changed since the last Check. The
+// caller is expected to arrange for Check to be called where the tree
+// is used. It returns a function that stops watching.
+func (r *Reloader) Watch(changed func()) (stop func()) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	done := make(chan struct{})
+	go func() {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick.C:
+				if r.modified() {
+					changed()
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+type reloaderClass struct {
+	widget.ContainerClassEmbed
+}
+
+func (reloaderClass) Layout(n *widget.Node, t *widget.Theme) {
+	if c := n.FirstChild; c != nil {
+		c.Rect = image.Rectangle{Max: n.Rect.Size()}
+		c.Class.Layout(c, t)
+	}
+}
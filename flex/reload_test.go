@@ -0,0 +1,82 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flex-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "layout.json")
+	write := func(doc string, mod time.Time) {
+		if err := ioutil.WriteFile(name, []byte(doc), 0666); err != nil {
+			t.Fatal(err)
+		}
+		// Set the time explicitly, as the file system's resolution may
+		// be too coarse to see the change.
+		if err := os.Chtimes(name, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := time.Now().Add(-time.Hour)
+	write(`{"type": "flex", "children": [{"type": "uniform", "id": "a", "style": "width: 10px; height: 10px"}]}`, base)
+
+	r, err := NewReloader(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := func() {
+		r.Class.Measure(&r.Node, nil)
+		r.Rect = image.Rect(0, 0, 100, 50)
+		r.Class.Layout(&r.Node, nil)
+	}
+	layout()
+	a := r.Tree().ByID["a"]
+	if r.FirstChild != r.Tree().Root || r.FirstChild.Rect != image.Rect(0, 0, 100, 50) || a.Rect != image.Rect(0, 0, 10, 10) {
+		t.Fatalf("initial layout: root %v, a %v", r.FirstChild.Rect, a.Rect)
+	}
+
+	if changed, err := r.Check(); changed || err != nil {
+		t.Errorf("Check of unchanged file = %t, %v", changed, err)
+	}
+
+	stop := r.Watch(func() {})
+	defer stop()
+
+	write(`{"type": "flex", "style": "justify-content: flex-end", "children": [{"type": "uniform", "id": "a", "style": "width: 10px; height: 10px"}]}`, base.Add(time.Minute))
+	if !r.modified() {
+		t.Error("modified = false after write")
+	}
+	if changed, err := r.Check(); !changed || err != nil {
+		t.Fatalf("Check after write = %t, %v", changed, err)
+	}
+	layout()
+	if a := r.Tree().ByID["a"]; a.Rect != image.Rect(90, 0, 100, 10) {
+		t.Errorf("after reload: a %v", a.Rect)
+	}
+	if r.FirstChild.NextSibling != nil {
+		t.Error("old tree not removed")
+	}
+
+	// A bad document keeps the last good tree.
+	good := r.Tree()
+	write(`{"type": "flex", "style": "justify-content: sideways"}`, base.Add(2*time.Minute))
+	if changed, err := r.Check(); changed || err == nil {
+		t.Errorf("Check of bad file = %t, %v", changed, err)
+	}
+	if r.Tree() != good || r.FirstChild != good.Root {
+		t.Error("bad file replaced the tree")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package render paints widget trees into images without a shiny
+// screen, for generating dashboards, thumbnails and the like on a
+// server.
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/crawshaw/exp/flex"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Decoration is painted with a node, like the CSS background and border
+// of an HTML element.
+type Decoration struct {
+	// Fill, if non-nil, fills the node's Rect before its content.
+	Fill color.Color
+
+	// Image, if non-nil, is scaled to the node's Rect and drawn over
+	// Fill, before the node's content.
+	Image image.Image
+
+	// BorderColor and BorderWidth describe a border drawn inside the
+	// node's Rect, after its content.
+	BorderColor color.Color
+	BorderWidth int
+}
+
+// Options are optional arguments to Render and Paint.
+type Options struct {
+	Theme *widget.Theme
+
+	// Background, if non-nil, fills the destination before painting.
+	Background color.Color
+
+	// Decorations are painted with their nodes.
+	Decorations map[*widget.Node]Decoration
+}
+
+// Render measures root, lays it out at the given size and paints it into
+// a new image.
+func Render(root *widget.Node, size image.Point, opts *Options) *image.RGBA {
+	var t *widget.Theme
+	if opts != nil {
+		t = opts.Theme
+	}
+	root.Class.Measure(root, t)
+	root.Rect = image.Rectangle{Max: size}
+	root.Class.Layout(root, t)
+
+	dst := image.NewRGBA(root.Rect)
+	Paint(dst, root, image.Point{}, opts)
+	return dst
+}
+
+// Paint paints the laid out tree rooted at n into dst. As with the Paint
+// method of a widget.Class, origin is the position in dst of the origin
+// of n's parent.
+//
+// Leaf nodes are painted by their Class. Nodes with children are not:
+// Paint walks into them itself, so that decorations are layered
+// correctly between a container and its children.
+func Paint(dst *image.RGBA, n *widget.Node, origin image.Point, opts *Options) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.Background != nil {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+	}
+	paint(dst, n, origin, opts)
+}
+
+func paint(dst *image.RGBA, n *widget.Node, origin image.Point, opts *Options) {
+	r := n.Rect.Add(origin)
+	deco, ok := opts.Decorations[n]
+	if ok {
+		if deco.Fill != nil {
+			draw.Draw(dst, r, image.NewUniform(deco.Fill), image.Point{}, draw.Over)
+		}
+		if deco.Image != nil {
+			xdraw.ApproxBiLinear.Scale(dst, r, deco.Image, deco.Image.Bounds(), draw.Over, nil)
+		}
+	}
+	if n.FirstChild == nil {
+		n.Class.Paint(n, opts.Theme, dst, origin)
+	} else {
+		cdst := dst
+		if clip, ok := flex.ClipRect(n); ok {
+			cdst, _ = dst.SubImage(clip.Add(r.Min)).(*image.RGBA)
+		}
+		for _, c := range flex.PaintOrder(n) {
+			paint(cdst, c, r.Min, opts)
+		}
+	}
+	if ok && deco.BorderColor != nil && deco.BorderWidth > 0 {
+		drawBorder(dst, r, deco.BorderColor, deco.BorderWidth)
+	}
+}
+
+// drawBorder draws a border of width w inside r.
+func drawBorder(dst *image.RGBA, r image.Rectangle, c color.Color, w int) {
+	src := image.NewUniform(c)
+	inner := r.Inset(w)
+	if inner.Empty() {
+		draw.Draw(dst, r, src, image.Point{}, draw.Over)
+		return
+	}
+	for _, side := range []image.Rectangle{
+		{r.Min, image.Pt(r.Max.X, inner.Min.Y)},                              // top
+		{image.Pt(r.Min.X, inner.Max.Y), r.Max},                              // bottom
+		{image.Pt(r.Min.X, inner.Min.Y), image.Pt(inner.Min.X, inner.Max.Y)}, // left
+		{image.Pt(inner.Max.X, inner.Min.Y), image.Pt(r.Max.X, inner.Max.Y)}, // right
+	} {
+		draw.Draw(dst, side, src, image.Point{}, draw.Over)
+	}
+}
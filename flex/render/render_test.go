@@ -0,0 +1,106 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex"
+)
+
+var (
+	red   = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	green = color.RGBA{0x00, 0xff, 0x00, 0xff}
+	blue  = color.RGBA{0x00, 0x00, 0xff, 0xff}
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+func TestRender(t *testing.T) {
+	fl, err := flex.ParseStyle("justify-content: space-between")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := widget.NewUniform(red, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(green, unit.Pixels(10), unit.Pixels(20)).Node
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+
+	pic := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	pic.Set(0, 0, blue)
+	pic.Set(1, 0, blue)
+	pic.Set(0, 1, blue)
+	pic.Set(1, 1, blue)
+
+	dst := Render(&fl.Node, image.Pt(40, 20), &Options{
+		Background: white,
+		Decorations: map[*widget.Node]Decoration{
+			&fl.Node: {BorderColor: black, BorderWidth: 1},
+			b:        {Image: pic, BorderColor: black, BorderWidth: 2},
+		},
+	})
+	if got, want := dst.Bounds(), image.Rect(0, 0, 40, 20); got != want {
+		t.Fatalf("Bounds=%v, want %v", got, want)
+	}
+
+	for _, test := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, black},   // container border
+		{39, 19, black}, // container border
+		{5, 5, red},     // a
+		{5, 15, white},  // below a
+		{20, 10, white}, // between a and b
+		{31, 3, black},  // b border, over container border
+		{35, 10, green}, // b content over its image
+	} {
+		if got := dst.RGBAAt(test.x, test.y); got != test.want {
+			t.Errorf("(%d, %d)=%v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestDecorationImage(t *testing.T) {
+	n := &widget.Node{Class: &widget.ContainerClassEmbed{}}
+	n.AppendChild(&widget.Node{Class: &widget.ContainerClassEmbed{}, Rect: image.Rect(2, 2, 6, 6)})
+	pic := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	pic.Set(0, 0, blue)
+	dst := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	Paint(dst, n, image.Point{}, &Options{
+		Decorations: map[*widget.Node]Decoration{
+			n.FirstChild: {Image: pic, Fill: red},
+		},
+	})
+	if got := dst.RGBAAt(3, 3); got != blue {
+		t.Errorf("inside=%v, want %v", got, blue)
+	}
+	if got := dst.RGBAAt(1, 1); got != (color.RGBA{}) {
+		t.Errorf("outside=%v, want transparent", got)
+	}
+}
+
+func TestRenderClip(t *testing.T) {
+	fl := flex.NewFlex()
+	fl.Wrap = flex.Wrap
+	fl.AlignContent = flex.AlignContentStart
+	fl.MaxLines = 1
+	fl.LineOverflow = flex.LineOverflowClip
+	fl.AppendChild(widget.NewUniform(red, unit.Pixels(10), unit.Pixels(10)).Node)
+	fl.AppendChild(widget.NewUniform(green, unit.Pixels(10), unit.Pixels(10)).Node)
+
+	dst := Render(&fl.Node, image.Pt(15, 30), &Options{Background: white})
+	if got := dst.RGBAAt(5, 5); got != red {
+		t.Errorf("first line is %v, want red", got)
+	}
+	if got := dst.RGBAAt(5, 15); got != white {
+		t.Errorf("clipped line is %v, want white", got)
+	}
+}
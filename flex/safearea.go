@@ -0,0 +1,44 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import "image"
+
+// Insets are distances in pixels from each edge of a rectangle.
+type Insets struct {
+	Top, Right, Bottom, Left int
+}
+
+// contentBox returns the part of a container of the given size that is
+// inside the SafeArea, relative to the container.
+func (fl *Flex) contentBox(size image.Point) image.Rectangle {
+	in := fl.SafeArea
+	r := image.Rect(in.Left, in.Top, size.X-in.Right, size.Y-in.Bottom)
+	if r.Max.X < r.Min.X {
+		r.Max.X = r.Min.X
+	}
+	if r.Max.Y < r.Min.Y {
+		r.Max.Y = r.Min.Y
+	}
+	return r
+}
+
+// bleed extends each edge of r that touches an edge of content out to
+// the matching edge of a container of the given size.
+func bleed(r, content image.Rectangle, size image.Point) image.Rectangle {
+	if r.Min.X <= content.Min.X {
+		r.Min.X = 0
+	}
+	if r.Min.Y <= content.Min.Y {
+		r.Min.Y = 0
+	}
+	if r.Max.X >= content.Max.X {
+		r.Max.X = size.X
+	}
+	if r.Max.Y >= content.Max.Y {
+		r.Max.Y = size.Y
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestSafeArea(t *testing.T) {
+	fl := NewFlex()
+	fl.Direction = Column
+	fl.SafeArea = Insets{Top: 20, Right: 5, Bottom: 10, Left: 5}
+	header := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(30)).Node
+	header.LayoutData = LayoutData{Align: AlignItemStretch, FullBleed: true}
+	body := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	body.LayoutData = LayoutData{Grow: 1, Align: AlignItemStretch}
+	fl.AppendChild(header)
+	fl.AppendChild(body)
+
+	fl.Class.Measure(&fl.Node, nil)
+	if want := image.Pt(20, 70); fl.MeasuredSize != want {
+		t.Errorf("MeasuredSize=%v, want %v", fl.MeasuredSize, want)
+	}
+	fl.Rect = image.Rect(0, 0, 100, 200)
+	fl.Class.Layout(&fl.Node, nil)
+
+	// The header bleeds into the top, left and right insets; the body
+	// stays inside the safe area.
+	if want := image.Rect(0, 0, 100, 50); header.Rect != want {
+		t.Errorf("header.Rect=%v, want %v", header.Rect, want)
+	}
+	if want := image.Rect(5, 50, 95, 190); body.Rect != want {
+		t.Errorf("body.Rect=%v, want %v", body.Rect, want)
+	}
+}
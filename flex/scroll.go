@@ -0,0 +1,72 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// ScrollIntoView returns the smallest change to a scroll offset that
+// brings child fully into viewport. Both child's Rect and viewport are
+// in the coordinates of fl, as of its last Layout, and the result is
+// to be added to the viewport's position.
+//
+// Along the cross axis the whole flex line holding child is brought
+// into view, so that scrolling a wrapping container by keyboard keeps
+// rows or columns intact. If the target is larger than the viewport,
+// its start edge is aligned with the viewport's.
+//
+// If child is not a child of fl, ScrollIntoView returns the zero Point.
+func (fl *Flex) ScrollIntoView(child *widget.Node, viewport image.Rectangle) image.Point {
+	if child.Parent != &fl.Node {
+		return image.Point{}
+	}
+	target := child.Rect
+
+	index := 0
+	for c := fl.FirstChild; c != child; c = c.NextSibling {
+		index++
+	}
+	if k, ok := fl.Class.(*flexClass); ok {
+		for _, line := range k.lines {
+			if !line.has(index) {
+				continue
+			}
+			lo := int(math.Floor(line.crossOffset))
+			hi := int(math.Ceil(line.crossOffset + line.crossSize))
+			switch fl.Direction {
+			case Row, RowReverse:
+				target.Min.Y, target.Max.Y = lo, hi
+			default:
+				target.Min.X, target.Max.X = lo, hi
+			}
+			break
+		}
+	}
+
+	return image.Point{
+		scrollDelta(target.Min.X, target.Max.X, viewport.Min.X, viewport.Max.X),
+		scrollDelta(target.Min.Y, target.Max.Y, viewport.Min.Y, viewport.Max.Y),
+	}
+}
+
+// scrollDelta returns the smallest move of the span [vlo, vhi) that
+// brings [lo, hi) into it, preferring to show lo.
+func scrollDelta(lo, hi, vlo, vhi int) int {
+	switch {
+	case lo < vlo:
+		return lo - vlo
+	case hi > vhi:
+		d := hi - vhi
+		if lo-d < vlo {
+			return lo - vlo
+		}
+		return d
+	}
+	return 0
+}
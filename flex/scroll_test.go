@@ -0,0 +1,59 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestScrollIntoView(t *testing.T) {
+	// A wrapping grid of 3 columns; the middle item of each line is
+	// shorter than its line.
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.AlignContent = AlignContentStart
+	var children []*widget.Node
+	for i := 0; i < 9; i++ {
+		h := 40.0
+		if i%3 == 1 {
+			h = 20
+		}
+		n := widget.NewUniform(color.Black, unit.Pixels(30), unit.Pixels(h)).Node
+		children = append(children, n)
+		fl.AppendChild(n)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 90, 400)
+	fl.Class.Layout(&fl.Node, nil)
+
+	tests := []struct {
+		child    int
+		viewport image.Rectangle
+		want     image.Point
+	}{
+		{0, image.Rect(0, 0, 90, 50), image.Pt(0, 0)},
+		{4, image.Rect(0, 0, 90, 50), image.Pt(0, 30)}, // whole second line, 40 to 80
+		{7, image.Rect(0, 0, 90, 50), image.Pt(0, 70)}, // third line, 80 to 120
+		{1, image.Rect(0, 60, 90, 110), image.Pt(0, -60)},
+		{2, image.Rect(0, 0, 50, 50), image.Pt(40, 0)},
+		{4, image.Rect(0, 0, 90, 30), image.Pt(0, 40)}, // too tall; show its start
+	}
+	for _, test := range tests {
+		got := fl.ScrollIntoView(children[test.child], test.viewport)
+		if got != test.want {
+			t.Errorf("ScrollIntoView(child %d, %v) = %v, want %v", test.child, test.viewport, got, test.want)
+		}
+	}
+
+	other := widget.NewUniform(color.Black, unit.Pixels(1), unit.Pixels(1)).Node
+	if got := fl.ScrollIntoView(other, image.Rect(0, 0, 1, 1)); got != (image.Point{}) {
+		t.Errorf("ScrollIntoView(non-child) = %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// A LayoutResult is an immutable record of a widget tree at one
+// moment: its shape, and the Rects, LayoutData and flex lines of its
+// nodes. It can be read from any goroutine while the tree is changed
+// and laid out again.
+type LayoutResult struct {
+	Root  *widget.Node
+	nodes map[*widget.Node]*nodeRecord
+}
+
+type nodeRecord struct {
+	rect     image.Rectangle
+	children []*widget.Node
+	data     LayoutData
+	hasData  bool
+	lines    []Line
+	err      *LoopError
+}
+
+// A Line is a flex line of a Flex at its last Layout.
+type Line struct {
+	// Start and End are the children of the line, [Start, End) in
+	// sibling order.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Rect is the box of the line, in the same coordinates as the
+	// Rects of the children. It spans the main size of the content box.
+	Rect image.Rectangle `json:"rect"`
+}
+
+// Lines returns the flex lines of fl at its last Layout.
+func (fl *Flex) Lines() []Line {
+	k, ok := fl.Class.(*flexClass)
+	if !ok {
+		return nil
+	}
+	return fl.makeLines(k.lines, fl.Rect.Size())
+}
+
+// makeLines returns the Lines of infos in a Flex of the given size.
+func (fl *Flex) makeLines(infos []lineInfo, size image.Point) []Line {
+	if len(infos) == 0 {
+		return nil
+	}
+	content := fl.contentBox(size)
+	lines := make([]Line, len(infos))
+	for i, l := range infos {
+		lo := int(math.Floor(l.crossOffset + 0.5))
+		hi := int(math.Floor(l.crossOffset + l.crossSize + 0.5))
+		r := content
+		if fl.Direction == Row || fl.Direction == RowReverse {
+			r.Min.Y, r.Max.Y = lo, hi
+		} else {
+			r.Min.X, r.Max.X = lo, hi
+		}
+		lines[i] = Line{Start: l.start, End: l.end, Rect: r}
+	}
+	return lines
+}
+
+// Snapshot records the tree rooted at root.
+func Snapshot(root *widget.Node) *LayoutResult {
+	r := &LayoutResult{
+		Root:  root,
+		nodes: make(map[*widget.Node]*nodeRecord),
+	}
+	r.record(root)
+	return r
+}
+
+func (r *LayoutResult) record(n *widget.Node) {
+	rec := &nodeRecord{rect: n.Rect}
+	rec.data, rec.hasData = n.LayoutData.(LayoutData)
+	if k, ok := n.Class.(*flexClass); ok {
+		rec.lines = k.flex.Lines()
+		rec.err = k.err
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		rec.children = append(rec.children, c)
+		r.record(c)
+	}
+	r.nodes[n] = rec
+}
+
+// Rect returns the Rect of n when the snapshot was taken, and whether n
+// was in the tree.
+func (r *LayoutResult) Rect(n *widget.Node) (image.Rectangle, bool) {
+	rec, ok := r.nodes[n]
+	if !ok {
+		return image.Rectangle{}, false
+	}
+	return rec.rect, true
+}
+
+// Children returns the children of n when the snapshot was taken.
+func (r *LayoutResult) Children(n *widget.Node) []*widget.Node {
+	if rec, ok := r.nodes[n]; ok {
+		return rec.children
+	}
+	return nil
+}
+
+// LayoutData returns the LayoutData of n when the snapshot was taken,
+// and whether n had one.
+func (r *LayoutResult) LayoutData(n *widget.Node) (LayoutData, bool) {
+	if rec, ok := r.nodes[n]; ok {
+		return rec.data, rec.hasData
+	}
+	return LayoutData{}, false
+}
+
+// Lines returns the flex lines of n when the snapshot was taken, if n
+// is the node of a Flex.
+func (r *LayoutResult) Lines(n *widget.Node) []Line {
+	if rec, ok := r.nodes[n]; ok {
+		return rec.lines
+	}
+	return nil
+}
+
+// Err returns the first error, in a depth-first walk of the snapshot,
+// of the layout of its Flex nodes, as CheckLayout does for a tree.
+func (r *LayoutResult) Err() error {
+	return r.err(r.Root)
+}
+
+func (r *LayoutResult) err(n *widget.Node) error {
+	rec, ok := r.nodes[n]
+	if !ok {
+		return nil
+	}
+	if rec.err != nil {
+		return rec.err
+	}
+	for _, c := range rec.children {
+		if err := r.err(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of nodes in the snapshot.
+func (r *LayoutResult) Len() int {
+	return len(r.nodes)
+}
+
+// Sync lets a widget tree be laid out on one goroutine and painted on
+// another. Layout writes the Rects of the tree in place, so every
+// change to the tree, including Measure and Layout, must be made in
+// Update, and every read of it, including Paint, in Paint or View.
+//
+// After each Update, Sync takes a Snapshot of the tree, which Result
+// returns without locking, for goroutines that only need the Rects,
+// such as one hit testing input events.
+//
+// The zero Sync is ready to use, and must not be copied after first
+// use.
+type Sync struct {
+	mu     sync.RWMutex
+	result atomic.Value // *LayoutResult
+}
+
+// Update calls f, which may change the tree rooted at root, with no
+// Paint or View running, and then records a new Result.
+func (s *Sync) Update(root *widget.Node, f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+	s.result.Store(Snapshot(root))
+}
+
+// Layout measures and lays out the tree rooted at root to fill size,
+// as Update.
+func (s *Sync) Layout(root *widget.Node, t *widget.Theme, size image.Point) {
+	s.Update(root, func() {
+		root.Class.Measure(root, t)
+		root.Rect = image.Rectangle{Max: size}
+		root.Class.Layout(root, t)
+	})
+}
+
+// Paint paints the tree rooted at root, with no Update running.
+func (s *Sync) Paint(root *widget.Node, t *widget.Theme, dst *image.RGBA, origin image.Point) {
+	s.View(func() { root.Class.Paint(root, t, dst, origin) })
+}
+
+// View calls f, which may read but not change the tree, with no
+// Update running. Calls to View may run at the same time.
+func (s *Sync) View(f func()) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f()
+}
+
+// Result returns the snapshot taken after the last Update, or nil.
+func (s *Sync) Result() *LayoutResult {
+	r, _ := s.result.Load().(*LayoutResult)
+	return r
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestSnapshot(t *testing.T) {
+	fl := NewFlex()
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	fl.AppendChild(a)
+	var s Sync
+	if s.Result() != nil {
+		t.Error("Result before any Update")
+	}
+	s.Layout(&fl.Node, nil, image.Pt(100, 50))
+
+	r := s.Result()
+	if got, ok := r.Rect(a); !ok || got != image.Rect(0, 0, 10, 10) {
+		t.Errorf("Rect(a) = %v, %t", got, ok)
+	}
+	if r.Len() != 2 || r.Root != &fl.Node {
+		t.Errorf("Len=%d Root=%p", r.Len(), r.Root)
+	}
+
+	// A later layout leaves the snapshot unchanged.
+	s.Update(&fl.Node, func() {
+		a.LayoutData = LayoutData{Grow: 1}
+		fl.Class.Layout(&fl.Node, nil)
+	})
+	if got, _ := r.Rect(a); got != image.Rect(0, 0, 10, 10) {
+		t.Errorf("old snapshot changed: %v", got)
+	}
+	if got, _ := s.Result().Rect(a); got != image.Rect(0, 0, 100, 10) {
+		t.Errorf("new snapshot Rect(a) = %v", got)
+	}
+}
+
+// TestSyncRace lays out and paints on separate goroutines, for the
+// race detector.
+func TestSyncRace(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	for i := 0; i < 10; i++ {
+		fl.AppendChild(widget.NewUniform(color.Black, unit.Pixels(20), unit.Pixels(20)).Node)
+	}
+	var s Sync
+	s.Layout(&fl.Node, nil, image.Pt(100, 100))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for w := 50; w < 150; w++ {
+			s.Layout(&fl.Node, nil, image.Pt(w, 100))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		dst := image.NewRGBA(image.Rect(0, 0, 150, 100))
+		for i := 0; i < 100; i++ {
+			s.Paint(&fl.Node, nil, dst, image.Point{})
+			if r := s.Result(); r.Len() != 11 {
+				t.Errorf("snapshot of %d nodes", r.Len())
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestSnapshotLines(t *testing.T) {
+	fl := NewFlex()
+	fl.Wrap = Wrap
+	fl.RowGap = 4
+	var kids []*widget.Node
+	for i := 0; i < 3; i++ {
+		c := widget.NewUniform(color.Black, unit.Pixels(40), unit.Pixels(10)).Node
+		c.LayoutData = LayoutData{Grow: float64(i)}
+		fl.AppendChild(c)
+		kids = append(kids, c)
+	}
+	fl.Class.Measure(&fl.Node, nil)
+	fl.Rect = image.Rect(0, 0, 100, 50)
+	fl.Class.Layout(&fl.Node, nil)
+
+	r := Snapshot(&fl.Node)
+	want := []Line{
+		{Start: 0, End: 2, Rect: image.Rect(0, 0, 100, 23)},
+		{Start: 2, End: 3, Rect: image.Rect(0, 27, 100, 50)},
+	}
+	if got := r.Lines(&fl.Node); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %v, want %v", got, want)
+	}
+	if got := r.Lines(kids[0]); got != nil {
+		t.Errorf("Lines of a leaf = %v", got)
+	}
+	if got := r.Children(&fl.Node); !reflect.DeepEqual(got, kids) {
+		t.Errorf("Children = %v, want %v", got, kids)
+	}
+	if d, ok := r.LayoutData(kids[2]); !ok || d.Grow != 2 {
+		t.Errorf("LayoutData = %+v, %t", d, ok)
+	}
+	if _, ok := r.LayoutData(&fl.Node); ok {
+		t.Error("root has LayoutData")
+	}
+
+	// The snapshot keeps the shape of the tree.
+	fl.RemoveChild(kids[2])
+	if got := len(r.Children(&fl.Node)); got != 3 {
+		t.Errorf("snapshot has %d children after RemoveChild", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+// Space is a step of a Spacing scale.
+type Space int8
+
+// Possible values of Space.
+const (
+	SpaceNone Space = iota
+	SpaceXS
+	SpaceS
+	SpaceM
+	SpaceL
+	SpaceXL
+)
+
+var spaceNames = [...]string{
+	SpaceNone: "none",
+	SpaceXS:   "xs",
+	SpaceS:    "s",
+	SpaceM:    "m",
+	SpaceL:    "l",
+	SpaceXL:   "xl",
+}
+
+func (s Space) String() string {
+	return enumName(spaceNames[:], "Space", int(s))
+}
+
+func (s Space) MarshalText() ([]byte, error) {
+	return marshalEnum(spaceNames[:], "Space", int(s))
+}
+
+func (s *Space) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(spaceNames[:], "Space", text)
+	*s = Space(i)
+	return err
+}
+
+// Density scales the steps of a Spacing, so that a whole app can be
+// made more compact or more comfortable at once.
+type Density int8
+
+// Possible values of Density.
+const (
+	DensityNormal      Density = iota // steps as given
+	DensityCompact                    // steps at three quarters
+	DensityComfortable                // steps at five quarters
+)
+
+var densityNames = [...]string{
+	DensityNormal:      "normal",
+	DensityCompact:     "compact",
+	DensityComfortable: "comfortable",
+}
+
+func (d Density) String() string {
+	return enumName(densityNames[:], "Density", int(d))
+}
+
+func (d Density) MarshalText() ([]byte, error) {
+	return marshalEnum(densityNames[:], "Density", int(d))
+}
+
+func (d *Density) UnmarshalText(text []byte) error {
+	i, err := unmarshalEnum(densityNames[:], "Density", text)
+	*d = Density(i)
+	return err
+}
+
+func (d Density) scale() float64 {
+	switch d {
+	case DensityCompact:
+		return 0.75
+	case DensityComfortable:
+		return 1.25
+	}
+	return 1
+}
+
+// Spacing is a scale of distances that widgets consult for their
+// default gaps, padding and minimum sizes, instead of each choosing
+// its own pixel counts.
+type Spacing struct {
+	XS, S, M, L, XL unit.Value
+
+	// MinTarget is the minimum height of interactive widgets, such
+	// as buttons. If zero, there is no minimum.
+	MinTarget unit.Value
+
+	Density Density
+}
+
+// DefaultSpacing is the Spacing used by a Flex whose Spacing is nil,
+// and by the widgets of package flexwidget. An app switches to compact
+// mode by setting its Density.
+//
+// DefaultSpacing is read without synchronization by every Measure and
+// Layout, so it must be set before any layout starts, such as in main
+// before RunWindow is called, and not changed afterwards.
+var DefaultSpacing = Spacing{
+	XS: unit.Pixels(2),
+	S:  unit.Pixels(4),
+	M:  unit.Pixels(8),
+	L:  unit.Pixels(16),
+	XL: unit.Pixels(32),
+}
+
+// Value returns the step s of the scale, scaled by its Density.
+func (sp *Spacing) Value(s Space) unit.Value {
+	var v unit.Value
+	switch s {
+	case SpaceXS:
+		v = sp.XS
+	case SpaceS:
+		v = sp.S
+	case SpaceM:
+		v = sp.M
+	case SpaceL:
+		v = sp.L
+	case SpaceXL:
+		v = sp.XL
+	}
+	v.F *= sp.Density.scale()
+	return v
+}
+
+// Pixels returns the step s of the scale in pixels.
+func (sp *Spacing) Pixels(t *widget.Theme, s Space) int {
+	return t.Pixels(sp.Value(s)).Round()
+}
+
+// MinTargetPixels returns MinTarget in pixels, scaled by the Density.
+func (sp *Spacing) MinTargetPixels(t *widget.Theme) int {
+	v := sp.MinTarget
+	v.F *= sp.Density.scale()
+	return t.Pixels(v).Round()
+}
+
+// spacing returns the Spacing of fl.
+func (fl *Flex) spacing() *Spacing {
+	if fl.Spacing != nil {
+		return fl.Spacing
+	}
+	return &DefaultSpacing
+}
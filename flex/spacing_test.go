@@ -0,0 +1,107 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestSpacing(t *testing.T) {
+	sp := Spacing{S: unit.DIPs(8), M: unit.DIPs(16)}
+	theme := &widget.Theme{DPI: 160}
+	if got := sp.Pixels(theme, SpaceM); got != 16 {
+		t.Errorf("M = %dpx, want 16", got)
+	}
+	if got := sp.Pixels(theme, SpaceNone); got != 0 {
+		t.Errorf("none = %dpx, want 0", got)
+	}
+	sp.Density = DensityCompact
+	if got := sp.Value(SpaceS); got != unit.DIPs(6) {
+		t.Errorf("compact S = %v, want 6dp", got)
+	}
+	sp.Density = DensityComfortable
+	if got := sp.Pixels(theme, SpaceM); got != 20 {
+		t.Errorf("comfortable M = %dpx, want 20", got)
+	}
+}
+
+func TestSpacingText(t *testing.T) {
+	if s := SpaceXL.String(); s != "xl" {
+		t.Errorf("SpaceXL.String() = %q", s)
+	}
+	if s := Space(9).String(); s != "Space(9)" {
+		t.Errorf("Space(9).String() = %q", s)
+	}
+	if s := DensityCompact.String(); s != "compact" {
+		t.Errorf("DensityCompact.String() = %q", s)
+	}
+	var d Density
+	if err := d.UnmarshalText([]byte("comfortable")); err != nil || d != DensityComfortable {
+		t.Errorf("UnmarshalText(comfortable) = %v, %v", d, err)
+	}
+	if err := d.UnmarshalText([]byte("dense")); err == nil {
+		t.Errorf("UnmarshalText(dense) succeeded")
+	}
+	if _, err := Space(9).MarshalText(); err == nil {
+		t.Errorf("Space(9).MarshalText() succeeded")
+	}
+}
+
+func TestGapSpace(t *testing.T) {
+	layout := func(fl *Flex) []image.Rectangle {
+		fl.Wrap = Wrap
+		fl.AlignContent = AlignContentStart
+		var cs []*widget.Node
+		for i := 0; i < 3; i++ {
+			c := &widget.Node{Class: &widget.LeafClassEmbed{}, MeasuredSize: size(40, 10)}
+			fl.AppendChild(c)
+			cs = append(cs, c)
+		}
+		fl.Rect = image.Rect(0, 0, 100, 100)
+		fl.Class.Layout(&fl.Node, nil)
+		return []image.Rectangle{cs[0].Rect, cs[1].Rect, cs[2].Rect}
+	}
+
+	fl := NewFlex()
+	fl.GapSpace = SpaceM
+	want := []image.Rectangle{image.Rect(0, 0, 40, 10), image.Rect(48, 0, 88, 10), image.Rect(0, 18, 40, 28)}
+	if got := layout(fl); !equalRects(got, want) {
+		t.Errorf("GapSpace M: %v, want %v", got, want)
+	}
+
+	// A pixel gap wins over GapSpace.
+	fl = NewFlex()
+	fl.GapSpace = SpaceM
+	fl.RowGap = 1
+	want = []image.Rectangle{image.Rect(0, 0, 40, 10), image.Rect(48, 0, 88, 10), image.Rect(0, 11, 40, 21)}
+	if got := layout(fl); !equalRects(got, want) {
+		t.Errorf("GapSpace M, RowGap 1: %v, want %v", got, want)
+	}
+
+	// Density applies to every container using the Spacing.
+	fl = NewFlex()
+	fl.GapSpace = SpaceM
+	fl.Spacing = &Spacing{M: unit.Pixels(8), Density: DensityCompact}
+	want = []image.Rectangle{image.Rect(0, 0, 40, 10), image.Rect(46, 0, 86, 10), image.Rect(0, 16, 40, 26)}
+	if got := layout(fl); !equalRects(got, want) {
+		t.Errorf("compact GapSpace M: %v, want %v", got, want)
+	}
+}
+
+func equalRects(a, b []image.Rectangle) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,751 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/unit"
+)
+
+// ParseStyle parses a block of CSS flex container declarations, such as
+//
+//	flex-direction: column; justify-content: space-between
+//
+// and returns a new Flex with those properties set.
+//
+// Supported properties are flex-direction, flex-wrap, flex-flow,
+// justify-content, align-items, align-content, gap, row-gap and
+// column-gap. Gaps must be px lengths, or for gap, a step of the
+// Spacing scale (xs, s, m, l or xl) that sets GapSpace.
+func ParseStyle(s string) (*Flex, error) {
+	fl := NewFlex()
+	if err := parseDecls(s, fl.setProperty); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+// ParseItemStyle parses a block of CSS flex item declarations, such as
+//
+//	flex: 1 0 120px; align-self: center
+//
+// and returns the equivalent LayoutData.
+//
+// Supported properties are flex, flex-grow, flex-shrink, flex-basis,
+// align-self, min-width, min-height, max-width, max-height,
+// break-after and z-index. Lengths must be in px, except for the
+// minimum and maximum sizes, which may use any unit.Value unit or a
+// percentage of the container; those set MinLength and MaxLength.
+//
+// CSS has no property for Offset, so it is never set by ParseItemStyle
+// nor written by FormatItemStyle. The JSON form of LayoutData keeps it.
+func ParseItemStyle(s string) (LayoutData, error) {
+	var d LayoutData
+	if err := parseDecls(s, d.setProperty); err != nil {
+		return LayoutData{}, err
+	}
+	return d, nil
+}
+
+// parseDecls splits s into declarations and calls set for each.
+// Property names are lower-cased, values are trimmed.
+func parseDecls(s string, set func(prop, val string) (ok bool, err error)) error {
+	for _, decl := range strings.Split(s, ";") {
+		decl = strings.TrimSpace(decl)
+		if decl == "" {
+			continue
+		}
+		i := strings.IndexByte(decl, ':')
+		if i < 0 {
+			return fmt.Errorf("flex: malformed declaration %q", decl)
+		}
+		prop := strings.ToLower(strings.TrimSpace(decl[:i]))
+		val := strings.TrimSpace(decl[i+1:])
+		ok, err := set(prop, val)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("flex: unknown property %q", prop)
+		}
+	}
+	return nil
+}
+
+var directionNames = [...]string{
+	Row:           "row",
+	RowReverse:    "row-reverse",
+	Column:        "column",
+	ColumnReverse: "column-reverse",
+}
+
+var flexWrapNames = [...]string{
+	NoWrap:      "nowrap",
+	Wrap:        "wrap",
+	WrapReverse: "wrap-reverse",
+}
+
+var justifyNames = [...]string{
+	JustifyStart:        "flex-start",
+	JustifyEnd:          "flex-end",
+	JustifyCenter:       "center",
+	JustifySpaceBetween: "space-between",
+	JustifySpaceAround:  "space-around",
+}
+
+var alignItemNames = [...]string{
+	AlignItemAuto:         "auto",
+	AlignItemStart:        "flex-start",
+	AlignItemEnd:          "flex-end",
+	AlignItemCenter:       "center",
+	AlignItemBaseline:     "baseline",
+	AlignItemStretch:      "stretch",
+	AlignItemLastBaseline: "last baseline",
+}
+
+var alignContentNames = [...]string{
+	AlignContentStretch:      "stretch",
+	AlignContentStart:        "flex-start",
+	AlignContentEnd:          "flex-end",
+	AlignContentCenter:       "center",
+	AlignContentSpaceBetween: "space-between",
+	AlignContentSpaceAround:  "space-around",
+}
+
+var basisNames = [...]string{
+	Auto:       "auto",
+	Content:    "content",
+	Definite:   "definite",
+	FitContent: "fit-content",
+}
+
+var compatNames = [...]string{
+	CompatCSS:  "css",
+	CompatYoga: "yoga",
+}
+
+func (d Direction) String() string    { return enumName(directionNames[:], "Direction", int(d)) }
+func (w FlexWrap) String() string     { return enumName(flexWrapNames[:], "FlexWrap", int(w)) }
+func (j Justify) String() string      { return enumName(justifyNames[:], "Justify", int(j)) }
+func (a AlignItem) String() string    { return enumName(alignItemNames[:], "AlignItem", int(a)) }
+func (a AlignContent) String() string { return enumName(alignContentNames[:], "AlignContent", int(a)) }
+func (b Basis) String() string        { return enumName(basisNames[:], "Basis", int(b)) }
+func (c Compat) String() string       { return enumName(compatNames[:], "Compat", int(c)) }
+
+func enumName(names []string, typ string, v int) string {
+	if v >= 0 && v < len(names) {
+		return names[v]
+	}
+	return fmt.Sprintf("%s(%d)", typ, v)
+}
+
+// keyword returns the index of val in names, or -1.
+func keyword(names []string, val string) int {
+	val = strings.ToLower(val)
+	for i, name := range names {
+		if name == val {
+			return i
+		}
+	}
+	return -1
+}
+
+func badValue(prop, val string) error {
+	return fmt.Errorf("flex: invalid value %q for %s", val, prop)
+}
+
+// setProperty sets a container property on fl.
+// It reports false if prop is not a flex container property.
+func (fl *Flex) setProperty(prop, val string) (ok bool, err error) {
+	switch prop {
+	case "flex-direction":
+		i := keyword(directionNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		fl.Direction = Direction(i)
+	case "flex-wrap":
+		i := keyword(flexWrapNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		fl.Wrap = FlexWrap(i)
+	case "flex-flow":
+		for _, f := range strings.Fields(val) {
+			if i := keyword(directionNames[:], f); i >= 0 {
+				fl.Direction = Direction(i)
+			} else if i := keyword(flexWrapNames[:], f); i >= 0 {
+				fl.Wrap = FlexWrap(i)
+			} else {
+				return true, badValue(prop, val)
+			}
+		}
+	case "justify-content":
+		i := keyword(justifyNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		fl.Justify = Justify(i)
+	case "align-items":
+		i := keyword(alignItemNames[:], val)
+		if i <= 0 { // auto is only valid for align-self
+			return true, badValue(prop, val)
+		}
+		fl.AlignItem = AlignItem(i)
+	case "align-content":
+		i := keyword(alignContentNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		fl.AlignContent = AlignContent(i)
+	case "gap":
+		f := strings.Fields(val)
+		if len(f) == 0 || len(f) > 2 {
+			return true, badValue(prop, val)
+		}
+		if i := keyword(spaceNames[1:], val); i >= 0 {
+			fl.GapSpace = Space(i + 1)
+			return true, nil
+		}
+		if fl.RowGap, err = parseGap(f[0]); err != nil {
+			return true, badValue(prop, val)
+		}
+		fl.ColumnGap = fl.RowGap
+		if len(f) == 2 {
+			if fl.ColumnGap, err = parseGap(f[1]); err != nil {
+				return true, badValue(prop, val)
+			}
+		}
+	case "row-gap":
+		if fl.RowGap, err = parseGap(val); err != nil {
+			return true, badValue(prop, val)
+		}
+	case "column-gap":
+		if fl.ColumnGap, err = parseGap(val); err != nil {
+			return true, badValue(prop, val)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// noMaxSize is the MaxSize dimension used when only one of max-width
+// and max-height is set.
+const noMaxSize = math.MaxInt32
+
+// setProperty sets an item property on d.
+// It reports false if prop is not a flex item property.
+func (d *LayoutData) setProperty(prop, val string) (ok bool, err error) {
+	switch prop {
+	case "flex":
+		err = d.setFlex(val)
+	case "flex-grow":
+		d.Grow, err = parseFactor(val)
+	case "flex-shrink":
+		var f float64
+		if f, err = parseFactor(val); err == nil {
+			d.Shrink = &f
+		}
+	case "flex-basis":
+		if isBasisClamp(val) {
+			d.BasisClamp, err = parseBasisClamp(val)
+			break
+		}
+		d.Basis, d.BasisPx, err = parseBasis(val)
+	case "align-self":
+		i := keyword(alignItemNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		d.Align = AlignItem(i)
+	case "min-width", "min-height":
+		var (
+			px int
+			l  Length
+		)
+		if px, l, err = parseSizeLength(val); err != nil {
+			break
+		}
+		d.MinLength = setLength(d.MinLength, prop == "min-width", l)
+		if prop == "min-width" {
+			d.MinSize.X = px
+		} else {
+			d.MinSize.Y = px
+		}
+	case "max-width", "max-height":
+		px := noMaxSize
+		var l Length
+		if !strings.EqualFold(val, "none") {
+			if px, l, err = parseSizeLength(val); err != nil {
+				break
+			}
+			if l.isSet() {
+				px = noMaxSize
+			}
+		}
+		d.MaxLength = setLength(d.MaxLength, prop == "max-width", l)
+		// Copy MaxSize rather than write through it, as it may be
+		// shared with other LayoutData.
+		max := image.Point{noMaxSize, noMaxSize}
+		if d.MaxSize != nil {
+			max = *d.MaxSize
+		}
+		if prop == "max-width" {
+			max.X = px
+		} else {
+			max.Y = px
+		}
+		d.MaxSize = nil
+		if max != (image.Point{noMaxSize, noMaxSize}) {
+			d.MaxSize = &max
+		}
+	case "z-index":
+		if strings.EqualFold(val, "auto") {
+			d.ZIndex = 0
+			break
+		}
+		d.ZIndex, err = strconv.Atoi(val)
+	case "margin":
+		d.Margin, err = parseMargin(val)
+	case "margin-top":
+		d.Margin.Top, err = parseMarginLength(val)
+	case "margin-right":
+		d.Margin.Right, err = parseMarginLength(val)
+	case "margin-bottom":
+		d.Margin.Bottom, err = parseMarginLength(val)
+	case "margin-left":
+		d.Margin.Left, err = parseMarginLength(val)
+	case "pointer-events":
+		i := keyword(pointerEventsNames[:], val)
+		if i < 0 {
+			return true, badValue(prop, val)
+		}
+		d.PointerEvents = PointerEvents(i)
+	case "break-after":
+		switch strings.ToLower(val) {
+		case "auto":
+			d.BreakAfter = false
+		case "always":
+			d.BreakAfter = true
+		default:
+			return true, badValue(prop, val)
+		}
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("flex: invalid value %q for %s: %v", val, prop, err)
+	}
+	return true, nil
+}
+
+// setFlex handles the 'flex' shorthand.
+//
+// https://www.w3.org/TR/css-flexbox-1/#flex-property
+func (d *LayoutData) setFlex(val string) error {
+	switch strings.ToLower(val) {
+	case "initial":
+		val = "0 1 auto"
+	case "auto":
+		val = "1 1 auto"
+	case "none":
+		val = "0 0 auto"
+	}
+	grow, shrink := 1.0, 1.0
+	basis, basisPx := Definite, 0 // omitted basis is 0
+	var factors []float64
+	haveBasis := false
+	fields := strings.Fields(val)
+	for i := 0; i < len(fields); i++ {
+		// A unitless number is a flex factor unless the factors have
+		// already been given, and the shrink factor must immediately
+		// follow the grow factor.
+		if n, err := parseFactor(fields[i]); err == nil && len(factors) == 0 {
+			factors = append(factors, n)
+			if i+1 < len(fields) {
+				if n, err := parseFactor(fields[i+1]); err == nil {
+					factors = append(factors, n)
+					i++
+				}
+			}
+			continue
+		}
+		if haveBasis {
+			return fmt.Errorf("unexpected %q", fields[i])
+		}
+		var err error
+		if basis, basisPx, err = parseBasis(fields[i]); err != nil {
+			return err
+		}
+		haveBasis = true
+	}
+	switch len(factors) {
+	case 0:
+		if !haveBasis {
+			return fmt.Errorf("empty value")
+		}
+	case 2:
+		shrink = factors[1]
+		fallthrough
+	case 1:
+		grow = factors[0]
+	}
+	d.Grow = grow
+	d.Shrink = &shrink
+	d.Basis, d.BasisPx = basis, basisPx
+	return nil
+}
+
+func parseFactor(val string) (float64, error) {
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", val)
+	}
+	if f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%q is not a non-negative number", val)
+	}
+	return f, nil
+}
+
+func parseBasis(val string) (Basis, int, error) {
+	switch strings.ToLower(val) {
+	case "auto":
+		return Auto, 0, nil
+	case "content":
+		return Content, 0, nil
+	}
+	if lower := strings.ToLower(val); strings.HasPrefix(lower, "fit-content(") && strings.HasSuffix(lower, ")") {
+		arg := val[len("fit-content(") : len(val)-1]
+		px, err := parseLength(strings.TrimSpace(arg))
+		if err != nil {
+			return Auto, 0, err
+		}
+		return FitContent, px, nil
+	}
+	px, err := parseLength(val)
+	if err != nil {
+		return Auto, 0, err
+	}
+	return Definite, px, nil
+}
+
+func isBasisClamp(val string) bool {
+	return strings.HasPrefix(strings.ToLower(val), "clamp(") && strings.HasSuffix(val, ")")
+}
+
+// parseBasisClamp parses clamp(min, preferred, max), where each
+// argument is a length in any unit or a percentage, and max may also be
+// none.
+func parseBasisClamp(val string) (*BasisClamp, error) {
+	args := strings.Split(val[len("clamp("):len(val)-1], ",")
+	if len(args) != 3 {
+		return nil, fmt.Errorf("clamp needs 3 arguments, not %d", len(args))
+	}
+	var l [3]Length
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if i == 2 && strings.EqualFold(arg, "none") {
+			break
+		}
+		px, sl, err := parseSizeLength(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !sl.isSet() {
+			sl = Length{Kind: LengthValue, Value: unit.Pixels(float64(px))}
+		}
+		l[i] = sl
+	}
+	return &BasisClamp{Min: l[0], Preferred: l[1], Max: l[2]}, nil
+}
+
+func formatBasisClamp(c BasisClamp) string {
+	max := "none"
+	if c.Max.isSet() {
+		max = formatSizeLength(c.Max)
+	}
+	return "clamp(" + formatSizeLength(c.Min) + ", " + formatSizeLength(c.Preferred) + ", " + max + ")"
+}
+
+// parseLength parses a CSS length in px, rounded to whole pixels.
+func parseLength(val string) (int, error) {
+	num := strings.TrimSuffix(strings.ToLower(val), "px")
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%q is not a px length", val)
+	}
+	if num == val && f != 0 {
+		return 0, fmt.Errorf("%q is missing a unit", val)
+	}
+	return int(math.Floor(f + 0.5)), nil
+}
+
+// parseGap parses a gap length, where normal means no gap.
+func parseGap(val string) (int, error) {
+	if strings.EqualFold(val, "normal") {
+		return 0, nil
+	}
+	return parseLength(val)
+}
+
+// parseMarginLength parses a px length that may be negative.
+func parseMarginLength(val string) (int, error) {
+	if strings.HasPrefix(val, "-") {
+		px, err := parseLength(val[1:])
+		return -px, err
+	}
+	return parseLength(val)
+}
+
+// parseMargin parses the 'margin' shorthand of one to four lengths, in
+// the CSS order top, right, bottom, left.
+func parseMargin(val string) (Insets, error) {
+	f := strings.Fields(val)
+	if len(f) == 0 || len(f) > 4 {
+		return Insets{}, fmt.Errorf("margin needs 1 to 4 lengths, not %d", len(f))
+	}
+	px := make([]int, len(f))
+	for i := range f {
+		var err error
+		if px[i], err = parseMarginLength(f[i]); err != nil {
+			return Insets{}, err
+		}
+	}
+	switch len(px) {
+	case 1:
+		return Insets{px[0], px[0], px[0], px[0]}, nil
+	case 2:
+		return Insets{px[0], px[1], px[0], px[1]}, nil
+	case 3:
+		return Insets{px[0], px[1], px[2], px[1]}, nil
+	}
+	return Insets{px[0], px[1], px[2], px[3]}, nil
+}
+
+// formatMargin returns the 'margin' shorthand for m, inverse of
+// parseMargin.
+func formatMargin(m Insets) string {
+	if m.Top == m.Bottom && m.Left == m.Right {
+		if m.Top == m.Left {
+			return formatLength(m.Top)
+		}
+		return formatLength(m.Top) + " " + formatLength(m.Right)
+	}
+	return formatLength(m.Top) + " " + formatLength(m.Right) + " " + formatLength(m.Bottom) + " " + formatLength(m.Left)
+}
+
+// parseSizeLength parses a minimum or maximum size. A px length is
+// returned as pixels; any other length is returned as a Length.
+func parseSizeLength(val string) (px int, l Length, err error) {
+	if px, err := parseLength(val); err == nil {
+		return px, Length{}, nil
+	}
+	if strings.HasSuffix(val, "%") {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, Length{}, fmt.Errorf("%q is not a percentage", val)
+		}
+		return 0, Length{Kind: LengthPercent, Percent: f}, nil
+	}
+	v, err := parseValue(val)
+	if err != nil {
+		return 0, Length{}, err
+	}
+	return 0, Length{Kind: LengthValue, Value: v}, nil
+}
+
+// setLength returns a copy of s with its width or height set to l, or
+// nil if neither dimension is set. s is not modified, as it may be
+// shared with other LayoutData, such as a DefaultLayoutData.
+func setLength(s *Size, width bool, l Length) *Size {
+	if s == nil {
+		s = new(Size)
+	} else {
+		c := *s
+		s = &c
+	}
+	if width {
+		s.Width = l
+	} else {
+		s.Height = l
+	}
+	if *s == (Size{}) {
+		return nil
+	}
+	return s
+}
+
+// FormatStyle returns the CSS declarations for the container
+// properties of fl, the inverse of ParseStyle.
+//
+// Properties with their initial value are omitted.
+func FormatStyle(fl *Flex) string {
+	var decls []string
+	add := func(prop string, v fmt.Stringer) {
+		decls = append(decls, prop+": "+v.String())
+	}
+	if fl.Direction != Row {
+		add("flex-direction", fl.Direction)
+	}
+	if fl.Wrap != NoWrap {
+		add("flex-wrap", fl.Wrap)
+	}
+	if fl.Justify != JustifyStart {
+		add("justify-content", fl.Justify)
+	}
+	if fl.AlignItem != AlignItemAuto {
+		add("align-items", fl.AlignItem)
+	}
+	if fl.AlignContent != AlignContentStretch {
+		add("align-content", fl.AlignContent)
+	}
+	if fl.GapSpace != SpaceNone {
+		add("gap", fl.GapSpace)
+	}
+	switch {
+	case fl.RowGap == fl.ColumnGap && fl.RowGap != 0:
+		decls = append(decls, "gap: "+formatLength(fl.RowGap))
+	case fl.RowGap != fl.ColumnGap:
+		decls = append(decls, "gap: "+formatLength(fl.RowGap)+" "+formatLength(fl.ColumnGap))
+	}
+	return strings.Join(decls, "; ")
+}
+
+// FormatItemStyle returns the CSS declarations for d, the inverse of
+// ParseItemStyle.
+//
+// The flex factors and basis are combined into the 'flex' shorthand,
+// and properties with their initial value are omitted.
+func FormatItemStyle(d LayoutData) string {
+	var decls []string
+	add := func(prop, val string) {
+		decls = append(decls, prop+": "+val)
+	}
+	shrink := 1.0
+	if d.Shrink != nil {
+		shrink = *d.Shrink
+	}
+	if d.Grow != 0 || d.Shrink != nil || d.Basis != Auto {
+		add("flex", formatFloat(d.Grow)+" "+formatFloat(shrink)+" "+formatBasis(d.Basis, d.BasisPx))
+	}
+	if d.BasisClamp != nil {
+		add("flex-basis", formatBasisClamp(*d.BasisClamp))
+	}
+	if d.Align != AlignItemAuto {
+		add("align-self", d.Align.String())
+	}
+	var minLen, maxLen Size
+	if d.MinLength != nil {
+		minLen = *d.MinLength
+	}
+	if d.MaxLength != nil {
+		maxLen = *d.MaxLength
+	}
+	if minLen.Width.isSet() {
+		add("min-width", formatSizeLength(minLen.Width))
+	} else if d.MinSize.X != 0 {
+		add("min-width", formatLength(d.MinSize.X))
+	}
+	if minLen.Height.isSet() {
+		add("min-height", formatSizeLength(minLen.Height))
+	} else if d.MinSize.Y != 0 {
+		add("min-height", formatLength(d.MinSize.Y))
+	}
+	if maxLen.Width.isSet() {
+		add("max-width", formatSizeLength(maxLen.Width))
+	} else if d.MaxSize != nil && d.MaxSize.X != noMaxSize {
+		add("max-width", formatLength(d.MaxSize.X))
+	}
+	if maxLen.Height.isSet() {
+		add("max-height", formatSizeLength(maxLen.Height))
+	} else if d.MaxSize != nil && d.MaxSize.Y != noMaxSize {
+		add("max-height", formatLength(d.MaxSize.Y))
+	}
+	if d.BreakAfter {
+		add("break-after", "always")
+	}
+	if d.ZIndex != 0 {
+		add("z-index", strconv.Itoa(d.ZIndex))
+	}
+	if d.Margin != (Insets{}) {
+		add("margin", formatMargin(d.Margin))
+	}
+	if d.PointerEvents != PointerAuto {
+		add("pointer-events", d.PointerEvents.String())
+	}
+	return strings.Join(decls, "; ")
+}
+
+// FormatStyleSheet returns a style sheet describing fl and its children
+// as an HTML document would, with the container as #container and its
+// children as #child0, #child1, and so on.
+//
+// The container is sized by its Rect and the children by their
+// MeasuredSize, so the result can be used to compare a layout with a
+// web browser.
+func FormatStyleSheet(fl *Flex) string {
+	buf := new(bytes.Buffer)
+	writeRule := func(selector string, size image.Point, first, style string) {
+		fmt.Fprintf(buf, "%s {\n", selector)
+		if first != "" {
+			fmt.Fprintf(buf, "\t%s;\n", first)
+		}
+		fmt.Fprintf(buf, "\twidth: %s;\n\theight: %s;\n", formatLength(size.X), formatLength(size.Y))
+		if style != "" {
+			fmt.Fprintf(buf, "\t%s;\n", strings.Replace(style, "; ", ";\n\t", -1))
+		}
+		fmt.Fprintf(buf, "}\n")
+	}
+	writeRule("#container", fl.Rect.Size(), "display: flex", FormatStyle(fl))
+	i := 0
+	for c := fl.FirstChild; c != nil; c = c.NextSibling {
+		style := FormatItemStyle(fl.itemData(c))
+		writeRule(fmt.Sprintf("#child%d", i), c.MeasuredSize, "", style)
+		i++
+	}
+	return buf.String()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatLength(px int) string {
+	return strconv.Itoa(px) + "px"
+}
+
+func formatSizeLength(l Length) string {
+	if l.Kind == LengthPercent {
+		return formatFloat(l.Percent) + "%"
+	}
+	for _, u := range unitSuffixes {
+		if u.unit == l.Value.U {
+			return formatFloat(l.Value.F) + u.suffix
+		}
+	}
+	return formatFloat(l.Value.F) + "px"
+}
+
+func formatBasis(b Basis, px int) string {
+	switch b {
+	case Auto:
+		return "auto"
+	case Content:
+		return "content"
+	case Definite:
+		return formatLength(px)
+	case FitContent:
+		return "fit-content(" + formatLength(px) + ")"
+	default:
+		panic(fmt.Sprintf("unknown flex-basis %v", b))
+	}
+}
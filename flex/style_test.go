@@ -0,0 +1,227 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func floatptr(f float64) *float64 { return &f }
+
+var parseStyleTests = []struct {
+	style string
+	want  Flex
+}{
+	{"", Flex{}},
+	{"flex-direction: column; justify-content: space-between", Flex{Direction: Column, Justify: JustifySpaceBetween}},
+	{"flex-flow: wrap-reverse row-reverse", Flex{Direction: RowReverse, Wrap: WrapReverse}},
+	{"FLEX-WRAP: Wrap;", Flex{Wrap: Wrap}},
+	{"align-items: center; align-content: space-around", Flex{AlignItem: AlignItemCenter, AlignContent: AlignContentSpaceAround}},
+	{"gap: 8px", Flex{RowGap: 8, ColumnGap: 8}},
+	{"gap: 4px 7px; row-gap: normal", Flex{ColumnGap: 7}},
+	{"row-gap: 3px; column-gap: 5.5px", Flex{RowGap: 3, ColumnGap: 6}},
+	{"gap: M", Flex{GapSpace: SpaceM}},
+	{"gap: xl; row-gap: 2px", Flex{GapSpace: SpaceXL, RowGap: 2}},
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, test := range parseStyleTests {
+		fl, err := ParseStyle(test.style)
+		if err != nil {
+			t.Errorf("ParseStyle(%q): %v", test.style, err)
+			continue
+		}
+		got := Flex{
+			Direction:    fl.Direction,
+			Wrap:         fl.Wrap,
+			Justify:      fl.Justify,
+			AlignItem:    fl.AlignItem,
+			AlignContent: fl.AlignContent,
+			RowGap:       fl.RowGap,
+			ColumnGap:    fl.ColumnGap,
+			GapSpace:     fl.GapSpace,
+		}
+		if got != test.want {
+			t.Errorf("ParseStyle(%q) = %+v, want %+v", test.style, got, test.want)
+		}
+	}
+}
+
+var parseItemStyleTests = []struct {
+	style string
+	want  LayoutData
+}{
+	{"", LayoutData{}},
+	{"flex: 1 0 120px", LayoutData{Grow: 1, Shrink: floatptr(0), Basis: Definite, BasisPx: 120}},
+	{"flex: 2", LayoutData{Grow: 2, Shrink: floatptr(1), Basis: Definite}},
+	{"flex: 2 3", LayoutData{Grow: 2, Shrink: floatptr(3), Basis: Definite}},
+	{"flex: 30px", LayoutData{Grow: 1, Shrink: floatptr(1), Basis: Definite, BasisPx: 30}},
+	{"flex: 30px 2 0", LayoutData{Grow: 2, Shrink: floatptr(0), Basis: Definite, BasisPx: 30}},
+	{"flex: 0 0 0", LayoutData{Shrink: floatptr(0), Basis: Definite}},
+	{"flex: auto", LayoutData{Grow: 1, Shrink: floatptr(1)}},
+	{"flex: none", LayoutData{Shrink: floatptr(0)}},
+	{"flex: initial", LayoutData{Shrink: floatptr(1)}},
+	{"flex-grow: 1.5; flex-shrink: 1; flex-basis: content", LayoutData{Grow: 1.5, Shrink: floatptr(1), Basis: Content}},
+	{"flex: 0 1 fit-content(120px)", LayoutData{Shrink: floatptr(1), Basis: FitContent, BasisPx: 120}},
+	{"flex-basis: clamp(100px, 30%, none)", LayoutData{BasisClamp: &BasisClamp{Min: Length{Kind: LengthValue, Value: unit.Pixels(100)}, Preferred: Length{Kind: LengthPercent, Percent: 30}}}},
+	{"flex: 1; flex-basis: clamp(2em, 50%, 20em)", LayoutData{Grow: 1, Shrink: floatptr(1), Basis: Definite, BasisClamp: &BasisClamp{Min: Length{Kind: LengthValue, Value: unit.Ems(2)}, Preferred: Length{Kind: LengthPercent, Percent: 50}, Max: Length{Kind: LengthValue, Value: unit.Ems(20)}}}},
+	{"align-self: flex-end; break-after: always", LayoutData{Align: AlignItemEnd, BreakAfter: true}},
+	{"min-width: 10px; min-height: 0; max-height: 20.4px", LayoutData{MinSize: size(10, 0), MaxSize: sizeptr(noMaxSize, 20)}},
+	{"max-width: 5px; max-height: 6px", LayoutData{MaxSize: sizeptr(5, 6)}},
+	{"max-width: 5px; max-width: none", LayoutData{}},
+	{"min-width: 2em; min-height: 4px", LayoutData{MinSize: size(0, 4), MinLength: &Size{Width: Length{Kind: LengthValue, Value: unit.Ems(2)}}}},
+	{"max-height: 50%; max-width: 10px", LayoutData{MaxSize: sizeptr(10, noMaxSize), MaxLength: &Size{Height: Length{Kind: LengthPercent, Percent: 50}}}},
+	{"max-width: 0%", LayoutData{MaxLength: &Size{Width: Length{Kind: LengthPercent}}}},
+	{"min-width: 3dp; min-width: 3px", LayoutData{MinSize: size(3, 0)}},
+	{"z-index: -2", LayoutData{ZIndex: -2}},
+	{"z-index: 4; z-index: auto", LayoutData{}},
+	{"pointer-events: none", LayoutData{PointerEvents: PointerNone}},
+	{"pointer-events: none; pointer-events: auto", LayoutData{}},
+	{"margin: 4px", LayoutData{Margin: Insets{4, 4, 4, 4}}},
+	{"margin: 0 -12px", LayoutData{Margin: Insets{0, -12, 0, -12}}},
+	{"margin: 1px 2px 3px", LayoutData{Margin: Insets{1, 2, 3, 2}}},
+	{"margin: 1px 2px 3px 4px; margin-left: -8px", LayoutData{Margin: Insets{1, 2, 3, -8}}},
+}
+
+func TestParseItemStyle(t *testing.T) {
+	for _, test := range parseItemStyleTests {
+		got, err := ParseItemStyle(test.style)
+		if err != nil {
+			t.Errorf("ParseItemStyle(%q): %v", test.style, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseItemStyle(%q) = %+v, want %+v", test.style, got, test.want)
+		}
+	}
+}
+
+func TestParseStyleErrors(t *testing.T) {
+	for _, style := range []string{
+		"flex-direction",
+		"flex-direction: sideways",
+		"align-items: auto",
+		"flex: 1",
+		"color: red",
+		"gap: 1px 2px 3px",
+		"row-gap: -1px",
+	} {
+		if _, err := ParseStyle(style); err == nil {
+			t.Errorf("ParseStyle(%q) succeeded, want error", style)
+		}
+	}
+	for _, style := range []string{
+		"flex: 1 2 3",
+		"flex: 10px 20px",
+		"flex: 1 10px 2",
+		"flex:",
+		"flex-grow: -1",
+		"flex-basis: 3em",
+		"min-width: -4px",
+		"max-height: -1%",
+		"min-height: 3furlongs",
+		"z-index: 1.5",
+		"pointer-events: visible",
+		"margin: 1px 2px 3px 4px 5px",
+		"margin: auto",
+		"margin-top: --1px",
+		"justify-content: center",
+	} {
+		if _, err := ParseItemStyle(style); err == nil {
+			t.Errorf("ParseItemStyle(%q) succeeded, want error", style)
+		}
+	}
+}
+
+func TestFormatItemStyle(t *testing.T) {
+	tests := []struct {
+		d    LayoutData
+		want string
+	}{
+		{LayoutData{}, ""},
+		{LayoutData{Grow: 1, Shrink: floatptr(0), Basis: Definite, BasisPx: 120}, "flex: 1 0 120px"},
+		{LayoutData{Grow: 0.5}, "flex: 0.5 1 auto"},
+		{LayoutData{Shrink: floatptr(1)}, "flex: 0 1 auto"},
+		{LayoutData{Align: AlignItemCenter, MinSize: size(3, 4), MaxSize: sizeptr(noMaxSize, 9)}, "align-self: center; min-width: 3px; min-height: 4px; max-height: 9px"},
+		{LayoutData{Basis: Content, BreakAfter: true}, "flex: 0 1 content; break-after: always"},
+		{LayoutData{ZIndex: 2, PointerEvents: PointerNone}, "z-index: 2; pointer-events: none"},
+		{LayoutData{Margin: Insets{0, -12, 0, -12}}, "margin: 0px -12px"},
+		{LayoutData{Margin: Insets{1, 2, 3, 4}}, "margin: 1px 2px 3px 4px"},
+	}
+	for _, test := range tests {
+		if got := FormatItemStyle(test.d); got != test.want {
+			t.Errorf("FormatItemStyle(%+v) = %q, want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestStyleRoundTrip(t *testing.T) {
+	for _, test := range parseStyleTests {
+		fl := NewFlex()
+		fl.Direction = test.want.Direction
+		fl.Wrap = test.want.Wrap
+		fl.Justify = test.want.Justify
+		fl.AlignItem = test.want.AlignItem
+		fl.AlignContent = test.want.AlignContent
+		fl.RowGap = test.want.RowGap
+		fl.ColumnGap = test.want.ColumnGap
+		fl.GapSpace = test.want.GapSpace
+		s := FormatStyle(fl)
+		got, err := ParseStyle(s)
+		if err != nil {
+			t.Errorf("ParseStyle(%q): %v", s, err)
+			continue
+		}
+		if FormatStyle(got) != s {
+			t.Errorf("round trip of %q = %q", s, FormatStyle(got))
+		}
+	}
+	for _, test := range parseItemStyleTests {
+		s := FormatItemStyle(test.want)
+		got, err := ParseItemStyle(s)
+		if err != nil {
+			t.Errorf("ParseItemStyle(%q): %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("round trip of %+v via %q = %+v", test.want, s, got)
+		}
+	}
+}
+
+func TestFormatStyleSheet(t *testing.T) {
+	fl := NewFlex()
+	fl.Direction = Column
+	fl.Rect = image.Rectangle{Max: image.Pt(300, 200)}
+	c0 := &widget.Node{MeasuredSize: image.Pt(10, 20)}
+	c1 := &widget.Node{MeasuredSize: image.Pt(30, 40), LayoutData: LayoutData{Grow: 2}}
+	fl.AppendChild(c0)
+	fl.AppendChild(c1)
+
+	want := `#container {
+	display: flex;
+	width: 300px;
+	height: 200px;
+	flex-direction: column;
+}
+#child0 {
+	width: 10px;
+	height: 20px;
+}
+#child1 {
+	width: 30px;
+	height: 40px;
+	flex: 2 1 auto;
+}
+`
+	if got := FormatStyleSheet(fl); got != want {
+		t.Errorf("FormatStyleSheet:\n%s\nwant:\n%s", got, want)
+	}
+}
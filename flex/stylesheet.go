@@ -0,0 +1,332 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget"
+)
+
+// A StyleSheet is a list of rules, each setting properties of the
+// nodes of a tree that match its selectors. It lets the look of a tree
+// be tuned apart from the code that builds it.
+//
+// A style sheet is written in a subset of CSS:
+//
+//	flex.toolbar { gap: s; align-items: center }
+//	.toolbar > * { flex: 0 0 auto }
+//	#sidebar, #inspector { min-width: 120px; density: compact }
+//
+// A selector is a sequence of simple selectors joined by descendant
+// (space) or child (>) combinators. A simple selector is * or a type,
+// followed by any number of #id and .class names, as given for each
+// node by NodeNames.
+//
+// Rules accept the container properties of ParseStyle, which apply to
+// Flex nodes only, and the item properties of ParseItemStyle, which
+// set the node's LayoutData. The density property, one of normal,
+// compact or comfortable, gives a Flex a copy of DefaultSpacing with
+// that Density.
+//
+// As in CSS, rules with more specific selectors win, and of those
+// equally specific, later rules win.
+type StyleSheet struct {
+	rules []styleRule
+}
+
+type styleRule struct {
+	sel   []compound // the last is the subject; each other is followed by its combinator
+	spec  [3]int     // ids, classes, types
+	order int
+	decls [][2]string
+}
+
+type compound struct {
+	typ     string // "" matches any type
+	id      string
+	classes []string
+	child   bool // the next compound must match a child, not any descendant
+}
+
+// NodeNames are the names a StyleSheet matches a node by.
+type NodeNames struct {
+	Type    string
+	ID      string
+	Classes []string
+}
+
+// ParseStyleSheet parses a style sheet.
+func ParseStyleSheet(s string) (*StyleSheet, error) {
+	s = stripComments(s)
+	ss := new(StyleSheet)
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return ss, nil
+		}
+		open := strings.IndexByte(s, '{')
+		close := strings.IndexByte(s, '}')
+		if open < 0 || close < open {
+			return nil, fmt.Errorf("flex: malformed rule %q", s)
+		}
+		selectors, body := s[:open], s[open+1:close]
+		s = s[close+1:]
+
+		var decls [][2]string
+		err := parseDecls(body, func(prop, val string) (bool, error) {
+			ok, err := checkProperty(prop, val)
+			if ok && err == nil {
+				decls = append(decls, [2]string{prop, val})
+			}
+			return ok, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, sel := range strings.Split(selectors, ",") {
+			r, err := parseSelector(sel)
+			if err != nil {
+				return nil, err
+			}
+			r.order = len(ss.rules)
+			r.decls = decls
+			ss.rules = append(ss.rules, r)
+		}
+	}
+}
+
+func stripComments(s string) string {
+	for {
+		i := strings.Index(s, "/*")
+		if i < 0 {
+			return s
+		}
+		j := strings.Index(s[i+2:], "*/")
+		if j < 0 {
+			return s[:i]
+		}
+		s = s[:i] + " " + s[i+2+j+2:]
+	}
+}
+
+// checkProperty reports whether prop is a style sheet property, and
+// whether val is valid for it.
+func checkProperty(prop, val string) (bool, error) {
+	if prop == "density" {
+		if keyword(densityNames[:], val) < 0 {
+			return true, badValue(prop, val)
+		}
+		return true, nil
+	}
+	if ok, err := NewFlex().setProperty(prop, val); ok {
+		return ok, err
+	}
+	return new(LayoutData).setProperty(prop, val)
+}
+
+func parseSelector(sel string) (styleRule, error) {
+	var r styleRule
+	fields := strings.Fields(strings.Replace(sel, ">", " > ", -1))
+	if len(fields) == 0 {
+		return r, fmt.Errorf("flex: empty selector")
+	}
+	for i, f := range fields {
+		if f == ">" {
+			if i == 0 || i == len(fields)-1 || fields[i-1] == ">" {
+				return r, fmt.Errorf("flex: malformed selector %q", sel)
+			}
+			r.sel[len(r.sel)-1].child = true
+			continue
+		}
+		c, err := parseCompound(f)
+		if err != nil {
+			return r, fmt.Errorf("flex: malformed selector %q", sel)
+		}
+		if c.id != "" {
+			r.spec[0]++
+		}
+		r.spec[1] += len(c.classes)
+		if c.typ != "" {
+			r.spec[2]++
+		}
+		r.sel = append(r.sel, c)
+	}
+	return r, nil
+}
+
+func parseCompound(s string) (compound, error) {
+	var c compound
+	i := strings.IndexAny(s, "#.")
+	if i < 0 {
+		i = len(s)
+	}
+	if c.typ = s[:i]; c.typ == "*" {
+		c.typ = ""
+	}
+	s = s[i:]
+	for s != "" {
+		kind := s[0]
+		s = s[1:]
+		j := strings.IndexAny(s, "#.")
+		if j < 0 {
+			j = len(s)
+		}
+		name := s[:j]
+		s = s[j:]
+		if name == "" {
+			return c, fmt.Errorf("empty name")
+		}
+		if kind == '#' {
+			c.id = name
+		} else {
+			c.classes = append(c.classes, name)
+		}
+	}
+	return c, nil
+}
+
+func (c *compound) matches(n NodeNames) bool {
+	if c.typ != "" && c.typ != n.Type {
+		return false
+	}
+	if c.id != "" && c.id != n.ID {
+		return false
+	}
+	for _, want := range c.classes {
+		found := false
+		for _, class := range n.Classes {
+			if class == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSelector reports whether sel matches the last of path, the
+// names of a node and its ancestors from the root down.
+func matchSelector(sel []compound, path []NodeNames) bool {
+	last := len(sel) - 1
+	if len(path) == 0 || !sel[last].matches(path[len(path)-1]) {
+		return false
+	}
+	if last == 0 {
+		return true
+	}
+	sel, path = sel[:last], path[:len(path)-1]
+	if sel[len(sel)-1].child {
+		return matchSelector(sel, path)
+	}
+	for i := len(path); i > 0; i-- {
+		if matchSelector(sel, path[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultNodeNames gives a Flex the type "flex", and other nodes no
+// names.
+func DefaultNodeNames(n *widget.Node) NodeNames {
+	if _, ok := n.Class.(*flexClass); ok {
+		return NodeNames{Type: "flex"}
+	}
+	return NodeNames{}
+}
+
+// Apply sets the properties of the nodes of the tree rooted at root
+// from the rules that match them. names gives the names of each node;
+// if nil, DefaultNodeNames is used.
+//
+// Properties not set by a rule keep their values, so Apply can follow
+// the code that builds a tree, and be repeated to apply another sheet.
+func (ss *StyleSheet) Apply(root *widget.Node, names func(n *widget.Node) NodeNames) {
+	if names == nil {
+		names = DefaultNodeNames
+	}
+	var walk func(n *widget.Node, path []NodeNames)
+	walk = func(n *widget.Node, path []NodeNames) {
+		path = append(path, names(n))
+		ss.applyNode(n, path)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, path)
+		}
+	}
+	walk(root, nil)
+}
+
+func (ss *StyleSheet) applyNode(n *widget.Node, path []NodeNames) {
+	var matched []*styleRule
+	for i := range ss.rules {
+		if r := &ss.rules[i]; matchSelector(r.sel, path) {
+			matched = append(matched, r)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	sort.Sort(byCascade(matched))
+
+	var fl *Flex
+	if k, ok := n.Class.(*flexClass); ok {
+		fl = k.flex
+	}
+	d, haveD := n.LayoutData.(LayoutData)
+	if !haveD && n.Parent != nil {
+		if k, ok := n.Parent.Class.(*flexClass); ok {
+			d = k.flex.itemData(n)
+		}
+	}
+	setD := false
+	for _, r := range matched {
+		for _, decl := range r.decls {
+			prop, val := decl[0], decl[1]
+			if prop == "density" {
+				if fl != nil {
+					sp := DefaultSpacing
+					if fl.Spacing != nil {
+						sp = *fl.Spacing
+					}
+					sp.Density = Density(keyword(densityNames[:], val))
+					fl.Spacing = &sp
+				}
+				continue
+			}
+			if fl != nil {
+				if ok, _ := fl.setProperty(prop, val); ok {
+					continue
+				}
+			}
+			if ok, _ := d.setProperty(prop, val); ok {
+				setD = true
+			}
+		}
+	}
+	if setD {
+		n.LayoutData = d
+	}
+}
+
+// byCascade sorts rules from the least to the most specific, and rules
+// equally specific in source order.
+type byCascade []*styleRule
+
+func (b byCascade) Len() int      { return len(b) }
+func (b byCascade) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byCascade) Less(i, j int) bool {
+	for k := range b[i].spec {
+		if b[i].spec[k] != b[j].spec[k] {
+			return b[i].spec[k] < b[j].spec[k]
+		}
+	}
+	return b[i].order < b[j].order
+}
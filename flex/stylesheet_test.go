@@ -0,0 +1,136 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+const styleSheetTestDoc = `{
+	"type": "flex",
+	"id": "root",
+	"children": [
+		{"type": "flex", "id": "toolbar", "class": "bar compact", "children": [
+			{"type": "uniform", "id": "a", "class": "tool"},
+			{"type": "uniform", "id": "b", "class": "tool wide", "style": "flex-grow: 5"}
+		]},
+		{"type": "flex", "id": "body", "children": [
+			{"type": "flex", "id": "inner", "children": [
+				{"type": "uniform", "id": "c", "class": "tool"}
+			]}
+		]}
+	]
+}`
+
+func TestStyleSheet(t *testing.T) {
+	tree, err := Load(strings.NewReader(styleSheetTestDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := ParseStyleSheet(`
+		/* Later rules win among equally specific ones. */
+		flex { flex-direction: column }
+		flex.bar { flex-direction: row; gap: s; density: compact }
+		#root { flex-direction: row-reverse }
+		.bar > .tool { flex-grow: 1; min-width: 10px }
+		.tool.wide { flex-grow: 2 }
+		#body .tool { align-self: center }
+		flex > .tool { z-index: 1 }
+		flex > .tool { z-index: 2 }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.Apply(tree.Root, tree.Names)
+
+	flexOf := func(id string) *Flex { return tree.ByID[id].Class.(*flexClass).flex }
+	data := func(id string) LayoutData {
+		d, _ := tree.ByID[id].LayoutData.(LayoutData)
+		return d
+	}
+	if got := flexOf("root").Direction; got != RowReverse {
+		t.Errorf("root Direction=%v, want row-reverse", got)
+	}
+	bar := flexOf("toolbar")
+	if bar.Direction != Row || bar.GapSpace != SpaceS {
+		t.Errorf("toolbar Direction=%v GapSpace=%v, want row, s", bar.Direction, bar.GapSpace)
+	}
+	if bar.Spacing == nil || bar.Spacing.Density != DensityCompact || bar.Spacing.M != DefaultSpacing.M {
+		t.Errorf("toolbar Spacing=%+v, want the default, compact", bar.Spacing)
+	}
+	if got := flexOf("inner").Direction; got != Column {
+		t.Errorf("inner Direction=%v, want column", got)
+	}
+
+	if d := data("a"); d.Grow != 1 || d.MinSize.X != 10 || d.ZIndex != 2 {
+		t.Errorf("a LayoutData=%+v", d)
+	}
+	// The more specific .tool.wide wins over the later .bar > .tool, and
+	// both over the Doc's style.
+	if d := data("b"); d.Grow != 2 {
+		t.Errorf("b Grow=%g, want 2", d.Grow)
+	}
+	if d := data("c"); d.Grow != 0 || d.Align != AlignItemCenter || d.ZIndex != 2 {
+		t.Errorf("c LayoutData=%+v", d)
+	}
+	if _, ok := tree.ByID["body"].LayoutData.(LayoutData); ok {
+		t.Error("body has LayoutData, want none")
+	}
+}
+
+// TestStyleSheetShared checks that a rule does not write through the
+// pointers of a DefaultLayoutData that its node shares with others.
+func TestStyleSheetShared(t *testing.T) {
+	fl := NewFlex()
+	fl.DefaultLayoutData = &LayoutData{
+		MaxSize:   &image.Point{100, 100},
+		MinLength: &Size{Width: Length{Kind: LengthPercent, Percent: 10}},
+	}
+	a := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	b := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	ids := map[*widget.Node]string{a: "a", b: "b"}
+
+	ss, err := ParseStyleSheet(`#a { max-width: 20px; min-height: 2em }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.Apply(&fl.Node, func(n *widget.Node) NodeNames { return NodeNames{ID: ids[n]} })
+
+	def := fl.DefaultLayoutData
+	if *def.MaxSize != (image.Point{100, 100}) || *def.MinLength != (Size{Width: Length{Kind: LengthPercent, Percent: 10}}) {
+		t.Errorf("DefaultLayoutData changed to MaxSize=%v MinLength=%+v", *def.MaxSize, *def.MinLength)
+	}
+	if d := fl.itemData(a); *d.MaxSize != (image.Point{20, 100}) || d.MinLength.Height.Kind != LengthValue {
+		t.Errorf("a MaxSize=%v MinLength=%+v", *d.MaxSize, *d.MinLength)
+	}
+	if d := fl.itemData(b); *d.MaxSize != (image.Point{100, 100}) || d.MinLength.Height.isSet() {
+		t.Errorf("b MaxSize=%v MinLength=%+v", *d.MaxSize, *d.MinLength)
+	}
+}
+
+func TestParseStyleSheetErrors(t *testing.T) {
+	for _, s := range []string{
+		"flex { flex-direction: sideways }",
+		"flex { colour: red }",
+		"flex { density: tiny }",
+		"flex flex-direction: row }",
+		"> flex { flex-grow: 1 }",
+		"a > > b { flex-grow: 1 }",
+		".{ flex-grow: 1 }",
+		", a { flex-grow: 1 }",
+	} {
+		if _, err := ParseStyleSheet(s); err == nil {
+			t.Errorf("ParseStyleSheet(%q) succeeded", s)
+		}
+	}
+}
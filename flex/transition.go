@@ -0,0 +1,142 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"time"
+
+	"golang.org/x/exp/shiny/widget"
+
+	"github.com/crawshaw/exp/flex/anim"
+)
+
+// Transition animates nodes between layouts. Instead of snapping to
+// the Rect computed by a new layout, each node whose Rect changed
+// glides there from where it was last shown.
+//
+// A typical event loop calls Layout when the tree changes and Step
+// before every paint:
+//
+//	tr := &flex.Transition{
+//		Duration: 200 * time.Millisecond,
+//		Repaint:  func() { w.Send(paint.Event{}) },
+//	}
+//	...
+//	case paint.Event:
+//		tr.Step(time.Now())
+//		root.Class.Paint(root, theme, buf.RGBA(), image.Point{})
+//
+// An event loop that steps other animations with an anim.Ticker can
+// add tr.Step to it after each Layout instead.
+//
+// Nodes without a previous Rect, such as those added since the last
+// layout, appear at their new Rect immediately.
+type Transition struct {
+	// Duration is the length of each animation. If zero, nodes snap
+	// to their new Rect.
+	Duration time.Duration
+
+	// Ease maps the linear progress of an animation, in [0, 1], to
+	// the fraction of the distance travelled. If nil, EaseInOut is
+	// used.
+	Ease func(t float64) float64
+
+	// Repaint, if non-nil, is called by Step while any node is still
+	// moving, so that another frame is scheduled.
+	Repaint func()
+
+	anims map[*widget.Node]*rectAnim
+}
+
+type rectAnim struct {
+	from, to image.Rectangle
+	tween    anim.Tween
+}
+
+// EaseLinear moves at a constant speed. It is anim.Linear.
+func EaseLinear(t float64) float64 { return anim.Linear(t) }
+
+// EaseInOut accelerates from rest and decelerates to rest. It is
+// anim.InOut.
+func EaseInOut(t float64) float64 { return anim.InOut(t) }
+
+// Layout lays out the tree rooted at n with n's Class, and starts an
+// animation at time now for every descendant of n whose Rect changed.
+// It then calls Step(now), so that the tree is shown as it was before
+// the layout.
+//
+// An animation interrupted by a new layout continues from the node's
+// current position.
+func (tr *Transition) Layout(n *widget.Node, t *widget.Theme, now time.Time) {
+	shown := make(map[*widget.Node]image.Rectangle)
+	walkDescendants(n, func(c *widget.Node) { shown[c] = c.Rect })
+
+	n.Class.Layout(n, t)
+
+	if tr.anims == nil {
+		tr.anims = make(map[*widget.Node]*rectAnim)
+	}
+	live := make(map[*widget.Node]bool)
+	walkDescendants(n, func(c *widget.Node) {
+		live[c] = true
+		from, ok := shown[c]
+		if !ok || from == (image.Rectangle{}) || from == c.Rect || tr.Duration <= 0 {
+			delete(tr.anims, c)
+			return
+		}
+		tr.anims[c] = &rectAnim{
+			from:  from,
+			to:    c.Rect,
+			tween: anim.Tween{Start: now, Duration: tr.Duration, Ease: tr.Ease},
+		}
+	})
+	for c := range tr.anims {
+		if !live[c] {
+			delete(tr.anims, c)
+		}
+	}
+	tr.Step(now)
+}
+
+// Step moves every animating node to its position at time now, and
+// reports whether any node is still moving.
+func (tr *Transition) Step(now time.Time) bool {
+	for c, a := range tr.anims {
+		if a.tween.Done(now) {
+			c.Rect = a.to
+			delete(tr.anims, c)
+			continue
+		}
+		c.Rect = anim.LerpRect(a.from, a.to, a.tween.Progress(now))
+	}
+	if len(tr.anims) == 0 {
+		return false
+	}
+	if tr.Repaint != nil {
+		tr.Repaint()
+	}
+	return true
+}
+
+// Animating reports whether any node is moving.
+func (tr *Transition) Animating() bool {
+	return len(tr.anims) > 0
+}
+
+// Finish moves every animating node to its final Rect.
+func (tr *Transition) Finish() {
+	for c, a := range tr.anims {
+		c.Rect = a.to
+		delete(tr.anims, c)
+	}
+}
+
+func walkDescendants(n *widget.Node, f func(*widget.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		f(c)
+		walkDescendants(c, f)
+	}
+}
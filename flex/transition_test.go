@@ -0,0 +1,74 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestTransition(t *testing.T) {
+	fl := NewFlex()
+	a := widget.NewUniform(color.Black, unit.Pixels(100), unit.Pixels(100)).Node
+	b := widget.NewUniform(color.White, unit.Pixels(100), unit.Pixels(100)).Node
+	fl.AppendChild(a)
+	fl.AppendChild(b)
+	fl.Rect = image.Rectangle{Max: image.Pt(300, 100)}
+	fl.Class.Measure(&fl.Node, nil)
+
+	repaints := 0
+	tr := &Transition{
+		Duration: time.Second,
+		Ease:     EaseLinear,
+		Repaint:  func() { repaints++ },
+	}
+	t0 := time.Unix(1000, 0)
+	tr.Layout(&fl.Node, nil, t0)
+	if tr.Animating() {
+		t.Fatal("first layout is animating")
+	}
+	if want := image.Rect(100, 0, 200, 100); b.Rect != want {
+		t.Fatalf("b.Rect=%v, want %v", b.Rect, want)
+	}
+
+	a.LayoutData = LayoutData{Grow: 1}
+	tr.Layout(&fl.Node, nil, t0)
+	for _, test := range []struct {
+		d     time.Duration
+		a, b  image.Rectangle
+		still bool
+	}{
+		{0, image.Rect(0, 0, 100, 100), image.Rect(100, 0, 200, 100), true},
+		{time.Second / 4, image.Rect(0, 0, 125, 100), image.Rect(125, 0, 225, 100), true},
+		{time.Second, image.Rect(0, 0, 200, 100), image.Rect(200, 0, 300, 100), false},
+	} {
+		still := tr.Step(t0.Add(test.d))
+		if a.Rect != test.a || b.Rect != test.b || still != test.still {
+			t.Errorf("at %v: a=%v, b=%v, moving=%v; want %v, %v, %v", test.d, a.Rect, b.Rect, still, test.a, test.b, test.still)
+		}
+	}
+	if repaints != 3 {
+		t.Errorf("repaints=%d, want 3", repaints)
+	}
+
+	// Interrupting an animation starts from the current position.
+	a.LayoutData = nil
+	tr.Layout(&fl.Node, nil, t0)
+	tr.Step(t0.Add(time.Second / 2))
+	a.LayoutData = LayoutData{Grow: 1}
+	tr.Layout(&fl.Node, nil, t0.Add(time.Second/2))
+	if want := image.Rect(0, 0, 150, 100); a.Rect != want {
+		t.Errorf("after interruption a.Rect=%v, want %v", a.Rect, want)
+	}
+	tr.Finish()
+	if want := image.Rect(0, 0, 200, 100); a.Rect != want || tr.Animating() {
+		t.Errorf("after Finish a.Rect=%v, animating=%v", a.Rect, tr.Animating())
+	}
+}
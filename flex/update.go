@@ -0,0 +1,99 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import "golang.org/x/exp/shiny/widget"
+
+type invalidateObserver struct {
+	f func()
+}
+
+// NotifyInvalidate arranges for f to be called whenever fl is
+// invalidated, so that the caller can schedule a new layout and paint.
+//
+// NotifyInvalidate returns a function that cancels the subscription.
+func (fl *Flex) NotifyInvalidate(f func()) (cancel func()) {
+	k := fl.Class.(*flexClass)
+	o := &invalidateObserver{f: f}
+	k.invalidators = append(k.invalidators, o)
+	return func() {
+		for i, x := range k.invalidators {
+			if x == o {
+				k.invalidators = append(k.invalidators[:i:i], k.invalidators[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Invalidate records that the children of fl, their LayoutData or the
+// properties of fl itself have changed, and calls the functions
+// registered with NotifyInvalidate. Between BeginUpdate and EndUpdate
+// the call is deferred to EndUpdate.
+func (fl *Flex) Invalidate() {
+	k := fl.Class.(*flexClass)
+	if k.updates > 0 {
+		k.invalid = true
+		return
+	}
+	for _, o := range append([]*invalidateObserver(nil), k.invalidators...) {
+		o.f()
+	}
+}
+
+// BeginUpdate starts a batch of changes to fl. Until the matching
+// EndUpdate, calls to Invalidate, including those made by AppendChild,
+// InsertBefore, RemoveChild and SetLayoutData, are collected into one.
+//
+// Calls to BeginUpdate nest: only the outermost EndUpdate invalidates.
+func (fl *Flex) BeginUpdate() {
+	fl.Class.(*flexClass).updates++
+}
+
+// EndUpdate ends a batch of changes started by BeginUpdate. If the
+// batch is the outermost one and anything in it invalidated fl, fl is
+// invalidated once.
+func (fl *Flex) EndUpdate() {
+	k := fl.Class.(*flexClass)
+	if k.updates == 0 {
+		panic("flex: EndUpdate without BeginUpdate")
+	}
+	k.updates--
+	if k.updates == 0 && k.invalid {
+		k.invalid = false
+		fl.Invalidate()
+	}
+}
+
+// Updating reports whether fl is between BeginUpdate and EndUpdate.
+func (fl *Flex) Updating() bool {
+	return fl.Class.(*flexClass).updates > 0
+}
+
+// AppendChild adds c as the last child of fl, and invalidates fl.
+func (fl *Flex) AppendChild(c *widget.Node) {
+	fl.Node.AppendChild(c)
+	fl.Invalidate()
+}
+
+// InsertBefore inserts c as a child of fl before nextSibling, and
+// invalidates fl.
+func (fl *Flex) InsertBefore(c, nextSibling *widget.Node) {
+	fl.Node.InsertBefore(c, nextSibling)
+	fl.Invalidate()
+}
+
+// RemoveChild removes c, a child of fl, and invalidates fl.
+func (fl *Flex) RemoveChild(c *widget.Node) {
+	fl.Node.RemoveChild(c)
+	fl.Invalidate()
+}
+
+// SetLayoutData sets the LayoutData of c, a child of fl, and
+// invalidates fl.
+func (fl *Flex) SetLayoutData(c *widget.Node, d LayoutData) {
+	c.LayoutData = d
+	fl.Invalidate()
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+)
+
+func TestBeginUpdate(t *testing.T) {
+	fl := NewFlex()
+	calls := 0
+	cancel := fl.NotifyInvalidate(func() { calls++ })
+
+	newNode := func() *widget.Node {
+		return widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	}
+	a := newNode()
+	fl.AppendChild(a)
+	if calls != 1 {
+		t.Fatalf("AppendChild: %d invalidations, want 1", calls)
+	}
+
+	fl.BeginUpdate()
+	b, c := newNode(), newNode()
+	fl.AppendChild(b)
+	fl.InsertBefore(c, b)
+	fl.BeginUpdate() // nested
+	fl.SetLayoutData(a, LayoutData{Grow: 1})
+	fl.RemoveChild(b)
+	fl.EndUpdate()
+	if calls != 1 || !fl.Updating() {
+		t.Errorf("inside batch: %d invalidations, Updating=%t", calls, fl.Updating())
+	}
+	fl.EndUpdate()
+	if calls != 2 || fl.Updating() {
+		t.Errorf("after batch: %d invalidations, Updating=%t", calls, fl.Updating())
+	}
+	if a.NextSibling != c || c.NextSibling != nil {
+		t.Error("children not updated")
+	}
+
+	// A batch with no changes does not invalidate.
+	fl.BeginUpdate()
+	fl.EndUpdate()
+	if calls != 2 {
+		t.Errorf("empty batch invalidated")
+	}
+
+	cancel()
+	fl.Invalidate()
+	if calls != 2 {
+		t.Errorf("observer called after cancel")
+	}
+}
+
+func TestEndUpdateUnbalanced(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("EndUpdate without BeginUpdate did not panic")
+		}
+	}()
+	NewFlex().EndUpdate()
+}
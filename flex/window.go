@@ -0,0 +1,171 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/paint"
+	sizeevent "golang.org/x/mobile/event/size"
+)
+
+// WindowOptions are optional arguments to RunWindow.
+type WindowOptions struct {
+	// Width and Height are the initial window size, in pixels.
+	Width, Height int
+
+	// Theme is passed to Measure, Layout and Paint. Its DPI is
+	// updated from each size.Event. If nil, a new Theme is used.
+	Theme *widget.Theme
+
+	// Background fills the window before the tree is painted. If nil,
+	// white is used.
+	Background color.Color
+
+	// Event, if non-nil, is called with every event before RunWindow
+	// handles it. It returns true if the tree changed and needs a new
+	// layout and paint.
+	Event func(e interface{}) (relayout bool)
+
+	// Reloader, if non-nil, is watched for changes to its file. When
+	// the file changes, a ReloadEvent is sent to the window, and the
+	// Reloader's tree is rebuilt, laid out and painted. The Reloader
+	// is usually root or one of its descendants.
+	Reloader *Reloader
+
+	// LayoutError is called with the error from CheckLayout after
+	// every layout of the tree that has one. If it returns nil, the
+	// tree is painted as laid out; otherwise RunWindow returns the
+	// error it returned. If LayoutError is nil, the error is logged
+	// and the tree is painted.
+	LayoutError func(err error) error
+}
+
+// ReloadEvent is sent to the window by RunWindow when the file of its
+// Reloader changes. Err is the error, if any, from rebuilding the tree,
+// in which case the last good tree is still shown. WindowOptions.Event
+// sees it after the rebuild.
+type ReloadEvent struct {
+	Err error
+}
+
+// RunWindow opens a window on s showing the widget tree rooted at
+// root, and runs its event loop until the window is closed.
+//
+// The tree is measured and laid out to fill the window whenever the
+// window size or DPI changes, and painted on every paint.Event.
+func RunWindow(s screen.Screen, root *widget.Node, opts *WindowOptions) error {
+	if opts == nil {
+		opts = new(WindowOptions)
+	}
+	t := opts.Theme
+	if t == nil {
+		t = new(widget.Theme)
+	}
+	bg := opts.Background
+	if bg == nil {
+		bg = color.White
+	}
+
+	w, err := s.NewWindow(&screen.NewWindowOptions{Width: opts.Width, Height: opts.Height})
+	if err != nil {
+		return err
+	}
+	defer w.Release()
+
+	var (
+		buf   screen.Buffer
+		sz    image.Point
+		dirty bool
+	)
+	defer func() {
+		if buf != nil {
+			buf.Release()
+		}
+	}()
+
+	if r := opts.Reloader; r != nil {
+		stop := r.Watch(func() { w.Send(ReloadEvent{}) })
+		defer stop()
+	}
+
+	for {
+		e := w.NextEvent()
+		if re, ok := e.(ReloadEvent); ok && opts.Reloader != nil {
+			changed, err := opts.Reloader.Check()
+			re.Err = err
+			e = re
+			if changed {
+				dirty = true
+				w.Send(paint.Event{})
+			}
+		}
+		if opts.Event != nil && opts.Event(e) {
+			dirty = true
+			w.Send(paint.Event{})
+		}
+
+		switch e := e.(type) {
+		case lifecycle.Event:
+			if e.To == lifecycle.StageDead {
+				return nil
+			}
+
+		case sizeevent.Event:
+			if dpi := float64(e.PixelsPerPt) * 72; dpi != 0 && dpi != t.DPI {
+				t.DPI = dpi
+				dirty = true
+			}
+			if e.Size() != sz {
+				sz = e.Size()
+				if buf != nil {
+					buf.Release()
+					buf = nil
+				}
+				dirty = true
+			}
+			if dirty {
+				w.Send(paint.Event{})
+			}
+
+		case paint.Event:
+			if sz.X <= 0 || sz.Y <= 0 {
+				continue
+			}
+			if buf == nil {
+				if buf, err = s.NewBuffer(sz); err != nil {
+					return err
+				}
+			}
+			if dirty {
+				root.Class.Measure(root, t)
+				root.Rect = image.Rectangle{Max: sz}
+				root.Class.Layout(root, t)
+				dirty = false
+				if err := CheckLayout(root); err != nil {
+					if opts.LayoutError == nil {
+						log.Print(err)
+					} else if err := opts.LayoutError(err); err != nil {
+						return err
+					}
+				}
+			}
+			dst := buf.RGBA()
+			draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+			root.Class.Paint(root, t, dst, image.Point{})
+			w.Upload(image.Point{}, buf, buf.Bounds())
+			w.Publish()
+
+		case error:
+			return e
+		}
+	}
+}
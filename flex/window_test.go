@@ -0,0 +1,170 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex_test
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/crawshaw/exp/flex"
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/size"
+)
+
+// testScreen opens a testWindow. Methods that RunWindow does not call
+// are left to the nil embedded Screen, and panic.
+type testScreen struct {
+	screen.Screen
+	w *testWindow
+}
+
+func (s *testScreen) NewBuffer(sz image.Point) (screen.Buffer, error) {
+	return &testBuffer{image.NewRGBA(image.Rectangle{Max: sz})}, nil
+}
+
+func (s *testScreen) NewWindow(opts *screen.NewWindowOptions) (screen.Window, error) {
+	return s.w, nil
+}
+
+type testBuffer struct {
+	m *image.RGBA
+}
+
+func (b *testBuffer) Release()                {}
+func (b *testBuffer) Size() image.Point       { return b.m.Bounds().Size() }
+func (b *testBuffer) Bounds() image.Rectangle { return b.m.Bounds() }
+func (b *testBuffer) RGBA() *image.RGBA       { return b.m }
+
+// testWindow replays a script of events. Events sent to the window
+// are delivered before the rest of the script. As with testScreen,
+// methods that RunWindow does not call panic.
+type testWindow struct {
+	screen.Window
+	script    []interface{}
+	sent      []interface{}
+	published []*image.RGBA
+	uploaded  *image.RGBA
+}
+
+func (w *testWindow) Release() {}
+
+func (w *testWindow) NextEvent() interface{} {
+	if len(w.sent) > 0 {
+		e := w.sent[0]
+		w.sent = w.sent[1:]
+		return e
+	}
+	if len(w.script) == 0 {
+		return lifecycle.Event{To: lifecycle.StageDead}
+	}
+	e := w.script[0]
+	w.script = w.script[1:]
+	return e
+}
+
+func (w *testWindow) Send(e interface{}) { w.sent = append(w.sent, e) }
+
+func (w *testWindow) Upload(dp image.Point, src screen.Buffer, sr image.Rectangle) {
+	m := image.NewRGBA(sr)
+	draw.Draw(m, sr, src.RGBA(), sr.Min, draw.Src)
+	w.uploaded = m
+}
+
+func (w *testWindow) Publish() screen.PublishResult {
+	w.published = append(w.published, w.uploaded)
+	return screen.PublishResult{}
+}
+
+func TestRunWindow(t *testing.T) {
+	fl := flex.NewFlex()
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	c := widget.NewUniform(red, unit.Pixels(10), unit.Pixels(10)).Node
+	c.LayoutData = flex.LayoutData{Grow: 1, Align: flex.AlignItemStretch}
+	fl.AppendChild(c)
+
+	w := &testWindow{script: []interface{}{
+		lifecycle.Event{To: lifecycle.StageVisible},
+		size.Event{WidthPx: 40, HeightPx: 20, PixelsPerPt: 1},
+		size.Event{WidthPx: 60, HeightPx: 30, PixelsPerPt: 2},
+	}}
+	theme := new(widget.Theme)
+	events := 0
+	err := flex.RunWindow(&testScreen{w: w}, &fl.Node, &flex.WindowOptions{
+		Theme: theme,
+		Event: func(interface{}) bool { events++; return false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.published) != 2 {
+		t.Fatalf("published %d frames, want 2", len(w.published))
+	}
+	if got, want := w.published[0].Bounds(), image.Rect(0, 0, 40, 20); got != want {
+		t.Errorf("first frame bounds %v, want %v", got, want)
+	}
+	if got, want := c.Rect, image.Rect(0, 0, 60, 30); got != want {
+		t.Errorf("child Rect=%v, want %v", got, want)
+	}
+	if got := w.published[1].At(59, 29); got != red {
+		t.Errorf("last frame corner=%v, want %v", got, red)
+	}
+	if theme.DPI != 144 {
+		t.Errorf("theme DPI=%v, want 144", theme.DPI)
+	}
+	if events != 6 {
+		t.Errorf("Event hook called %d times, want 6", events)
+	}
+}
+
+func TestRunWindowLayoutError(t *testing.T) {
+	fl := flex.NewFlex()
+	c := widget.NewUniform(color.Black, unit.Pixels(10), unit.Pixels(10)).Node
+	c.LayoutData = flex.LayoutData{Grow: math.NaN()}
+	fl.AppendChild(c)
+	script := []interface{}{size.Event{WidthPx: 40, HeightPx: 20, PixelsPerPt: 1}}
+
+	// By default, the error is logged and the tree painted.
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	w := &testWindow{script: script}
+	if err := flex.RunWindow(&testScreen{w: w}, &fl.Node, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.published) != 1 {
+		t.Errorf("published %d frames, want 1", len(w.published))
+	}
+
+	var errs []error
+	w = &testWindow{script: script}
+	err := flex.RunWindow(&testScreen{w: w}, &fl.Node, &flex.WindowOptions{
+		LayoutError: func(err error) error {
+			errs = append(errs, err)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || len(w.published) != 1 {
+		t.Errorf("LayoutError called with %v, published %d frames; want 1 error and 1 frame", errs, len(w.published))
+	}
+
+	w = &testWindow{script: script}
+	err = flex.RunWindow(&testScreen{w: w}, &fl.Node, &flex.WindowOptions{
+		LayoutError: func(err error) error { return err },
+	})
+	if _, ok := err.(*flex.LoopError); !ok {
+		t.Errorf("RunWindow returned %v, want a *LoopError", err)
+	}
+}
@@ -0,0 +1,500 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flex
+
+import (
+	"image"
+	"testing"
+)
+
+type yogaItem struct {
+	measured image.Point // the item's width and height, or 0 if unset
+	style    string      // CSS declarations for LayoutData
+	want     image.Rectangle
+}
+
+// yogaTests are fixtures from Yoga's generated test suite
+// (https://github.com/facebook/yoga/tree/master/gentest), limited to a
+// root node with a fixed size and leaf children.
+//
+// Yoga's default flex-direction and align-content are spelled out in
+// each style. An item whose cross size is set in Yoga is not stretched,
+// which is written as align-self: flex-start where it would otherwise
+// stretch.
+var yogaTests = []struct {
+	name  string
+	style string // CSS declarations for the container
+	size  image.Point
+	items []yogaItem
+}{
+	{
+		name:  "Flex_basis_flex_grow_column",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-grow: 1; flex-basis: 50px", image.Rect(0, 0, 100, 75)},
+			{image.Pt(0, 0), "flex-grow: 1", image.Rect(0, 75, 100, 100)},
+		},
+	},
+	{
+		name:  "Flex_basis_flex_grow_row",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-grow: 1; flex-basis: 50px", image.Rect(0, 0, 75, 100)},
+			{image.Pt(0, 0), "flex-grow: 1", image.Rect(75, 0, 100, 100)},
+		},
+	},
+	{
+		name:  "Flex_basis_flex_shrink_column",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-shrink: 1; flex-basis: 100px", image.Rect(0, 0, 100, 50)},
+			{image.Pt(0, 0), "flex-basis: 50px", image.Rect(0, 50, 100, 100)},
+		},
+	},
+	{
+		name:  "Flex_basis_flex_shrink_row",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-shrink: 1; flex-basis: 100px", image.Rect(0, 0, 50, 100)},
+			{image.Pt(0, 0), "flex-basis: 50px", image.Rect(50, 0, 100, 100)},
+		},
+	},
+	{
+		name:  "Flex_basis_overrides_main_size",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 20), "flex-grow: 1; flex-basis: 50px", image.Rect(0, 0, 100, 60)},
+			{image.Pt(0, 10), "flex-grow: 1", image.Rect(0, 60, 100, 80)},
+			{image.Pt(0, 10), "flex-grow: 1", image.Rect(0, 80, 100, 100)},
+		},
+	},
+	{
+		name:  "Flex_grow_less_than_factor_one",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(200, 500),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-grow: 0.2; flex-basis: 40px", image.Rect(0, 0, 200, 132)},
+			{image.Pt(0, 0), "flex-grow: 0.2", image.Rect(0, 132, 200, 224)},
+			{image.Pt(0, 0), "flex-grow: 0.4", image.Rect(0, 224, 200, 408)},
+		},
+	},
+	{
+		name:  "Justify_content_row_flex_start",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(0, 0, 10, 102)},
+			{image.Pt(10, 0), "", image.Rect(10, 0, 20, 102)},
+			{image.Pt(10, 0), "", image.Rect(20, 0, 30, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_row_flex_end",
+		style: "flex-direction: row; justify-content: flex-end; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(72, 0, 82, 102)},
+			{image.Pt(10, 0), "", image.Rect(82, 0, 92, 102)},
+			{image.Pt(10, 0), "", image.Rect(92, 0, 102, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_row_center",
+		style: "flex-direction: row; justify-content: center; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(36, 0, 46, 102)},
+			{image.Pt(10, 0), "", image.Rect(46, 0, 56, 102)},
+			{image.Pt(10, 0), "", image.Rect(56, 0, 66, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_row_space_between",
+		style: "flex-direction: row; justify-content: space-between; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(0, 0, 10, 102)},
+			{image.Pt(10, 0), "", image.Rect(46, 0, 56, 102)},
+			{image.Pt(10, 0), "", image.Rect(92, 0, 102, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_row_space_around",
+		style: "flex-direction: row; justify-content: space-around; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(12, 0, 22, 102)},
+			{image.Pt(10, 0), "", image.Rect(46, 0, 56, 102)},
+			{image.Pt(10, 0), "", image.Rect(80, 0, 90, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_column_flex_start",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 0, 102, 10)},
+			{image.Pt(0, 0), "", image.Rect(0, 10, 102, 10)},
+			{image.Pt(0, 10), "", image.Rect(0, 10, 102, 20)},
+		},
+	},
+	{
+		name:  "Justify_content_column_flex_end",
+		style: "flex-direction: column; justify-content: flex-end; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 72, 102, 82)},
+			{image.Pt(0, 10), "", image.Rect(0, 82, 102, 92)},
+			{image.Pt(0, 10), "", image.Rect(0, 92, 102, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_column_center",
+		style: "flex-direction: column; justify-content: center; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 36, 102, 46)},
+			{image.Pt(0, 10), "", image.Rect(0, 46, 102, 56)},
+			{image.Pt(0, 10), "", image.Rect(0, 56, 102, 66)},
+		},
+	},
+	{
+		name:  "Justify_content_column_space_between",
+		style: "flex-direction: column; justify-content: space-between; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 0, 102, 10)},
+			{image.Pt(0, 10), "", image.Rect(0, 46, 102, 56)},
+			{image.Pt(0, 10), "", image.Rect(0, 92, 102, 102)},
+		},
+	},
+	{
+		name:  "Justify_content_column_space_around",
+		style: "flex-direction: column; justify-content: space-around; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(102, 102),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 12, 102, 22)},
+			{image.Pt(0, 10), "", image.Rect(0, 46, 102, 56)},
+			{image.Pt(0, 10), "", image.Rect(0, 80, 102, 90)},
+		},
+	},
+	{
+		name:  "Flex_wrap_align_stretch_fits_one_row",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 0, 100, 100)},
+		},
+	},
+	{
+		name:  "Wrap_reverse_column_fixed_size",
+		style: "flex-direction: column; flex-wrap: wrap-reverse; align-items: center; align-content: flex-start",
+		size:  image.Pt(200, 100),
+		items: []yogaItem{
+			{image.Pt(30, 10), "", image.Rect(170, 0, 200, 10)},
+			{image.Pt(30, 20), "", image.Rect(170, 10, 200, 30)},
+			{image.Pt(30, 30), "", image.Rect(170, 30, 200, 60)},
+			{image.Pt(30, 40), "", image.Rect(170, 60, 200, 100)},
+			{image.Pt(30, 50), "", image.Rect(140, 0, 170, 50)},
+		},
+	},
+	{
+		name:  "Max_width",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 10), "max-width: 50px", image.Rect(0, 0, 50, 10)},
+		},
+	},
+	{
+		name:  "Max_height",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 0), "max-height: 50px", image.Rect(0, 0, 10, 50)},
+		},
+	},
+	{
+		name:  "Min_height",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-grow: 1; min-height: 60px", image.Rect(0, 0, 100, 80)},
+			{image.Pt(0, 0), "flex-grow: 1", image.Rect(0, 80, 100, 100)},
+		},
+	},
+	{
+		name:  "Min_width",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 0), "flex-grow: 1; min-width: 60px", image.Rect(0, 0, 80, 100)},
+			{image.Pt(0, 0), "flex-grow: 1", image.Rect(80, 0, 100, 100)},
+		},
+	},
+	{
+		name:  "AlignContentFlexStart",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(130, 100),
+		items: []yogaItem{
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 0, 50, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 0, 100, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 10, 50, 20)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 10, 100, 20)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 20, 50, 30)},
+		},
+	},
+	{
+		name:  "Align_content_flex_start_without_height_on_children",
+		style: "flex-direction: column; flex-wrap: wrap; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 0, 50, 10)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 10, 50, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 10, 50, 20)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 20, 50, 20)},
+		},
+	},
+	{
+		name:  "Align_content_flex_end",
+		style: "flex-direction: column; flex-wrap: wrap; align-items: stretch; align-content: flex-end",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 0, 50, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 10, 50, 20)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 20, 50, 30)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 30, 50, 40)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 40, 50, 50)},
+		},
+	},
+	{
+		name:  "Align_content_stretch",
+		style: "flex-direction: column; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+			{image.Pt(50, 0), "align-self: flex-start", image.Rect(0, 0, 50, 0)},
+		},
+	},
+	{
+		name:  "Align_content_spacebetween",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: space-between",
+		size:  image.Pt(130, 100),
+		items: []yogaItem{
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 0, 50, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 0, 100, 10)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 45, 50, 55)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 45, 100, 55)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 90, 50, 100)},
+		},
+	},
+	{
+		name:  "Align_content_spacearound",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: space-around",
+		size:  image.Pt(140, 120),
+		items: []yogaItem{
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 15, 50, 25)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 15, 100, 25)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 55, 50, 65)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(50, 55, 100, 65)},
+			{image.Pt(50, 10), "align-self: flex-start", image.Rect(0, 95, 50, 105)},
+		},
+	},
+	{
+		name:  "Align_content_stretch_row",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 50)},
+			{image.Pt(50, 0), "", image.Rect(50, 0, 100, 50)},
+			{image.Pt(50, 0), "", image.Rect(100, 0, 150, 50)},
+			{image.Pt(50, 0), "", image.Rect(0, 50, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 50, 100, 100)},
+		},
+	},
+	{
+		name:  "Align_content_stretch_row_with_single_row",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 0, 100, 100)},
+		},
+	},
+	{
+		name:  "Align_content_stretch_row_with_fixed_height",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 80)},
+			{image.Pt(50, 60), "align-self: flex-start", image.Rect(50, 0, 100, 60)},
+			{image.Pt(50, 0), "", image.Rect(100, 0, 150, 80)},
+			{image.Pt(50, 0), "", image.Rect(0, 80, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 80, 100, 100)},
+		},
+	},
+	{
+		name:  "Align_content_stretch_row_with_max_height",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 50)},
+			{image.Pt(50, 0), "max-height: 20px", image.Rect(50, 0, 100, 20)},
+			{image.Pt(50, 0), "", image.Rect(100, 0, 150, 50)},
+			{image.Pt(50, 0), "", image.Rect(0, 50, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 50, 100, 100)},
+		},
+	},
+	{
+		name:  "Align_content_stretch_row_with_min_height",
+		style: "flex-direction: row; flex-wrap: wrap; align-items: stretch; align-content: stretch",
+		size:  image.Pt(150, 100),
+		items: []yogaItem{
+			{image.Pt(50, 0), "", image.Rect(0, 0, 50, 90)},
+			{image.Pt(50, 0), "min-height: 80px", image.Rect(50, 0, 100, 90)},
+			{image.Pt(50, 0), "", image.Rect(100, 0, 150, 90)},
+			{image.Pt(50, 0), "", image.Rect(0, 90, 50, 100)},
+			{image.Pt(50, 0), "", image.Rect(50, 90, 100, 100)},
+		},
+	},
+	{
+		name:  "Align_items_stretch",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 0, 100, 10)},
+		},
+	},
+	{
+		name:  "Align_items_center",
+		style: "flex-direction: column; align-items: center; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "", image.Rect(45, 0, 55, 10)},
+		},
+	},
+	{
+		name:  "Align_items_flex_start",
+		style: "flex-direction: column; align-items: flex-start; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "", image.Rect(0, 0, 10, 10)},
+		},
+	},
+	{
+		name:  "Align_items_flex_end",
+		style: "flex-direction: column; align-items: flex-end; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "", image.Rect(90, 0, 100, 10)},
+		},
+	},
+	{
+		name:  "Align_self_center",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "align-self: center", image.Rect(45, 0, 55, 10)},
+		},
+	},
+	{
+		name:  "Align_self_flex_end",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "align-self: flex-end", image.Rect(90, 0, 100, 10)},
+		},
+	},
+	{
+		name:  "Align_self_flex_start",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "align-self: flex-start", image.Rect(0, 0, 10, 10)},
+		},
+	},
+	{
+		name:  "Align_self_flex_end_override_flex_start",
+		style: "flex-direction: column; align-items: flex-start; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 10), "align-self: flex-end", image.Rect(90, 0, 100, 10)},
+		},
+	},
+	{
+		name:  "Flex_direction_column",
+		style: "flex-direction: column; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 0, 100, 10)},
+			{image.Pt(0, 10), "", image.Rect(0, 10, 100, 20)},
+			{image.Pt(0, 10), "", image.Rect(0, 20, 100, 30)},
+		},
+	},
+	{
+		name:  "Flex_direction_row",
+		style: "flex-direction: row; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(0, 0, 10, 100)},
+			{image.Pt(10, 0), "", image.Rect(10, 0, 20, 100)},
+			{image.Pt(10, 0), "", image.Rect(20, 0, 30, 100)},
+		},
+	},
+	{
+		name:  "Flex_direction_column_reverse",
+		style: "flex-direction: column-reverse; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(0, 10), "", image.Rect(0, 90, 100, 100)},
+			{image.Pt(0, 10), "", image.Rect(0, 80, 100, 90)},
+			{image.Pt(0, 10), "", image.Rect(0, 70, 100, 80)},
+		},
+	},
+	{
+		name:  "Flex_direction_row_reverse",
+		style: "flex-direction: row-reverse; align-items: stretch; align-content: flex-start",
+		size:  image.Pt(100, 100),
+		items: []yogaItem{
+			{image.Pt(10, 0), "", image.Rect(90, 0, 100, 100)},
+			{image.Pt(10, 0), "", image.Rect(80, 0, 90, 100)},
+			{image.Pt(10, 0), "", image.Rect(70, 0, 80, 100)},
+		},
+	},
+}
+
+func TestYoga(t *testing.T) {
+	for _, test := range yogaTests {
+		fl, err := ParseStyle(test.style)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		fl.Compat = CompatYoga
+		var items []Item
+		for _, it := range test.items {
+			d, err := ParseItemStyle(it.style)
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			items = append(items, Item{MeasuredSize: it.measured, LayoutData: d})
+		}
+		rects := fl.Solve(test.size, items)
+		for i, it := range test.items {
+			if rects[i] != it.want {
+				t.Errorf("%s: [%d]=%v, want %v", test.name, i, rects[i], it.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build yoga
+// +build yoga
+
+// This file compares the speed of the flex algorithm with that of Yoga,
+// through the pure Go port at github.com/kjk/flex. It needs that
+// package, so it is built only with the yoga tag:
+//
+//	go get github.com/kjk/flex
+//	go test -tags yoga -run Yoga -bench Yoga -v
+//
+// The benchmarks run the same scenarios through Solve and through
+// Yoga's CalculateLayout, so they can be compared with benchstat.
+// TestYogaRelative prints the ratios directly.
+
+package flex
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	yoga "github.com/kjk/flex"
+)
+
+type benchItem struct {
+	size           image.Point
+	grow, shrink   float64
+	basis          int // definite if positive
+	alignSelfStart bool
+}
+
+type benchScenario struct {
+	name string
+	dir  Direction
+	wrap FlexWrap
+	size image.Point
+	item func(i int) benchItem
+	n    int
+}
+
+var benchScenarios = []benchScenario{
+	{
+		name: "row10",
+		dir:  Row,
+		size: image.Pt(1000, 100),
+		n:    10,
+		item: func(i int) benchItem {
+			return benchItem{size: image.Pt(40+i, 20), grow: 1, shrink: 1}
+		},
+	},
+	{
+		name: "column100shrink",
+		dir:  Column,
+		size: image.Pt(300, 2000),
+		n:    100,
+		item: func(i int) benchItem {
+			return benchItem{size: image.Pt(300, 30), shrink: float64(1 + i%3), basis: 25 + i%10}
+		},
+	},
+	{
+		name: "wrap1000",
+		dir:  Row,
+		wrap: Wrap,
+		size: image.Pt(1280, 4000),
+		n:    1000,
+		item: func(i int) benchItem {
+			return benchItem{size: image.Pt(60+i%40, 20+i%7), grow: float64(i % 2), alignSelfStart: i%5 == 0}
+		},
+	},
+}
+
+// flexBench returns a function that lays out s once, alternating
+// between two container widths so that no result can be reused.
+func (s benchScenario) flexBench() func(i int) {
+	fl := &Flex{Direction: s.dir, Wrap: s.wrap, AlignContent: AlignContentStart, Compat: CompatYoga}
+	items := make([]Item, s.n)
+	for i := range items {
+		it := s.item(i)
+		shrink := it.shrink
+		d := LayoutData{Grow: it.grow, Shrink: &shrink}
+		if it.basis > 0 {
+			d.Basis, d.BasisPx = Definite, it.basis
+		}
+		if it.alignSelfStart {
+			d.Align = AlignItemStart
+		}
+		items[i] = Item{MeasuredSize: it.size, LayoutData: d}
+	}
+	return func(i int) {
+		size := s.size
+		size.X += i % 2
+		fl.Solve(size, items)
+	}
+}
+
+func (s benchScenario) yogaBench() func(i int) {
+	config := yoga.NewConfig()
+	root := yoga.NewNodeWithConfig(config)
+	if s.dir == Row {
+		root.StyleSetFlexDirection(yoga.FlexDirectionRow)
+	}
+	if s.wrap == Wrap {
+		root.StyleSetFlexWrap(yoga.WrapWrap)
+	}
+	root.StyleSetHeight(float32(s.size.Y))
+	for i := 0; i < s.n; i++ {
+		it := s.item(i)
+		c := yoga.NewNodeWithConfig(config)
+		c.StyleSetWidth(float32(it.size.X))
+		c.StyleSetHeight(float32(it.size.Y))
+		c.StyleSetFlexGrow(float32(it.grow))
+		c.StyleSetFlexShrink(float32(it.shrink))
+		if it.basis > 0 {
+			c.StyleSetFlexBasis(float32(it.basis))
+		}
+		if it.alignSelfStart {
+			c.StyleSetAlignSelf(yoga.AlignFlexStart)
+		}
+		root.InsertChild(c, i)
+	}
+	return func(i int) {
+		root.StyleSetWidth(float32(s.size.X + i%2))
+		yoga.CalculateLayout(root, yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	}
+}
+
+func runBench(b *testing.B, layout func(int)) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		layout(i)
+	}
+}
+
+func BenchmarkYoga(b *testing.B) {
+	for _, s := range benchScenarios {
+		s := s
+		b.Run(s.name+"/flex", func(b *testing.B) { runBench(b, s.flexBench()) })
+		b.Run(s.name+"/yoga", func(b *testing.B) { runBench(b, s.yogaBench()) })
+	}
+}
+
+// TestYogaRelative reports, for each scenario, the time and allocations
+// of this package relative to Yoga. Ratios below 1 favor this package.
+func TestYogaRelative(t *testing.T) {
+	if testing.Short() {
+		t.Skip("benchmarks in short mode")
+	}
+	for _, s := range benchScenarios {
+		f := testing.Benchmark(func(b *testing.B) { runBench(b, s.flexBench()) })
+		y := testing.Benchmark(func(b *testing.B) { runBench(b, s.yogaBench()) })
+		t.Logf("%-16s time %s  allocs %s  (flex %d ns/op %d allocs/op, yoga %d ns/op %d allocs/op)",
+			s.name,
+			ratio(f.NsPerOp(), y.NsPerOp()), ratio(f.AllocsPerOp(), y.AllocsPerOp()),
+			f.NsPerOp(), f.AllocsPerOp(), y.NsPerOp(), y.AllocsPerOp())
+	}
+}
+
+func ratio(a, b int64) string {
+	if b == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2fx", float64(a)/float64(b))
+}